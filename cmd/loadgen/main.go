@@ -0,0 +1,500 @@
+// Command loadgen runs simulated clients against a chat server and reports
+// delivery latency, dropped connections and ERROR rates as JSON.
+//
+//	loadgen [-url ws://localhost:8080/ws] [-clients 50] [-rooms 2] [-room-pool 10]
+//	        [-rate 0.5] [-posts 1] [-comment-rate 0] [-duration 1m] [-out report.json]
+//
+// Each client connects (spread over -ramp), joins -rooms rooms from a pool of
+// -room-pool load-N rooms, subscribes to -posts of the server's posts, then
+// sends chat messages at -rate and comments at -comment-rate per second for
+// -duration after the ramp. Every message and comment carries its send time,
+// so each receiving client records the send-to-broadcast latency. After -drain
+// the report is written to -out, or stdout; progress goes to stderr.
+//
+// Messages and comments are stored like any others, so point loadgen at a
+// --demo or staging server. The server rate-limits chat to 3 messages per
+// second per connection; higher rates show up as RATE_LIMITED errors.
+//
+// -max-p99, -max-error-rate and -min-delivery turn the run into a check: the
+// report lists what failed and loadgen exits with status 1.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"websocket/pkg/client"
+	"websocket/pkg/protocol"
+)
+
+// payloadPrefix marks loadgen traffic; the send time in nanoseconds follows
+const payloadPrefix = "loadgen"
+
+// Config is the run's configuration, repeated in the report
+type Config struct {
+	URL            string  `json:"url"`
+	Clients        int     `json:"clients"`
+	RoomsPerClient int     `json:"rooms_per_client"`
+	RoomPool       int     `json:"room_pool"`
+	PostsPerClient int     `json:"posts_per_client"`
+	MessageRate    float64 `json:"message_rate"` // Per client per second
+	CommentRate    float64 `json:"comment_rate"` // Per client per second
+	Duration       string  `json:"duration"`
+	Ramp           string  `json:"ramp"`
+	Drain          string  `json:"drain"`
+}
+
+// Report is the JSON result of a run
+type Report struct {
+	Config      Config           `json:"config"`
+	StartedAt   time.Time        `json:"started_at"`
+	Elapsed     float64          `json:"elapsed_seconds"`
+	Connections ConnectionReport `json:"connections"`
+	Messages    FlowReport       `json:"messages"`
+	Comments    FlowReport       `json:"comments"`
+	Errors      ErrorReport      `json:"errors"`
+	SetupMean   float64          `json:"setup_mean_ms"` // Mean time to connect, join and subscribe
+	Failed      []string         `json:"failed_checks,omitempty"`
+}
+
+// ConnectionReport counts connection outcomes
+type ConnectionReport struct {
+	Attempted   int64 `json:"attempted"`
+	Established int64 `json:"established"`
+	Failed      int64 `json:"failed"`      // Never connected
+	Dropped     int64 `json:"dropped"`     // Lost after connecting
+	Reconnected int64 `json:"reconnected"` // Recovered by the client's backoff
+	SetupFailed int64 `json:"setup_failed"`
+}
+
+// FlowReport covers one kind of traffic: chat messages or comments
+type FlowReport struct {
+	Sent          int64          `json:"sent"`
+	SendFailed    int64          `json:"send_failed"`
+	Expected      int64          `json:"expected_deliveries"` // Sends times subscribers at send time
+	Delivered     int64          `json:"delivered"`
+	DeliveryRatio float64        `json:"delivery_ratio"`
+	Latency       LatencySummary `json:"latency"`
+}
+
+// ErrorReport covers ERROR events received by all clients
+type ErrorReport struct {
+	Total    int64            `json:"total"`
+	Rate     float64          `json:"rate"` // Errors per event sent
+	ByCode   map[string]int64 `json:"by_code"`
+	Examples []string         `json:"examples,omitempty"`
+}
+
+// flow tracks one kind of traffic while the run is going
+type flow struct {
+	sent, sendFailed, expected, delivered int64
+	latency                               *latencies
+}
+
+func (f *flow) report() FlowReport {
+	report := FlowReport{
+		Sent:       atomic.LoadInt64(&f.sent),
+		SendFailed: atomic.LoadInt64(&f.sendFailed),
+		Expected:   atomic.LoadInt64(&f.expected),
+		Delivered:  atomic.LoadInt64(&f.delivered),
+		Latency:    f.latency.summary(),
+	}
+	if report.Expected > 0 {
+		report.DeliveryRatio = round(float64(report.Delivered) / float64(report.Expected))
+	}
+	return report
+}
+
+// run holds the shared state of all simulated clients
+type run struct {
+	config   Config
+	duration time.Duration
+	ramp     time.Duration
+	prefix   string
+
+	rooms       []string
+	posts       []string
+	roomMembers map[string]*int64 // Clients in each room, for expected deliveries
+	postMembers map[string]*int64
+
+	attempted, established, failed, dropped, reconnected, live, setupFailed int64
+	setupNanos                                                              int64
+
+	messages flow
+	comments flow
+	errors   *errorCounts
+
+	stop chan struct{} // Closed when sending should end
+	done chan struct{} // Closed when clients should disconnect
+}
+
+func main() {
+	rawURL := flag.String("url", "ws://localhost:8080/ws", "server WebSocket endpoint")
+	clients := flag.Int("clients", 50, "simulated clients")
+	rooms := flag.Int("rooms", 2, "rooms each client joins")
+	roomPool := flag.Int("room-pool", 10, "distinct rooms (load-0 ... load-N-1)")
+	posts := flag.Int("posts", 1, "posts each client subscribes to")
+	rate := flag.Float64("rate", 0.5, "chat messages per client per second")
+	commentRate := flag.Float64("comment-rate", 0, "comments per client per second")
+	duration := flag.Duration("duration", time.Minute, "how long to send")
+	ramp := flag.Duration("ramp", 5*time.Second, "spread client connects over this long")
+	drain := flag.Duration("drain", 3*time.Second, "wait for deliveries after sending stops")
+	interval := flag.Duration("interval", 5*time.Second, "progress line interval")
+	prefix := flag.String("user-prefix", "load", "username prefix; clients are PREFIX-N")
+	out := flag.String("out", "", "report file; stdout when empty")
+	maxP99 := flag.Float64("max-p99", 0, "fail when message p99 latency exceeds this many ms")
+	maxErrorRate := flag.Float64("max-error-rate", 0, "fail when errors per event sent exceed this")
+	minDelivery := flag.Float64("min-delivery", 0, "fail when the message delivery ratio is below this")
+	flag.Parse()
+
+	if *clients < 1 || *rooms < 0 || *roomPool < 1 || *rooms > *roomPool || *posts < 0 || *rate < 0 || *commentRate < 0 {
+		log.Fatal("invalid flags: need clients >= 1, 0 <= rooms <= room-pool, and non-negative posts and rates")
+	}
+	if *rate > 3 {
+		log.Printf("⚠️ -rate %.1f is above the server's chat limit of 3/s per connection; expect RATE_LIMITED errors", *rate)
+	}
+
+	r := &run{
+		config: Config{
+			URL:            *rawURL,
+			Clients:        *clients,
+			RoomsPerClient: *rooms,
+			RoomPool:       *roomPool,
+			PostsPerClient: *posts,
+			MessageRate:    *rate,
+			CommentRate:    *commentRate,
+			Duration:       duration.String(),
+			Ramp:           ramp.String(),
+			Drain:          drain.String(),
+		},
+		duration:    *duration,
+		ramp:        *ramp,
+		prefix:      *prefix,
+		roomMembers: make(map[string]*int64),
+		postMembers: make(map[string]*int64),
+		messages:    flow{latency: newLatencies()},
+		comments:    flow{latency: newLatencies()},
+		errors:      newErrorCounts(),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	for i := 0; i < *roomPool; i++ {
+		room := fmt.Sprintf("load-%d", i)
+		r.rooms = append(r.rooms, room)
+		r.roomMembers[room] = new(int64)
+	}
+	if *posts > 0 || *commentRate > 0 {
+		postIDs, err := fetchPostIDs(*rawURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(postIDs) == 0 {
+			log.Fatal("the server has no posts to subscribe to; use -posts 0 -comment-rate 0")
+		}
+		r.posts = postIDs
+		for _, postID := range postIDs {
+			r.postMembers[postID] = new(int64)
+		}
+		if *commentRate > 0 && *posts == 0 {
+			r.config.PostsPerClient = 1 // Commenters need a post to comment on
+		}
+	}
+
+	log.Printf("🚀 %d clients against %s for %s", *clients, *rawURL, *duration)
+	startedAt := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < *clients; i++ {
+		wg.Add(1)
+		go r.simulate(i, &wg)
+	}
+
+	progress := time.NewTicker(*interval)
+	sendEnd := time.After(*ramp + *duration)
+	var lastCount int64
+	var lastSum float64
+	for running := true; running; {
+		select {
+		case <-progress.C:
+			lastCount, lastSum = r.progress(time.Since(startedAt), lastCount, lastSum)
+		case <-sendEnd:
+			running = false
+		}
+	}
+	progress.Stop()
+
+	close(r.stop)
+	time.Sleep(*drain)
+	close(r.done)
+	wg.Wait()
+
+	report := r.report(startedAt, *maxP99, *maxErrorRate, *minDelivery)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		fmt.Println(string(data))
+	} else if err := os.WriteFile(*out, append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	} else {
+		log.Printf("📄 Report written to %s", *out)
+	}
+
+	if len(report.Failed) > 0 {
+		log.Printf("❌ Failed checks: %s", strings.Join(report.Failed, "; "))
+		os.Exit(1)
+	}
+}
+
+// simulate runs client i: connect, join, subscribe, then send until stopped
+func (r *run) simulate(i int, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	// Spread connects over the ramp so the server isn't hit all at once
+	select {
+	case <-time.After(r.ramp * time.Duration(i) / time.Duration(r.config.Clients)):
+	case <-r.stop:
+		return
+	}
+
+	atomic.AddInt64(&r.attempted, 1)
+	setupStart := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := client.Connect(ctx, r.config.URL, client.Options{Username: fmt.Sprintf("%s-%d", r.prefix, i)})
+	if err != nil {
+		atomic.AddInt64(&r.failed, 1)
+		log.Printf("❌ Client %d failed to connect: %v", i, err)
+		return
+	}
+	defer c.Close()
+
+	atomic.AddInt64(&r.established, 1)
+	atomic.AddInt64(&r.live, 1)
+
+	c.OnChatMessage(func(e *protocol.ChatMessageEvent) { r.received(&r.messages, e.Message) })
+	c.OnPostComment(func(e *protocol.PostCommentEvent) { r.received(&r.comments, e.Comment) })
+	c.OnError(func(e *protocol.ErrorEvent) { r.errors.add(e.Code, e.Message) })
+	c.OnDisconnect(func(error) {
+		atomic.AddInt64(&r.dropped, 1)
+		atomic.AddInt64(&r.live, -1)
+	})
+	c.OnReconnect(func() {
+		atomic.AddInt64(&r.reconnected, 1)
+		atomic.AddInt64(&r.live, 1)
+	})
+
+	// Neighbouring clients share rooms and posts, so every room has members
+	var myRooms, myPosts []string
+	for k := 0; k < r.config.RoomsPerClient; k++ {
+		room := r.rooms[(i+k)%len(r.rooms)]
+		if _, err := c.JoinRoom(ctx, room); err != nil {
+			atomic.AddInt64(&r.setupFailed, 1)
+			continue
+		}
+		atomic.AddInt64(r.roomMembers[room], 1)
+		myRooms = append(myRooms, room)
+	}
+	for k := 0; k < r.config.PostsPerClient && k < len(r.posts); k++ {
+		postID := r.posts[(i+k)%len(r.posts)]
+		if _, err := c.SubscribePost(ctx, postID, 0); err != nil {
+			atomic.AddInt64(&r.setupFailed, 1)
+			continue
+		}
+		atomic.AddInt64(r.postMembers[postID], 1)
+		myPosts = append(myPosts, postID)
+	}
+	atomic.AddInt64(&r.setupNanos, int64(time.Since(setupStart)))
+
+	var wait sync.WaitGroup
+	if r.config.MessageRate > 0 && len(myRooms) > 0 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			r.send(r.config.MessageRate, myRooms, func(room, payload string) {
+				event := &protocol.ChatMessageEvent{Type: protocol.EventChatMessage, Room: room, Message: payload}
+				r.sent(&r.messages, c.Send(event), r.roomMembers[room])
+			})
+		}()
+	}
+	if r.config.CommentRate > 0 && len(myPosts) > 0 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			r.send(r.config.CommentRate, myPosts, func(postID, payload string) {
+				event := &protocol.PostCommentEvent{Type: protocol.EventPostComment, PostID: postID, Comment: payload}
+				r.sent(&r.comments, c.Send(event), r.postMembers[postID])
+			})
+		}()
+	}
+	wait.Wait()
+
+	<-r.done
+}
+
+// send calls fn at rate per second, cycling through targets, until the run stops
+func (r *run) send(rate float64, targets []string, fn func(target, payload string)) {
+	interval := time.Duration(float64(time.Second) / rate)
+
+	// A random phase keeps clients from sending in lockstep
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(interval)))):
+	case <-r.stop:
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for n := 0; ; n++ {
+		fn(targets[n%len(targets)], payloadPrefix+" "+strconv.FormatInt(time.Now().UnixNano(), 10))
+
+		select {
+		case <-ticker.C:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// sent records a send; each current member of the target should receive it
+func (r *run) sent(f *flow, err error, members *int64) {
+	if err != nil {
+		atomic.AddInt64(&f.sendFailed, 1)
+		return
+	}
+	atomic.AddInt64(&f.sent, 1)
+	atomic.AddInt64(&f.expected, atomic.LoadInt64(members))
+}
+
+// received records the latency of a loadgen payload; other traffic is ignored
+func (r *run) received(f *flow, payload string) {
+	fields := strings.Fields(payload)
+	if len(fields) != 2 || fields[0] != payloadPrefix {
+		return
+	}
+	sentAt, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return
+	}
+	atomic.AddInt64(&f.delivered, 1)
+	f.latency.add(time.Since(time.Unix(0, sentAt)))
+}
+
+// progress logs a status line and returns the latency totals for the next one
+func (r *run) progress(elapsed time.Duration, lastCount int64, lastSum float64) (int64, float64) {
+	count, sum := r.messages.latency.totals()
+	mean := 0.0
+	if count > lastCount {
+		mean = (sum - lastSum) / float64(count-lastCount)
+	}
+	_, _, errors := r.errors.snapshot()
+
+	log.Printf("⏱️ %s: %d/%d live, %d messages sent, %d/%d delivered, %d errors, %.2fms mean latency",
+		elapsed.Round(time.Second),
+		atomic.LoadInt64(&r.live), r.config.Clients,
+		atomic.LoadInt64(&r.messages.sent),
+		atomic.LoadInt64(&r.messages.delivered), atomic.LoadInt64(&r.messages.expected),
+		errors, mean)
+	return count, sum
+}
+
+func (r *run) report(startedAt time.Time, maxP99, maxErrorRate, minDelivery float64) *Report {
+	byCode, examples, total := r.errors.snapshot()
+
+	report := &Report{
+		Config:    r.config,
+		StartedAt: startedAt,
+		Elapsed:   round(time.Since(startedAt).Seconds()),
+		Connections: ConnectionReport{
+			Attempted:   atomic.LoadInt64(&r.attempted),
+			Established: atomic.LoadInt64(&r.established),
+			Failed:      atomic.LoadInt64(&r.failed),
+			Dropped:     atomic.LoadInt64(&r.dropped),
+			Reconnected: atomic.LoadInt64(&r.reconnected),
+			SetupFailed: atomic.LoadInt64(&r.setupFailed),
+		},
+		Messages: r.messages.report(),
+		Comments: r.comments.report(),
+		Errors:   ErrorReport{Total: total, ByCode: byCode, Examples: examples},
+	}
+
+	if sent := report.Messages.Sent + report.Comments.Sent; sent > 0 {
+		report.Errors.Rate = round(float64(total) / float64(sent))
+	}
+	if report.Connections.Established > 0 {
+		mean := float64(atomic.LoadInt64(&r.setupNanos)) / float64(report.Connections.Established)
+		report.SetupMean = round(mean / float64(time.Millisecond))
+	}
+
+	if maxP99 > 0 && report.Messages.Latency.P99 > maxP99 {
+		report.Failed = append(report.Failed, fmt.Sprintf("message p99 %.2fms > %.2fms", report.Messages.Latency.P99, maxP99))
+	}
+	if maxErrorRate > 0 && report.Errors.Rate > maxErrorRate {
+		report.Failed = append(report.Failed, fmt.Sprintf("error rate %.4f > %.4f", report.Errors.Rate, maxErrorRate))
+	}
+	if minDelivery > 0 && report.Messages.DeliveryRatio < minDelivery {
+		report.Failed = append(report.Failed, fmt.Sprintf("delivery ratio %.4f < %.4f", report.Messages.DeliveryRatio, minDelivery))
+	}
+	if report.Connections.Failed > 0 && (maxP99 > 0 || maxErrorRate > 0 || minDelivery > 0) {
+		report.Failed = append(report.Failed, fmt.Sprintf("%d clients failed to connect", report.Connections.Failed))
+	}
+	return report
+}
+
+// fetchPostIDs lists posts through the REST API next to the WebSocket endpoint
+func fetchPostIDs(wsURL string) ([]string, error) {
+	target, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %v", err)
+	}
+	switch target.Scheme {
+	case "wss":
+		target.Scheme = "https"
+	default:
+		target.Scheme = "http"
+	}
+	target.Path = "/api/v1/posts"
+	target.RawQuery = "limit=50"
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Get(target.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list posts: HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Posts []struct {
+			ID string `json:"id"`
+		} `json:"posts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode posts: %v", err)
+	}
+
+	var ids []string
+	for _, post := range body.Posts {
+		ids = append(ids, post.ID)
+	}
+	return ids, nil
+}
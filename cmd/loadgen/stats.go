@@ -0,0 +1,144 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds memory on long soaks; past it the kept samples are
+// a uniform random subset (reservoir sampling), so percentiles stay unbiased
+const maxLatencySamples = 1_000_000
+
+// LatencySummary describes send-to-receipt latencies in milliseconds
+type LatencySummary struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean_ms"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+// latencies records delivery latencies from every receiving client
+type latencies struct {
+	mutex   sync.Mutex
+	samples []float64
+	count   int64
+	sum     float64
+	max     float64
+	rng     *rand.Rand
+}
+
+func newLatencies() *latencies {
+	return &latencies{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (l *latencies) add(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.count++
+	l.sum += ms
+	if ms > l.max {
+		l.max = ms
+	}
+	if len(l.samples) < maxLatencySamples {
+		l.samples = append(l.samples, ms)
+	} else if i := l.rng.Int63n(l.count); i < maxLatencySamples {
+		l.samples[i] = ms
+	}
+}
+
+// totals returns the count and sum so far, for progress lines
+func (l *latencies) totals() (int64, float64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.count, l.sum
+}
+
+func (l *latencies) summary() LatencySummary {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.count == 0 {
+		return LatencySummary{}
+	}
+
+	sorted := append([]float64(nil), l.samples...)
+	sort.Float64s(sorted)
+
+	return LatencySummary{
+		Count: l.count,
+		Mean:  round(l.sum / float64(l.count)),
+		P50:   round(percentile(sorted, 50)),
+		P90:   round(percentile(sorted, 90)),
+		P95:   round(percentile(sorted, 95)),
+		P99:   round(percentile(sorted, 99)),
+		Max:   round(l.max),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted samples
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func round(ms float64) float64 {
+	return math.Round(ms*1000) / 1000
+}
+
+// errorCounts tallies ERROR events by code, keeping a few example messages
+type errorCounts struct {
+	mutex    sync.Mutex
+	byCode   map[string]int64
+	examples []string
+}
+
+// maxErrorExamples caps the distinct ERROR messages kept for the report
+const maxErrorExamples = 10
+
+func newErrorCounts() *errorCounts {
+	return &errorCounts{byCode: make(map[string]int64)}
+}
+
+func (e *errorCounts) add(code, message string) {
+	if code == "" {
+		code = "UNCODED"
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.byCode[code]++
+	if len(e.examples) < maxErrorExamples {
+		for _, example := range e.examples {
+			if example == message {
+				return
+			}
+		}
+		e.examples = append(e.examples, message)
+	}
+}
+
+func (e *errorCounts) snapshot() (map[string]int64, []string, int64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	byCode := make(map[string]int64, len(e.byCode))
+	var total int64
+	for code, n := range e.byCode {
+		byCode[code] = n
+		total += n
+	}
+	return byCode, append([]string{}, e.examples...), total
+}
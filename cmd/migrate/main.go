@@ -0,0 +1,123 @@
+// Command migrate applies and inspects the versioned database migrations.
+//
+//	migrate [-db path] up [n]      apply pending migrations (all, or the next n)
+//	migrate [-db path] down [n]    roll back the last n applied migrations (default 1)
+//	migrate [-db path] status      list migrations and whether they are applied
+//	migrate [-dir path] create name    add empty up/down scripts for a new migration
+//
+// The database defaults to DB_PATH, or ./chat.db. The server applies pending
+// migrations itself on startup; this tool is for rolling out, checking and
+// undoing them by hand.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"websocket/pkg/database"
+)
+
+func main() {
+	dbPath := flag.String("db", database.Path(), "SQLite database file")
+	dir := flag.String("dir", "pkg/database/migrations", "migrations source directory (for create)")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal("usage: migrate create <name>")
+		}
+		upPath, downPath, err := database.CreateMigration(*dir, args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Created %s\nCreated %s\nRebuild to embed the new migration.\n", upPath, downPath)
+		return
+	}
+
+	db, err := database.Open(*dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		migrations, err := db.MigrateUp(steps(args, 0))
+		report("Applied", migrations, err)
+	case "down":
+		migrations, err := db.MigrateDown(steps(args, 1))
+		report("Rolled back", migrations, err)
+	case "status":
+		status(db)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate [-db path] up [n] | down [n] | status")
+	fmt.Fprintln(os.Stderr, "       migrate [-dir path] create <name>")
+	flag.PrintDefaults()
+}
+
+// steps parses the optional count after up/down
+func steps(args []string, fallback int) int {
+	if len(args) < 2 {
+		return fallback
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 {
+		log.Fatalf("invalid step count %q", args[1])
+	}
+	return n
+}
+
+// report lists what an up or down run did before it finished or failed
+func report(verb string, migrations []*database.Migration, err error) {
+	for _, migration := range migrations {
+		fmt.Printf("%s %04d_%s\n", verb, migration.Version, migration.Name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("Nothing to do")
+	}
+}
+
+func status(db *database.DB) {
+	statuses, err := db.MigrationStatus()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.ChecksumMismatch:
+			state = "CHANGED since applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		case status.Applied:
+			state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		default:
+			pending++
+		}
+		rollback := ""
+		if status.Down == "" {
+			rollback = " (no down script)"
+		}
+		fmt.Printf("%04d_%-40s %s%s\n", status.Version, status.Name, state, rollback)
+	}
+	fmt.Printf("%d migrations, %d pending\n", len(statuses), pending)
+}
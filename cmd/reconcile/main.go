@@ -0,0 +1,47 @@
+// Command reconcile recomputes each post's comment count from the comments table.
+//
+//	reconcile [-db path] [-dry-run]
+//
+// The database defaults to DB_PATH, or ./chat.db. Counts are kept in step as
+// comments are written; run this after importing data or to repair counts
+// that drifted before comment writes became transactional.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"websocket/internal/repository"
+	"websocket/pkg/database"
+)
+
+func main() {
+	dbPath := flag.String("db", database.Path(), "SQLite database file")
+	dryRun := flag.Bool("dry-run", false, "report drifted counts without fixing them")
+	flag.Parse()
+
+	db, err := database.Open(*dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	drifts, orphaned, err := repository.ReconcileCommentCounts(db, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, drift := range drifts {
+		fmt.Printf("%-40s %d -> %d\n", drift.PostID, drift.Stored, drift.Actual)
+	}
+
+	verb := "Fixed"
+	if *dryRun {
+		verb = "Would fix"
+	}
+	fmt.Printf("%s %d post comment counts\n", verb, len(drifts))
+	if orphaned > 0 {
+		fmt.Printf("%d comments belong to posts that no longer exist\n", orphaned)
+	}
+}
@@ -1,45 +1,114 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
 	"os"
 
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
 	"websocket/internal/handlers"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
+	"websocket/internal/retention"
 	"websocket/internal/websocket"
+	"websocket/pkg/blobstore"
 	"websocket/pkg/database"
 )
 
 func main() {
+	demo := flag.Bool("demo", false, "keep all data in memory and discard it on exit")
+	flag.Parse()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	// Initialize database
-	db, err := database.NewDatabase()
+	// Initialize database (in memory for --demo, so nothing touches disk)
+	var db *database.DB
+	var err error
+	if *demo {
+		db, err = database.NewMemoryDatabase()
+	} else {
+		db, err = database.NewDatabase()
+	}
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer db.Close()
 
-	// Initialize repositories
-	messageRepo := repository.NewMessageRepository(db)
-	postRepo := repository.NewPostRepository(db)
-	commentRepo := repository.NewCommentRepository(db)
+	// Initialize repositories. Demo mode uses the same ones on the in-memory
+	// database, so search, unread counts, mentions and reactions cover demo data.
+	messageRepository := repository.NewMessageRepository(db)
+	var messageRepo repository.MessageStore = messageRepository
+	var postRepo repository.PostStore = repository.NewPostRepository(db)
+	var commentRepo repository.CommentStore = repository.NewCommentRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	readReceiptRepo := repository.NewReadReceiptRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
+	roomModerationRepo := repository.NewRoomModerationRepository(db)
+
+	// Initialize attachment storage (local disk unless BLOB_STORE=s3)
+	var blobStore blobstore.Store = blobstore.NewMemoryStore()
+	if !*demo {
+		if blobStore, err = blobstore.NewFromEnv(); err != nil {
+			log.Fatal("Failed to initialize blob store:", err)
+		}
+	}
+	attachmentService := attachments.NewService(blobStore, attachmentRepo)
+
+	// Initialize content moderation (MODERATION_CONFIG, or built-in defaults)
+	moderator, err := moderation.NewModeratorFromEnv(moderationRepo)
+	if err != nil {
+		log.Fatal("Failed to initialize moderation:", err)
+	}
+
+	// Initialize message retention (RETENTION_CONFIG, or keep everything).
+	// Demo data never outlives the process, so there is nothing to purge.
+	purger := retention.NewPurger(nil, db, messageRepository, attachmentService)
+	if !*demo {
+		if purger, err = retention.NewPurgerFromEnv(db, messageRepository, attachmentService); err != nil {
+			log.Fatal("Failed to initialize retention:", err)
+		}
+	}
+	go purger.Run()
+
+	// Demo mode starts from a small fixture set instead of an empty store
+	if *demo {
+		if err := handlers.LoadDemoFixtures(postRepo, unitOfWork); err != nil {
+			log.Fatal("Failed to load demo fixtures:", err)
+		}
+	}
+
+	// Post and comment writes from REST and WebSocket alike are published on the
+	// domain bus, and the hub fans them out to post subscribers and the posts feed
+	bus := domain.NewBus()
 
 	// Initialize event router with repositories
-	websocket.InitializeEventRouter(messageRepo, commentRepo)
+	websocket.InitializeEventRouter(messageRepo, postRepo, commentRepo, unitOfWork, reactionRepo, readReceiptRepo, searchRepo, mentionRepo, attachmentRepo, attachmentService, moderator, roomModerationRepo, bus)
 
 	// Initialize simple WebSocket hub
 	hub := websocket.NewHub()
+	bus.Subscribe(hub.HandleDomainEvent)
 	go hub.Run()
 
+	// Long-polling sessions join the hub like WebSocket clients; idle ones expire
+	poller := websocket.NewLongPoller(hub)
+	go poller.Run()
+
 	// Setup routes
-	router := handlers.SetupEnhancedRoutes(hub, messageRepo, postRepo, commentRepo)
+	router := handlers.SetupEnhancedRoutes(hub, messageRepo, postRepo, commentRepo, unitOfWork, readReceiptRepo, searchRepo, mentionRepo, attachmentRepo, attachmentService, moderationRepo, moderator, roomModerationRepo, purger, bus, poller)
 
 	log.Printf("🚀 WebSocket server starting on port %s", port)
+	if *demo {
+		log.Println("🧪 Demo mode: all data is kept in memory and lost on exit")
+	}
 	log.Printf("📝 Visit http://localhost:%s for Posts & Comments demo", port)
 	log.Printf("💬 Visit http://localhost:%s/chat for Chat demo", port)
 	log.Printf("🔗 WebSocket endpoint: ws://localhost:%s/ws", port)
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"websocket/pkg/client"
+	"websocket/pkg/protocol"
+)
+
+const helpText = `Commands:
+  /join ROOM                     join a room and make it current
+  /say ROOM TEXT                 send a chat message (plain text goes to the current room)
+  /comment POST TEXT             comment on a post
+  /reply POST COMMENT_ID TEXT    reply to a comment
+  /sub POST [SNAPSHOT]           subscribe to a post, optionally with the newest comments
+  /unsub POST                    unsubscribe from a post
+  /feed, /unfeed                 subscribe to or leave the posts feed
+  /history [ROOM | post:ID] [N]  fetch the newest messages or comments
+  /search QUERY                  full-text search
+  /only [TYPES], /hide [TYPES]   show only, or hide, comma-separated event types; empty clears
+  /sleep DURATION                pause, e.g. /sleep 500ms (for scripts)
+  /quit
+Lines starting with { are sent as raw events, e.g. {"type":"MARK_READ","room":"general"}`
+
+// rawQuiet is how long a raw event may go unanswered before it counts as
+// accepted. Events without a reply never echo their request_id unless they fail.
+const rawQuiet = 500 * time.Millisecond
+
+var errQuit = errors.New("quit")
+
+// session executes command lines against one client
+type session struct {
+	client  *client.Client
+	out     *printer
+	timeout time.Duration
+	room    string // Target of plain-text lines; the last room joined
+
+	mutex   sync.Mutex
+	rawID   int
+	rawSent map[string]chan json.RawMessage // Raw events awaiting a reply, by request_id
+}
+
+func newSession(c *client.Client, out *printer, timeout time.Duration) *session {
+	s := &session{client: c, out: out, timeout: timeout, rawSent: map[string]chan json.RawMessage{}}
+	c.OnEvent("*", s.replied)
+	return s
+}
+
+// run executes lines until EOF or /quit and returns how many failed. Script
+// lines are echoed so their output can be told apart.
+func (s *session) run(scanner *bufio.Scanner, echo bool) int {
+	failed := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if echo {
+			s.out.step(line)
+		}
+
+		if err := s.execute(line); err != nil {
+			if err == errQuit {
+				break
+			}
+			failed++
+			s.out.failure(err)
+		}
+	}
+	return failed
+}
+
+func (s *session) execute(line string) error {
+	if strings.HasPrefix(line, "{") {
+		return s.sendRaw(line)
+	}
+	if !strings.HasPrefix(line, "/") {
+		if s.room == "" {
+			return fmt.Errorf("no current room; /join one first")
+		}
+		return s.say(s.room, line)
+	}
+
+	command, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	args := strings.Fields(rest)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	switch command {
+	case "/join":
+		if len(args) != 1 {
+			return usage("/join ROOM")
+		}
+		if _, err := s.client.JoinRoom(ctx, args[0]); err != nil {
+			return err
+		}
+		s.room = args[0]
+		return nil
+
+	case "/say":
+		room, text, ok := strings.Cut(rest, " ")
+		if !ok {
+			return usage("/say ROOM TEXT")
+		}
+		return s.say(room, text)
+
+	case "/comment":
+		postID, text, ok := strings.Cut(rest, " ")
+		if !ok {
+			return usage("/comment POST TEXT")
+		}
+		_, err := s.client.PostComment(ctx, postID, text, "")
+		return err
+
+	case "/reply":
+		parts := strings.SplitN(rest, " ", 3)
+		if len(parts) != 3 {
+			return usage("/reply POST COMMENT_ID TEXT")
+		}
+		_, err := s.client.PostComment(ctx, parts[0], parts[2], parts[1])
+		return err
+
+	case "/sub":
+		if len(args) < 1 || len(args) > 2 {
+			return usage("/sub POST [SNAPSHOT]")
+		}
+		snapshot := 0
+		if len(args) == 2 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return usage("/sub POST [SNAPSHOT]")
+			}
+			snapshot = n
+		}
+		_, err := s.client.SubscribePost(ctx, args[0], snapshot)
+		return err
+
+	case "/unsub":
+		if len(args) != 1 {
+			return usage("/unsub POST")
+		}
+		return s.client.UnsubscribePost(ctx, args[0])
+
+	case "/feed":
+		return s.client.SubscribeFeed(ctx)
+
+	case "/unfeed":
+		return s.client.UnsubscribeFeed(ctx)
+
+	case "/history":
+		return s.history(ctx, args)
+
+	case "/search":
+		if rest == "" {
+			return usage("/search QUERY")
+		}
+		_, err := s.client.Search(ctx, protocol.SearchEvent{Query: rest})
+		return err
+
+	case "/only":
+		s.out.setOnly(rest)
+		return nil
+
+	case "/hide":
+		s.out.setHide(rest)
+		return nil
+
+	case "/sleep":
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return usage("/sleep DURATION")
+		}
+		time.Sleep(d)
+		return nil
+
+	case "/help":
+		fmt.Println(helpText)
+		return nil
+
+	case "/quit", "/exit":
+		return errQuit
+
+	default:
+		return fmt.Errorf("unknown command %s; /help lists them", command)
+	}
+}
+
+func (s *session) say(room, text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	_, err := s.client.SendMessage(ctx, room, text)
+	return err
+}
+
+// history fetches the newest page of a room (the current one by default) or, with post:ID, a post
+func (s *session) history(ctx context.Context, args []string) error {
+	query := protocol.FetchHistoryEvent{Room: s.room}
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			query.Limit = n
+		} else if postID, ok := strings.CutPrefix(arg, "post:"); ok {
+			query.Room, query.PostID = "", postID
+		} else {
+			query.Room = arg
+		}
+	}
+	if query.Room == "" && query.PostID == "" {
+		return usage("/history [ROOM | post:ID] [N]")
+	}
+
+	_, err := s.client.FetchHistory(ctx, query)
+	return err
+}
+
+// sendRaw sends a raw event tagged with a request_id, unless it has one, and
+// fails if the server answers with an ERROR echoing it
+func (s *session) sendRaw(line string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return fmt.Errorf("invalid JSON event")
+	}
+	var id string
+	if json.Unmarshal(fields["request_id"], &id) != nil || id == "" {
+		s.mutex.Lock()
+		s.rawID++
+		id = "wscli-" + strconv.Itoa(s.rawID)
+		s.mutex.Unlock()
+		fields["request_id"], _ = json.Marshal(id)
+	}
+
+	reply := make(chan json.RawMessage, 1)
+	s.mutex.Lock()
+	s.rawSent[id] = reply
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.rawSent, id)
+		s.mutex.Unlock()
+	}()
+
+	if err := s.client.Send(fields); err != nil {
+		return err
+	}
+
+	select {
+	case data := <-reply:
+		var event protocol.ErrorEvent
+		if json.Unmarshal(data, &event) != nil || event.Type != protocol.EventError {
+			return nil
+		}
+		return &client.Error{Code: event.Code, Message: event.Message, Field: event.Field}
+	case <-time.After(rawQuiet):
+		return nil
+	}
+}
+
+// replied hands an event echoing a raw event's request_id to its sender
+func (s *session) replied(data json.RawMessage) {
+	var event struct {
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(data, &event) != nil || event.RequestID == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if reply, ok := s.rawSent[event.RequestID]; ok {
+		delete(s.rawSent, event.RequestID)
+		reply <- data
+	}
+}
+
+func usage(syntax string) error {
+	return fmt.Errorf("usage: %s", syntax)
+}
@@ -0,0 +1,89 @@
+// Command wscli is an interactive WebSocket client for debugging the chat
+// protocol from a terminal.
+//
+//	wscli [-url ws://localhost:8080/ws] [-user name] [-only TYPES] [-hide TYPES] [-raw] [-script file [-i]]
+//
+// Lines read from stdin are commands (/join general, /say hi, /comment post123
+// nice, /sub post123 ...; /help lists them) or raw JSON events. Incoming events
+// are pretty-printed as they arrive; -only and -hide take comma-separated event
+// types, and -raw prints them as JSON lines for jq.
+//
+// -script replays a file of the same lines, one step at a time, then waits
+// briefly for trailing events and exits non-zero if any step failed. Raw events
+// are tagged with a request_id and fail when the server answers it with an
+// ERROR. Use /sleep between steps to pace them, and -i to stay interactive
+// afterwards.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"websocket/pkg/client"
+)
+
+func main() {
+	url := flag.String("url", "ws://localhost:8080/ws", "server WebSocket endpoint")
+	user := flag.String("user", "wscli", "username to connect as")
+	only := flag.String("only", "", "comma-separated event types to show; all when empty")
+	hide := flag.String("hide", "", "comma-separated event types to hide")
+	raw := flag.Bool("raw", false, "print events as raw JSON lines")
+	noColor := flag.Bool("no-color", false, "disable colored output")
+	script := flag.String("script", "", "replay commands and events from this file")
+	interactive := flag.Bool("i", false, "read commands from stdin after the script")
+	wait := flag.Duration("wait", 2*time.Second, "how long to print events after the script")
+	timeout := flag.Duration("timeout", 10*time.Second, "reply timeout for commands")
+	flag.Parse()
+
+	out := newPrinter(*raw, !*noColor && isTerminal(os.Stdout))
+	out.setOnly(*only)
+	out.setHide(*hide)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	c, err := client.Connect(ctx, *url, client.Options{Username: *user, RequestTimeout: *timeout})
+	cancel()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	c.OnEvent("*", out.event)
+	c.OnDisconnect(func(err error) { out.notice("disconnected (%v), reconnecting...", err) })
+	c.OnReconnect(func() { out.notice("reconnected; rejoining rooms and subscriptions") })
+	out.notice("connected to %s as %s", *url, *user)
+
+	session := newSession(c, out, *timeout)
+
+	if *script != "" {
+		file, err := os.Open(*script)
+		if err != nil {
+			log.Fatal(err)
+		}
+		failed := session.run(bufio.NewScanner(file), true)
+		file.Close()
+
+		if !*interactive {
+			time.Sleep(*wait)
+			if failed > 0 {
+				out.notice("%d script steps failed", failed)
+				c.Close()
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Type /help for commands, /quit to exit")
+	session.run(bufio.NewScanner(os.Stdin), false)
+}
+
+// isTerminal reports whether f is a character device, i.e. not a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/client"
+	"websocket/pkg/protocol"
+)
+
+// ANSI colors, used only when stdout is a terminal
+const (
+	colorReset = "\033[0m"
+	colorDim   = "\033[2m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+// printer writes events to stdout and notices to stderr, so -raw output can be piped
+type printer struct {
+	raw   bool
+	color bool
+
+	mutex sync.Mutex
+	only  map[string]bool // Show only these types when non-empty
+	hide  map[string]bool
+}
+
+func newPrinter(raw, color bool) *printer {
+	return &printer{raw: raw, color: color}
+}
+
+func (p *printer) setOnly(types string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.only = typeSet(types)
+}
+
+func (p *printer) setHide(types string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.hide = typeSet(types)
+}
+
+// event prints an incoming event, unless filtered out
+func (p *printer) event(data json.RawMessage) {
+	var base struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(data, &base)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if (len(p.only) > 0 && !p.only[base.Type]) || p.hide[base.Type] {
+		return
+	}
+	if p.raw {
+		fmt.Println(string(data))
+		return
+	}
+
+	color := colorCyan
+	if base.Type == protocol.EventError {
+		color = colorRed
+	}
+	fmt.Printf("%s %s %s\n",
+		p.paint(colorDim, time.Now().Format("15:04:05")),
+		p.paint(color, fmt.Sprintf("%-17s", base.Type)),
+		describe(base.Type, data))
+}
+
+// notice prints connection status
+func (p *printer) notice(format string, args ...interface{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	fmt.Fprintln(os.Stderr, p.paint(colorDim, "-- "+fmt.Sprintf(format, args...)))
+}
+
+// step echoes a script line before it runs
+func (p *printer) step(line string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	fmt.Fprintln(os.Stderr, p.paint(colorGreen, "> "+line))
+}
+
+// failure reports a failed command. Errors the server sent are already shown
+// as ERROR or CONTENT_HELD events, so only local failures are printed.
+func (p *printer) failure(err error) {
+	var serverErr *client.Error
+	if errors.As(err, &serverErr) || errors.Is(err, client.ErrContentHeld) {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	fmt.Fprintln(os.Stderr, p.paint(colorRed, "!! "+err.Error()))
+}
+
+func (p *printer) paint(color, text string) string {
+	if !p.color {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// describe renders the interesting fields of known event types on one line,
+// or a few for pages of results; anything else is printed as compact JSON
+func describe(eventType string, data json.RawMessage) string {
+	switch eventType {
+	case protocol.EventChatMessage:
+		var e protocol.ChatMessageEvent
+		if json.Unmarshal(data, &e) == nil {
+			return fmt.Sprintf("[%s] %s: %s  (%s)", e.Room, e.User, e.Message, e.MessageID)
+		}
+
+	case protocol.EventRoomJoined:
+		var e protocol.RoomJoinedEvent
+		if json.Unmarshal(data, &e) == nil {
+			return fmt.Sprintf("[%s] %s joined", e.Room, e.User)
+		}
+
+	case protocol.EventPostComment:
+		var e protocol.PostCommentEvent
+		if json.Unmarshal(data, &e) == nil {
+			reply := ""
+			if e.ReplyTo != "" {
+				reply = ", reply to " + e.ReplyTo
+			}
+			return fmt.Sprintf("[post %s] %s: %s  (%s%s)", e.PostID, e.User, e.Comment, e.CommentID, reply)
+		}
+
+	case protocol.EventCommentUpdated:
+		var e protocol.CommentUpdatedEvent
+		if json.Unmarshal(data, &e) == nil {
+			return fmt.Sprintf("[post %s] %s edited %s: %s", e.PostID, e.User, e.CommentID, e.Comment)
+		}
+
+	case protocol.EventCommentDeleted:
+		var e protocol.CommentDeletedEvent
+		if json.Unmarshal(data, &e) == nil {
+			return fmt.Sprintf("[post %s] %s deleted %s", e.PostID, e.User, e.CommentID)
+		}
+
+	case protocol.EventPostCreated, protocol.EventPostUpdated, protocol.EventPostDeleted:
+		var e protocol.PostEvent
+		if json.Unmarshal(data, &e) == nil {
+			title := ""
+			if e.Post != nil {
+				title = fmt.Sprintf(": %q", e.Post.Title)
+			}
+			return fmt.Sprintf("[post %s] by %s%s", e.PostID, orUnknown(e.User), title)
+		}
+
+	case protocol.EventCommentCreated:
+		var e protocol.CommentCreatedEvent
+		if json.Unmarshal(data, &e) == nil && e.Comment != nil {
+			return fmt.Sprintf("[post %s] %s commented: %s  (%s)", e.PostID, e.User, e.Comment.Content, e.Comment.ID)
+		}
+
+	case protocol.EventPostSubscribers:
+		var e protocol.PostSubscribersEvent
+		if json.Unmarshal(data, &e) == nil {
+			return fmt.Sprintf("[post %s] %d reading", e.PostID, e.Subscribers)
+		}
+
+	case protocol.EventPostSubscribed:
+		var e protocol.PostSubscribedEvent
+		if json.Unmarshal(data, &e) == nil {
+			text := fmt.Sprintf("[post %s] subscribed, %d reading", e.PostID, e.Subscribers)
+			if e.Snapshot != nil {
+				text += fmt.Sprintf(", %d newest comments (more: %t)", len(e.Snapshot.Comments), e.Snapshot.HasMore)
+				text += comments(e.Snapshot.Comments)
+			}
+			return text
+		}
+
+	case protocol.EventHistoryPage:
+		var e protocol.HistoryPageEvent
+		if json.Unmarshal(data, &e) == nil {
+			if e.PostID != "" {
+				return fmt.Sprintf("[post %s] %d comments (more: %t)", e.PostID, len(e.Comments), e.HasMore) + comments(e.Comments)
+			}
+			text := fmt.Sprintf("[%s] %d messages (more: %t)", e.Room, len(e.Messages), e.HasMore)
+			for _, message := range e.Messages {
+				text += fmt.Sprintf("\n    %s %s: %s", message.Timestamp.Format("15:04:05"), message.Username, message.Content)
+			}
+			return text
+		}
+
+	case protocol.EventSearchResults:
+		var e protocol.SearchResultsEvent
+		if json.Unmarshal(data, &e) == nil {
+			text := fmt.Sprintf("%q in %s: %d results", e.Query, e.Scope, len(e.Results))
+			for _, result := range e.Results {
+				text += fmt.Sprintf("\n    %-7s %s by %s: %s", result.Type, result.ID, result.Author, result.Snippet)
+			}
+			return text
+		}
+
+	case protocol.EventMention:
+		var e protocol.MentionEvent
+		if json.Unmarshal(data, &e) == nil {
+			where := "room " + e.RoomID
+			if e.PostID != "" {
+				where = "post " + e.PostID
+			}
+			return fmt.Sprintf("%s mentioned you in %s: %s", e.Author, where, e.Excerpt)
+		}
+
+	case protocol.EventError:
+		var e protocol.ErrorEvent
+		if json.Unmarshal(data, &e) == nil {
+			text := e.Message
+			if e.Code != "" {
+				text = e.Code + ": " + text
+			}
+			if e.RequestID != "" {
+				text += "  (" + e.RequestID + ")"
+			}
+			return text
+		}
+	}
+
+	return compact(data)
+}
+
+func comments(list []*models.Comment) string {
+	text := ""
+	for _, comment := range list {
+		text += fmt.Sprintf("\n    %s %s: %s", comment.ID, comment.AuthorName, comment.Content)
+	}
+	return text
+}
+
+// compact prints an event without its type, which is already shown
+func compact(data json.RawMessage) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return string(data)
+	}
+	delete(fields, "type")
+	out, _ := json.Marshal(fields)
+	return string(out)
+}
+
+func orUnknown(user string) string {
+	if user == "" {
+		return "unknown"
+	}
+	return user
+}
+
+// typeSet parses a comma-separated list of event types
+func typeSet(types string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+			set[t] = true
+		}
+	}
+	return set
+}
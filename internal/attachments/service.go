@@ -0,0 +1,214 @@
+// Package attachments validates uploaded files, stores them in a blob store with
+// image thumbnails, and records their metadata.
+package attachments
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gabriel-vasile/mimetype"
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/pkg/blobstore"
+)
+
+// MaxUploadSize is the largest accepted file in bytes
+const MaxUploadSize = 10 << 20
+
+// Upload errors callers map to client-facing responses
+var (
+	ErrEmptyFile       = errors.New("file is empty")
+	ErrTooLarge        = fmt.Errorf("file too large (max %d MB)", MaxUploadSize>>20)
+	ErrUnsupportedType = errors.New("file type not allowed")
+	ErrImageTooLarge   = errors.New("image dimensions too large")
+)
+
+// allowedTypes lists accepted MIME types, detected from file contents rather than
+// trusted from the client
+var allowedTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+}
+
+// thumbnailTypes are the image types the standard library can decode
+var thumbnailTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Service handles the upload and download side of attachments
+type Service struct {
+	store blobstore.Store
+	repo  *repository.AttachmentRepository
+}
+
+func NewService(store blobstore.Store, repo *repository.AttachmentRepository) *Service {
+	return &Service{
+		store: store,
+		repo:  repo,
+	}
+}
+
+// Upload validates and stores a file for uploader, returning its unattached metadata
+func (s *Service) Upload(filename, uploader string, r io.Reader) (*models.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxUploadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := detectType(data)
+	if contentType == "" {
+		return nil, ErrUnsupportedType
+	}
+
+	id := generateAttachmentID()
+	attachment := &models.Attachment{
+		ID:          id,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  "attachments/" + id + "/original",
+		UploadedBy:  uploader,
+		CreatedAt:   time.Now(),
+	}
+
+	var thumbnail []byte
+	var thumbnailType string
+	if strings.HasPrefix(contentType, "image/") {
+		if width, height, err := imageDimensions(data); err == nil {
+			if width*height > maxImagePixels {
+				return nil, ErrImageTooLarge
+			}
+			attachment.Width, attachment.Height = width, height
+		}
+
+		if thumbnailTypes[contentType] {
+			thumbnail, thumbnailType, err = makeThumbnail(data, contentType)
+			if err != nil {
+				// The original is still useful without a preview
+				log.Printf("⚠️ No thumbnail for %s: %v", id, err)
+				thumbnail = nil
+			}
+		}
+	}
+
+	// STEP 1: Store the blobs
+	if err := s.store.Put(attachment.StorageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		attachment.ThumbnailKey = "attachments/" + id + "/thumbnail"
+		if err := s.store.Put(attachment.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+			s.removeBlobs(attachment)
+			return nil, err
+		}
+	}
+
+	// STEP 2: Record metadata; drop the blobs if that fails so nothing is orphaned
+	if err := s.repo.CreateAttachment(attachment); err != nil {
+		s.removeBlobs(attachment)
+		return nil, err
+	}
+
+	log.Printf("📎 %s uploaded %s (%s, %d bytes) as %s", uploader, attachment.Filename, contentType, attachment.Size, id)
+	return attachment, nil
+}
+
+// Open returns an attachment's metadata and a reader for the original or its thumbnail.
+// The returned content type is the one to serve the blob with.
+func (s *Service) Open(id string, thumbnail bool) (*models.Attachment, io.ReadCloser, string, error) {
+	attachment, err := s.repo.GetAttachmentByID(id)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	key, contentType := attachment.StorageKey, attachment.ContentType
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, "", blobstore.ErrNotFound
+		}
+		key = attachment.ThumbnailKey
+		contentType = "image/png"
+		if attachment.ContentType == "image/jpeg" {
+			contentType = "image/jpeg"
+		}
+	}
+
+	reader, err := s.store.Get(key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return attachment, reader, contentType, nil
+}
+
+// RemoveBlobs deletes the stored files of attachments whose records are already gone
+func (s *Service) RemoveBlobs(attachments []*models.Attachment) {
+	for _, attachment := range attachments {
+		s.removeBlobs(attachment)
+	}
+}
+
+func (s *Service) removeBlobs(attachment *models.Attachment) {
+	for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(key); err != nil {
+			log.Printf("❌ Failed to clean up blob %s: %v", key, err)
+		}
+	}
+}
+
+// detectType sniffs the content and returns the matching allowed type, or ""
+func detectType(data []byte) string {
+	detected := mimetype.Detect(data)
+	for _, allowed := range allowedTypes {
+		if detected.Is(allowed) {
+			return allowed
+		}
+	}
+	return ""
+}
+
+// cleanFilename keeps the base name, without control characters, at a sane length
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+	return name
+}
+
+// generateAttachmentID creates a unique attachment ID
+func generateAttachmentID() string {
+	return fmt.Sprintf("att_%d", time.Now().UnixNano())
+}
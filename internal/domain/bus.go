@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"websocket/internal/models"
+)
+
+// Domain event types, published once a write has been committed
+const (
+	PostCreated    = "POST_CREATED"
+	PostUpdated    = "POST_UPDATED"
+	PostDeleted    = "POST_DELETED"
+	CommentCreated = "COMMENT_CREATED"
+)
+
+// Where a write came from
+const (
+	SourceREST      = "rest"
+	SourceWebSocket = "websocket"
+)
+
+// Event describes a committed change to a post or its comments
+type Event struct {
+	Type       string
+	PostID     string
+	Post       *models.Post    // Set for POST_CREATED and POST_UPDATED
+	Comment    *models.Comment // Set for COMMENT_CREATED, attachments included
+	Actor      string          // Username that made the change
+	Source     string          // SourceREST or SourceWebSocket
+	OccurredAt time.Time
+}
+
+// Handler receives published events
+type Handler func(event Event)
+
+// Bus is a synchronous in-process publish/subscribe bus. Handlers run on the
+// publisher's goroutine in the order they subscribed, so by the time Publish
+// returns every subscriber has seen the event.
+type Bus struct {
+	mutex    sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every event published from now on
+func (b *Bus) Subscribe(handler Handler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish delivers an event to all subscribers. A nil bus drops events, so
+// callers that were built without one keep working.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mutex.RLock()
+	handlers := b.handlers
+	b.mutex.RUnlock()
+
+	log.Printf("📣 %s for post %s (%s)", event.Type, event.PostID, event.Source)
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
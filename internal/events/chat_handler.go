@@ -7,14 +7,15 @@ import (
 
 	"websocket/internal/models"
 	"websocket/internal/repository"
+	"websocket/pkg/markdown"
 )
 
 // ChatEventHandler handles chat-related WebSocket events
 type ChatEventHandler struct {
-	messageRepo *repository.MessageRepository
+	messageRepo repository.MessageStore
 }
 
-func NewChatEventHandler(messageRepo *repository.MessageRepository) *ChatEventHandler {
+func NewChatEventHandler(messageRepo repository.MessageStore) *ChatEventHandler {
 	return &ChatEventHandler{
 		messageRepo: messageRepo,
 	}
@@ -63,6 +64,7 @@ func (h *ChatEventHandler) handleSendMessage(event *models.WSEvent, client Clien
 		ID:        generateID(),
 		Username:  client.GetUsername(),
 		Content:   chatData.Message.Content,
+		ContentHTML: markdown.Render(chatData.Message.Content),
 		RoomID:    client.GetRoomID(),
 		Type:      "message",
 		Timestamp: time.Now(),
@@ -97,6 +99,7 @@ func (h *ChatEventHandler) handleJoinRoom(event *models.WSEvent, client Client)
 		ID:        generateID(),
 		Username:  client.GetUsername(),
 		Content:   client.GetUsername() + " joined the chat",
+		ContentHTML: markdown.Render(client.GetUsername() + " joined the chat"),
 		RoomID:    client.GetRoomID(),
 		Type:      "join",
 		Timestamp: time.Now(),
@@ -130,6 +133,7 @@ func (h *ChatEventHandler) handleLeaveRoom(event *models.WSEvent, client Client)
 		ID:        generateID(),
 		Username:  client.GetUsername(),
 		Content:   client.GetUsername() + " left the chat",
+		ContentHTML: markdown.Render(client.GetUsername() + " left the chat"),
 		RoomID:    client.GetRoomID(),
 		Type:      "leave",
 		Timestamp: time.Now(),
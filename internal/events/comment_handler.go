@@ -5,20 +5,24 @@ import (
 	"log"
 	"time"
 
+	"websocket/internal/attachments"
 	"websocket/internal/models"
 	"websocket/internal/repository"
+	"websocket/pkg/markdown"
 )
 
 // CommentEventHandler handles comment-related WebSocket events
 type CommentEventHandler struct {
-	commentRepo *repository.CommentRepository
-	postRepo    *repository.PostRepository
+	commentRepo repository.CommentStore
+	postRepo    repository.PostStore
+	attachments *attachments.Service
 }
 
-func NewCommentEventHandler(commentRepo *repository.CommentRepository, postRepo *repository.PostRepository) *CommentEventHandler {
+func NewCommentEventHandler(commentRepo repository.CommentStore, postRepo repository.PostStore, attachmentService *attachments.Service) *CommentEventHandler {
 	return &CommentEventHandler{
 		commentRepo: commentRepo,
 		postRepo:    postRepo,
+		attachments: attachmentService,
 	}
 }
 
@@ -73,6 +77,7 @@ func (h *CommentEventHandler) handleCreateComment(event *models.WSEvent, client
 		ID:         generateID(),
 		PostID:     commentData.PostID,
 		Content:    commentData.Comment.Content,
+		ContentHTML: markdown.Render(commentData.Comment.Content),
 		AuthorID:   client.GetUserID(),
 		AuthorName: client.GetUsername(),
 		CreatedAt:  time.Now(),
@@ -143,6 +148,7 @@ func (h *CommentEventHandler) handleUpdateComment(event *models.WSEvent, client
 
 	// Update comment
 	existingComment.Content = commentData.Comment.Content
+	existingComment.ContentHTML = markdown.Render(commentData.Comment.Content)
 	if err := h.commentRepo.UpdateComment(existingComment); err != nil {
 		log.Printf("Failed to update comment: %v", err)
 		return err
@@ -200,13 +206,15 @@ func (h *CommentEventHandler) handleDeleteComment(event *models.WSEvent, client
 	}
 
 	// Delete comment
-	if err := h.commentRepo.DeleteComment(commentData.Comment.ID); err != nil {
+	deleted, removed, err := h.commentRepo.DeleteComment(commentData.Comment.ID)
+	if err != nil {
 		log.Printf("Failed to delete comment: %v", err)
 		return err
 	}
+	h.attachments.RemoveBlobs(removed)
 
-	// Decrement post comment count
-	if err := h.postRepo.DecrementCommentCount(existingComment.PostID); err != nil {
+	// Decrement post comment count (replies are deleted too)
+	if err := h.postRepo.DecrementCommentCount(existingComment.PostID, deleted); err != nil {
 		log.Printf("Failed to decrement comment count: %v", err)
 	}
 
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin guards admin endpoints with the shared secret in ADMIN_TOKEN, sent as
+// "Authorization: Bearer <token>" or X-Admin-Token. Without ADMIN_TOKEN the admin
+// API is disabled.
+func RequireAdmin() gin.HandlerFunc {
+	token := os.Getenv("ADMIN_TOKEN")
+
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin API is disabled (set ADMIN_TOKEN)"})
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if bearer := c.GetHeader("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+			provided = strings.TrimPrefix(bearer, "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
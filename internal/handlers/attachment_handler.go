@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/attachments"
+	"websocket/internal/repository"
+	"websocket/pkg/blobstore"
+)
+
+// AttachmentHandler serves file uploads and downloads
+type AttachmentHandler struct {
+	service *attachments.Service
+}
+
+func NewAttachmentHandler(service *attachments.Service) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+	}
+}
+
+// Upload handles POST /attachments?username= with a multipart "file" field.
+// The returned ID can then be referenced from CHAT_MESSAGE or POST_COMMENT.
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username query parameter is required"})
+		return
+	}
+
+	// Leave room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, attachments.MaxUploadSize+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": attachments.ErrTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field \"file\" is required"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(header.Filename, username, file)
+	switch {
+	case errors.Is(err, attachments.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, attachments.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, attachments.ErrEmptyFile), errors.Is(err, attachments.ErrImageTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("❌ Upload failed for %s: %v", username, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// Download handles GET /attachments/:id
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// Thumbnail handles GET /attachments/:id/thumbnail
+func (h *AttachmentHandler) Thumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	attachment, reader, contentType, err := h.service.Open(c.Param("id"), thumbnail)
+	if errors.Is(err, repository.ErrAttachmentNotFound) || errors.Is(err, blobstore.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to open attachment %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
+		return
+	}
+	defer reader.Close()
+
+	// Only images render inline; everything else downloads so the browser never runs it
+	disposition := "attachment"
+	if strings.HasPrefix(contentType, "image/") {
+		disposition = "inline"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=31536000, immutable")
+	if !thumbnail {
+		c.Header("Content-Length", fmt.Sprint(attachment.Size))
+	}
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		log.Printf("❌ Failed to send attachment %s: %v", attachment.ID, err)
+	}
+}
@@ -5,16 +5,17 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"websocket/internal/models"
 	"websocket/internal/repository"
 	"websocket/internal/websocket"
 )
 
 type ChatHandler struct {
 	hub         *websocket.Hub
-	messageRepo *repository.MessageRepository
+	messageRepo repository.MessageStore
 }
 
-func NewChatHandler(hub *websocket.Hub, messageRepo *repository.MessageRepository) *ChatHandler {
+func NewChatHandler(hub *websocket.Hub, messageRepo repository.MessageStore) *ChatHandler {
 	return &ChatHandler{
 		hub:         hub,
 		messageRepo: messageRepo,
@@ -37,39 +38,53 @@ func (h *ChatHandler) IndexPage(c *gin.Context) {
 	})
 }
 
+// GetMessages returns a page of a room's messages, oldest first. Without a cursor it
+// returns the newest page; next_cursor (used as before) scrolls back to older messages
+// and prev_cursor (used as after) catches up on newer ones. offset is still accepted.
 func (h *ChatHandler) GetMessages(c *gin.Context) {
 	roomID := c.Query("room")
 	if roomID == "" {
 		roomID = "general"
 	}
 
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+	page, ok := parsePageRequest(c, 50, 100)
+	if !ok {
 		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
-		return
+	var messages []*models.Message
+	var hasMore bool
+	var err error
+	switch {
+	case page.UseOffset:
+		messages, err = h.messageRepo.GetMessagesByRoom(roomID, page.Limit+1, page.Offset)
+		if hasMore = len(messages) > page.Limit; hasMore {
+			messages = messages[:page.Limit]
+		}
+	case page.After != nil:
+		messages, hasMore, err = h.messageRepo.GetMessagesAfter(roomID, page.After, page.Limit)
+	default:
+		messages, hasMore, err = h.messageRepo.GetMessagesBefore(roomID, page.Before, page.Limit)
 	}
-
-	messages, err := h.messageRepo.GetMessagesByRoom(roomID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"messages": messages,
 		"room_id":  roomID,
-		"limit":    limit,
-		"offset":   offset,
-	})
+	}
+
+	var next, prev *repository.Cursor
+	if len(messages) > 0 {
+		oldest, newest := messages[0], messages[len(messages)-1]
+		next = repository.NewCursor(oldest.Timestamp, oldest.ID)
+		prev = repository.NewCursor(newest.Timestamp, newest.ID)
+	}
+	page.addPagination(response, len(messages), hasMore, next, prev)
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *ChatHandler) GetRecentMessages(c *gin.Context) {
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondCached writes body as JSON with an ETag and answers 304 Not Modified
+// when the client already holds the same response. The ETag hashes the whole
+// body, so it changes with anything the client would see, including deletions,
+// comment counts and reactions. There is no Last-Modified: no stored timestamp
+// moves on all of those changes.
+func respondCached(c *gin.Context, body gin.H) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode response"})
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	// Clients may store the response but must revalidate before reusing it
+	c.Header("Cache-Control", "no-cache")
+	c.Header("ETag", etag)
+
+	if notModified(c.Request, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+// notModified reports whether If-None-Match lists etag (RFC 9110 13.1.2)
+func notModified(r *http.Request, etag string) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || (candidate != "" && strings.TrimPrefix(candidate, "W/") == etag) {
+			return true
+		}
+	}
+	return false
+}
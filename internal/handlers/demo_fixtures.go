@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/pkg/markdown"
+)
+
+// LoadDemoFixtures fills the empty demo-mode database with a couple of posts and
+// comments, so the posts pages have something to show. Comments go through
+// the unit of work like any other, so the stored counts match.
+func LoadDemoFixtures(postRepo repository.PostStore, unitOfWork repository.UnitOfWork) error {
+	now := time.Now()
+
+	posts := []*models.Post{
+		{
+			ID:         "demo-post-456",
+			Title:      "📚 Học Go từ cơ bản đến nâng cao",
+			Content:    "Series bài viết hướng dẫn học Go programming language từ A-Z...",
+			AuthorID:   "author-456",
+			AuthorName: "Go Expert",
+			CreatedAt:  now.Add(-4 * time.Hour),
+			UpdatedAt:  now.Add(-3 * time.Hour),
+		},
+		{
+			ID:         "demo-post-123",
+			Title:      "🚀 Demo WebSocket Real-time Comments",
+			Content:    "Đây là bài post demo để test hệ thống comment real-time với WebSocket. Bạn có thể thử comment và sẽ thấy các comment xuất hiện real-time trên tất cả các kết nối WebSocket đang lắng nghe post này.\n\nTính năng:\n• Real-time comments qua WebSocket\n• Event-driven architecture\n• Room-based broadcasting\n• Persistent storage với SQLite\n\nHãy thử comment bên dưới! 💬",
+			AuthorID:   "demo-user-123",
+			AuthorName: "Demo User",
+			CreatedAt:  now.Add(-2 * time.Hour),
+			UpdatedAt:  now.Add(-1 * time.Hour),
+		},
+	}
+	for _, post := range posts {
+		if err := postRepo.CreatePost(post); err != nil {
+			return err
+		}
+	}
+
+	comments := []*models.Comment{
+		{
+			ID:         "demo-comment-1",
+			PostID:     "demo-post-123",
+			Content:    "Bài viết rất hay! Cảm ơn bạn đã chia sẻ kiến thức về WebSocket 🎉",
+			AuthorID:   "user-1",
+			AuthorName: "Alice",
+			CreatedAt:  now.Add(-90 * time.Minute),
+		},
+		{
+			ID:         "demo-comment-2",
+			PostID:     "demo-post-123",
+			Content:    "Event-driven architecture thực sự rất powerful. Tôi đã implement tương tự và performance tăng đáng kể! 🚀",
+			AuthorID:   "user-2",
+			AuthorName: "Bob",
+			CreatedAt:  now.Add(-60 * time.Minute),
+		},
+		{
+			ID:         "demo-comment-3",
+			PostID:     "demo-post-123",
+			Content:    "Có thể share source code của dự án không? Tôi muốn học thêm về WebSocket với Go 💻",
+			AuthorID:   "user-3",
+			AuthorName: "Charlie",
+			CreatedAt:  now.Add(-30 * time.Minute),
+		},
+	}
+	for _, comment := range comments {
+		comment.ContentHTML = markdown.Render(comment.Content)
+		comment.UpdatedAt = comment.CreatedAt
+		if err := repository.CreatePostComment(unitOfWork, comment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
 	"websocket/internal/events"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
+	"websocket/internal/retention"
 	"websocket/internal/websocket"
 
 	"github.com/gin-contrib/cors"
@@ -11,9 +15,21 @@ import (
 
 func SetupEnhancedRoutes(
 	hub *websocket.Hub,
-	messageRepo *repository.MessageRepository,
-	postRepo *repository.PostRepository,
-	commentRepo *repository.CommentRepository,
+	messageRepo repository.MessageStore,
+	postRepo repository.PostStore,
+	commentRepo repository.CommentStore,
+	unitOfWork repository.UnitOfWork,
+	readReceiptRepo *repository.ReadReceiptRepository,
+	searchRepo *repository.SearchRepository,
+	mentionRepo *repository.MentionRepository,
+	attachmentRepo *repository.AttachmentRepository,
+	attachmentService *attachments.Service,
+	moderationRepo *repository.ModerationRepository,
+	moderator *moderation.Moderator,
+	roomModerationRepo *repository.RoomModerationRepository,
+	purger *retention.Purger,
+	bus *domain.Bus,
+	poller *websocket.LongPoller,
 ) *gin.Engine {
 	r := gin.Default()
 
@@ -31,7 +47,16 @@ func SetupEnhancedRoutes(
 
 	// Initialize simple chat handler
 	chatHandler := NewSimpleChatHandler(hub, messageRepo)
-	postHandler := NewPostHandler(postRepo, commentRepo)
+	postHandler := NewPostHandler(hub, postRepo, commentRepo, unitOfWork, attachmentService, moderator, bus)
+	userHandler := NewUserHandler(readReceiptRepo, mentionRepo)
+	searchHandler := NewSearchHandler(searchRepo)
+	attachmentHandler := NewAttachmentHandler(attachmentService)
+	moderationHandler := NewModerationHandler(hub, moderationRepo, messageRepo, commentRepo, unitOfWork, attachmentRepo, mentionRepo, readReceiptRepo, bus)
+	roomAdminHandler := NewRoomAdminHandler(roomModerationRepo)
+	retentionHandler := NewRetentionHandler(purger)
+	streamHandler := NewStreamHandler(hub, postRepo, roomModerationRepo)
+	pollHandler := NewPollHandler(poller)
+	protocolHandler := NewProtocolHandler()
 
 	// Frontend routes
 	r.GET("/", chatHandler.IndexPage)
@@ -63,10 +88,33 @@ func SetupEnhancedRoutes(
 					"comments",
 					"posts",
 					"real-time events",
+					"server-sent events",
+					"long polling",
+					"protocol schema",
+					"search",
+					"attachments",
+					"moderation",
 				},
 			})
 		})
 
+		// Machine-readable WebSocket protocol, generated from pkg/protocol
+		api.GET("/protocol", protocolHandler.GetDocument)             // GET /api/v1/protocol (AsyncAPI 2.6)
+		api.GET("/protocol/schemas", protocolHandler.ListSchemas)     // GET /api/v1/protocol/schemas
+		api.GET("/protocol/schemas/:name", protocolHandler.GetSchema) // GET /api/v1/protocol/schemas/client.JOIN_ROOM (JSON Schema)
+
+		// Read-only event stream for clients that can't use WebSocket
+		api.GET("/stream", streamHandler.Stream) // GET /api/v1/stream?rooms=&posts=&username= (SSE)
+
+		// Long-polling fallback for clients that can use neither WebSocket nor SSE
+		poll := api.Group("/poll")
+		{
+			poll.POST("/sessions", pollHandler.CreateSession)      // POST /api/v1/poll/sessions?username=
+			poll.GET("/sessions/:id", pollHandler.Poll)            // GET /api/v1/poll/sessions/:id?after=&timeout=
+			poll.POST("/sessions/:id/send", pollHandler.Send)      // POST /api/v1/poll/sessions/:id/send (one event)
+			poll.DELETE("/sessions/:id", pollHandler.CloseSession) // DELETE /api/v1/poll/sessions/:id
+		}
+
 		// Chat messages (legacy support)
 		api.GET("/messages/:room", chatHandler.GetRecentMessages)
 		api.GET("/messages/recent", chatHandler.GetRecentMessages)
@@ -76,18 +124,56 @@ func SetupEnhancedRoutes(
 		api.GET("/test/comment", chatHandler.SendTestComment)
 		api.GET("/stats", chatHandler.GetStats)
 
-		// Posts management (using mock for demo)
+		// Full-text search over messages, posts and comments
+		api.GET("/search", searchHandler.Search)
+
+		// File attachments for chat messages and comments
+		api.POST("/attachments", attachmentHandler.Upload)                 // POST /api/v1/attachments?username= (multipart "file")
+		api.GET("/attachments/:id", attachmentHandler.Download)            // GET /api/v1/attachments/:id
+		api.GET("/attachments/:id/thumbnail", attachmentHandler.Thumbnail) // GET /api/v1/attachments/:id/thumbnail
+
+		// Per-user state (identified by ?username=)
+		me := api.Group("/me")
+		{
+			me.GET("/unread", userHandler.GetUnread)     // GET /api/v1/me/unread?username=
+			me.GET("/mentions", userHandler.GetMentions) // GET /api/v1/me/mentions?username=
+		}
+
+		// Admin endpoints (ADMIN_TOKEN bearer token)
+		admin := api.Group("/admin", RequireAdmin())
+		{
+			admin.GET("/moderation/queue", moderationHandler.GetQueue)             // GET /api/v1/admin/moderation/queue?status=
+			admin.POST("/moderation/queue/:id/approve", moderationHandler.Approve) // POST /api/v1/admin/moderation/queue/:id/approve
+			admin.POST("/moderation/queue/:id/reject", moderationHandler.Reject)   // POST /api/v1/admin/moderation/queue/:id/reject
+
+			admin.GET("/rooms/:room/roles", roomAdminHandler.GetRoles)                // GET /api/v1/admin/rooms/:room/roles
+			admin.PUT("/rooms/:room/roles/:username", roomAdminHandler.SetRole)       // PUT /api/v1/admin/rooms/:room/roles/:username
+			admin.DELETE("/rooms/:room/roles/:username", roomAdminHandler.RemoveRole) // DELETE /api/v1/admin/rooms/:room/roles/:username
+			admin.GET("/rooms/:room/sanctions", roomAdminHandler.GetSanctions)        // GET /api/v1/admin/rooms/:room/sanctions
+
+			admin.GET("/retention", retentionHandler.GetStatus)        // GET /api/v1/admin/retention
+			admin.POST("/retention/preview", retentionHandler.Preview) // POST /api/v1/admin/retention/preview (dry run)
+			admin.POST("/retention/run", retentionHandler.RunNow)      // POST /api/v1/admin/retention/run
+		}
+
+		// Posts management
 		posts := api.Group("/posts")
 		{
-			posts.GET("", GetAllMockPosts)               // GET /api/v1/posts (MOCK)
+			posts.GET("", postHandler.GetAllPosts)       // GET /api/v1/posts (ETag)
 			posts.POST("", postHandler.CreatePost)       // POST /api/v1/posts
-			posts.GET("/:id", GetMockPost)               // GET /api/v1/posts/:id (MOCK)
+			posts.GET("/:id", postHandler.GetPostByID)   // GET /api/v1/posts/:id (ETag)
 			posts.PUT("/:id", postHandler.UpdatePost)    // PUT /api/v1/posts/:id
 			posts.DELETE("/:id", postHandler.DeletePost) // DELETE /api/v1/posts/:id
 
-			// Comments for posts (using mock for demo)
-			posts.GET("/:id/comments", GetMockComments)        // GET /api/v1/posts/:id/comments (MOCK)
-			posts.GET("/:id/comments/recent", GetMockComments) // GET /api/v1/posts/:id/comments/recent (MOCK)
+			// Comments for posts
+			posts.GET("/:id/comments", postHandler.GetCommentsByPostID)                  // GET /api/v1/posts/:id/comments (ETag)
+			posts.GET("/:id/comments/recent", postHandler.GetRecentCommentsByPostID)     // GET /api/v1/posts/:id/comments/recent (ETag)
+			posts.GET("/:id/comments/tree", postHandler.GetCommentTree)                  // GET /api/v1/posts/:id/comments/tree
+			posts.GET("/:id/comments/:commentId/replies", postHandler.GetCommentReplies) // GET /api/v1/posts/:id/comments/:commentId/replies
+
+			// Editing or deleting someone else's comment is moderation; authors use the WebSocket events
+			posts.PUT("/:id/comments/:commentId", RequireAdmin(), postHandler.UpdateComment)    // PUT /api/v1/posts/:id/comments/:commentId (admin)
+			posts.DELETE("/:id/comments/:commentId", RequireAdmin(), postHandler.DeleteComment) // DELETE /api/v1/posts/:id/comments/:commentId?username= (admin)
 		}
 	}
 
@@ -96,16 +182,17 @@ func SetupEnhancedRoutes(
 
 // SetupEventManager configures all event handlers
 func SetupEventManager(
-	messageRepo *repository.MessageRepository,
-	postRepo *repository.PostRepository,
-	commentRepo *repository.CommentRepository,
+	messageRepo repository.MessageStore,
+	postRepo repository.PostStore,
+	commentRepo repository.CommentStore,
+	attachmentService *attachments.Service,
 ) *events.EventManager {
 
 	eventManager := events.NewEventManager()
 
 	// Register event handlers
 	chatHandler := events.NewChatEventHandler(messageRepo)
-	commentHandler := events.NewCommentEventHandler(commentRepo, postRepo)
+	commentHandler := events.NewCommentEventHandler(commentRepo, postRepo, attachmentService)
 
 	eventManager.RegisterHandler(chatHandler)
 	eventManager.RegisterHandler(commentHandler)
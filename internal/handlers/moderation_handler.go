@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/domain"
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket"
+	"websocket/internal/websocket/handlers/chat"
+	"websocket/internal/websocket/handlers/mentions"
+	"websocket/internal/websocket/handlers/receipts"
+	"websocket/pkg/markdown"
+)
+
+// ModerationHandler lets moderators work through the review queue. Approving an
+// item publishes it exactly like a freshly sent message or comment.
+type ModerationHandler struct {
+	hub             *websocket.Hub
+	moderationRepo  *repository.ModerationRepository
+	messageRepo     repository.MessageStore
+	commentRepo     repository.CommentStore
+	unitOfWork      repository.UnitOfWork
+	attachmentRepo  *repository.AttachmentRepository
+	mentionRepo     *repository.MentionRepository
+	readReceiptRepo *repository.ReadReceiptRepository
+	bus             *domain.Bus
+}
+
+func NewModerationHandler(
+	hub *websocket.Hub,
+	moderationRepo *repository.ModerationRepository,
+	messageRepo repository.MessageStore,
+	commentRepo repository.CommentStore,
+	unitOfWork repository.UnitOfWork,
+	attachmentRepo *repository.AttachmentRepository,
+	mentionRepo *repository.MentionRepository,
+	readReceiptRepo *repository.ReadReceiptRepository,
+	bus *domain.Bus,
+) *ModerationHandler {
+	return &ModerationHandler{
+		hub:             hub,
+		moderationRepo:  moderationRepo,
+		messageRepo:     messageRepo,
+		commentRepo:     commentRepo,
+		unitOfWork:      unitOfWork,
+		attachmentRepo:  attachmentRepo,
+		mentionRepo:     mentionRepo,
+		readReceiptRepo: readReceiptRepo,
+		bus:             bus,
+	}
+}
+
+// GetQueue lists queue items newest first (?status=pending|approved|rejected, default pending)
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ModerationPending)
+	switch status {
+	case models.ModerationPending, models.ModerationApproved, models.ModerationRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	page, ok := parsePageRequest(c, 50, 100)
+	if !ok {
+		return
+	}
+	if page.After != nil || page.UseOffset {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the queue only pages backwards with before"})
+		return
+	}
+
+	items, hasMore, err := h.moderationRepo.ListByStatus(status, page.Before, page.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+
+	response := gin.H{
+		"status": status,
+		"items":  items,
+	}
+
+	var next *repository.Cursor
+	if len(items) > 0 {
+		oldest := items[len(items)-1]
+		next = repository.NewCursor(oldest.CreatedAt, oldest.ID)
+	}
+	page.addPagination(response, len(items), hasMore, next, nil)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Approve publishes a held item (?username= names the reviewer). Publishing
+// stores nothing unless it succeeds, so a failed item is reopened as it was and
+// approving it again cannot publish it twice.
+func (h *ModerationHandler) Approve(c *gin.Context) {
+	item, ok := h.review(c, models.ModerationApproved)
+	if !ok {
+		return
+	}
+
+	var publishedID string
+	var err error
+	switch item.TargetType {
+	case models.ModerationTargetMessage:
+		publishedID, err = h.publishMessage(item)
+	case models.ModerationTargetComment:
+		publishedID, err = h.publishComment(item)
+	default:
+		err = fmt.Errorf("unknown target type %q", item.TargetType)
+	}
+
+	if err != nil {
+		log.Printf("❌ Failed to publish moderation item %s: %v", item.ID, err)
+		if reopenErr := h.moderationRepo.Reopen(item.ID); reopenErr != nil {
+			log.Printf("❌ Failed to reopen moderation item %s: %v", item.ID, reopenErr)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to publish held content",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.moderationRepo.SetPublishedID(item.ID, publishedID); err != nil {
+		log.Printf("❌ Failed to record published ID for %s: %v", item.ID, err)
+	}
+	item.PublishedID = publishedID
+
+	log.Printf("🛡️ %s approved %s %s as %s", item.ReviewedBy, item.TargetType, item.ID, publishedID)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+}
+
+// Reject discards a held item (?username= names the reviewer)
+func (h *ModerationHandler) Reject(c *gin.Context) {
+	item, ok := h.review(c, models.ModerationRejected)
+	if !ok {
+		return
+	}
+
+	log.Printf("🛡️ %s rejected %s %s", item.ReviewedBy, item.TargetType, item.ID)
+	c.JSON(http.StatusOK, gin.H{"item": item})
+}
+
+// review claims a pending item for status, writing the error response on failure
+func (h *ModerationHandler) review(c *gin.Context, status string) (*models.ModerationItem, bool) {
+	reviewer := c.DefaultQuery("username", "admin")
+
+	item, err := h.moderationRepo.Review(c.Param("id"), status, reviewer)
+	switch {
+	case errors.Is(err, repository.ErrModerationItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Moderation item not found"})
+		return nil, false
+	case errors.Is(err, repository.ErrModerationItemReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Moderation item was already " + item.Status})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review moderation item"})
+		return nil, false
+	}
+
+	return item, true
+}
+
+// publishMessage saves and broadcasts an approved chat message
+func (h *ModerationHandler) publishMessage(item *models.ModerationItem) (string, error) {
+	attachments, err := h.pendingAttachments(item)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	message := &models.Message{
+		ID:          fmt.Sprintf("msg_%d", now.UnixNano()),
+		Username:    item.Author,
+		Content:     item.Content,
+		ContentHTML: markdown.Render(item.Content),
+		RoomID:      item.RoomID,
+		Type:        "chat",
+		Timestamp:   now,
+		CreatedAt:   now,
+	}
+	// Claim the attachments before saving, and give them back if saving fails,
+	// so a failed publish leaves nothing behind for the item to be reopened
+	if err := h.attachmentRepo.AttachTo(attachments, models.AttachmentTargetMessage, message.ID); err != nil {
+		return "", err
+	}
+	if err := h.messageRepo.SaveMessage(message); err != nil {
+		h.releaseAttachments(item, attachments)
+		return "", err
+	}
+
+	h.hub.BroadcastToChatRoom(item.RoomID, &chat.ChatMessageEvent{
+		Type:        websocket.EventChatMessage,
+		Room:        item.RoomID,
+		User:        item.Author,
+		Message:     message.Content,
+		ContentHTML: message.ContentHTML,
+		MessageID:   message.ID,
+		Attachments: attachments,
+	})
+
+	if _, _, err := h.readReceiptRepo.MarkRead(item.Author, item.RoomID, message.ID); err != nil {
+		log.Printf("❌ Failed to update read position for %s: %v", item.Author, err)
+	}
+	receipts.NotifyNewMessage(h.hub, h.readReceiptRepo, item.RoomID, item.Author)
+
+	mentions.Record(h.hub, h.mentionRepo, models.Mention{
+		SourceType: models.MentionSourceMessage,
+		SourceID:   message.ID,
+		RoomID:     item.RoomID,
+		Author:     item.Author,
+		CreatedAt:  now,
+	}, message.Content, item.Author)
+
+	return message.ID, nil
+}
+
+// publishComment saves an approved comment and publishes it on the domain bus
+func (h *ModerationHandler) publishComment(item *models.ModerationItem) (string, error) {
+	attachments, err := h.pendingAttachments(item)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	comment := &models.Comment{
+		ID:          fmt.Sprintf("comment_%d", now.UnixNano()),
+		PostID:      item.PostID,
+		ParentID:    item.ParentID,
+		Content:     item.Content,
+		ContentHTML: markdown.Render(item.Content),
+		AuthorID:    item.Author,
+		AuthorName:  item.Author,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := h.attachmentRepo.AttachTo(attachments, models.AttachmentTargetComment, comment.ID); err != nil {
+		return "", err
+	}
+	if err := repository.CreatePostComment(h.unitOfWork, comment); err != nil {
+		h.releaseAttachments(item, attachments)
+		return "", err
+	}
+	comment.Attachments = attachments
+
+	h.bus.Publish(domain.Event{
+		Type:       domain.CommentCreated,
+		PostID:     item.PostID,
+		Comment:    comment,
+		Actor:      item.Author,
+		Source:     domain.SourceREST,
+		OccurredAt: now,
+	})
+
+	mentions.Record(h.hub, h.mentionRepo, models.Mention{
+		SourceType: models.MentionSourceComment,
+		SourceID:   comment.ID,
+		PostID:     item.PostID,
+		Author:     item.Author,
+		CreatedAt:  now,
+	}, comment.Content, item.Author)
+
+	return comment.ID, nil
+}
+
+// pendingAttachments re-checks the held item's uploads; they must still be unattached
+func (h *ModerationHandler) pendingAttachments(item *models.ModerationItem) ([]*models.Attachment, error) {
+	if len(item.AttachmentIDs) == 0 {
+		return nil, nil
+	}
+	return h.attachmentRepo.GetPendingAttachments(item.AttachmentIDs, item.Author)
+}
+
+// releaseAttachments gives back the uploads claimed for an item that failed to publish
+func (h *ModerationHandler) releaseAttachments(item *models.ModerationItem, attachments []*models.Attachment) {
+	if err := h.attachmentRepo.Release(attachments); err != nil {
+		log.Printf("❌ Failed to release attachments of moderation item %s: %v", item.ID, err)
+	}
+}
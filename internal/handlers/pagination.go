@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/repository"
+)
+
+// pageRequest holds the paging parameters shared by list endpoints.
+// Keyset cursors (before/after) are the default; offset is only honoured when a
+// client sends it explicitly, for compatibility with older callers.
+type pageRequest struct {
+	Limit     int
+	Before    *repository.Cursor
+	After     *repository.Cursor
+	Offset    int
+	UseOffset bool
+}
+
+// parsePageRequest reads limit, before, after and offset from the query string,
+// writing a 400 response and returning false when they are invalid
+func parsePageRequest(c *gin.Context, defaultLimit, maxLimit int) (pageRequest, bool) {
+	page := pageRequest{Limit: defaultLimit}
+
+	if raw, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return page, false
+		}
+		page.Limit = limit
+	}
+	if page.Limit > maxLimit {
+		page.Limit = maxLimit
+	}
+
+	beforeStr := c.Query("before")
+	afterStr := c.Query("after")
+	offsetStr, hasOffset := c.GetQuery("offset")
+
+	if beforeStr != "" && afterStr != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "before and after cannot be combined"})
+		return page, false
+	}
+	if hasOffset && (beforeStr != "" || afterStr != "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset cannot be combined with a cursor"})
+		return page, false
+	}
+
+	var err error
+	if beforeStr != "" {
+		if page.Before, err = repository.DecodeCursor(beforeStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before cursor"})
+			return page, false
+		}
+	}
+	if afterStr != "" {
+		if page.After, err = repository.DecodeCursor(afterStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid after cursor"})
+			return page, false
+		}
+	}
+
+	if hasOffset {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+			return page, false
+		}
+		page.Offset = offset
+		page.UseOffset = true
+	}
+
+	return page, true
+}
+
+// addPagination writes the paging fields into a list response. next is the cursor
+// for older items, prev the one for newer items; both are nil for an empty page.
+// next_cursor is only written while older items remain, so it is left out of after
+// pages, whose has_more describes newer items. prev_cursor is always written so
+// clients can catch up on items that arrive later.
+func (p pageRequest) addPagination(response gin.H, count int, hasMore bool, next, prev *repository.Cursor) {
+	response["limit"] = p.Limit
+	response["count"] = count
+	response["has_more"] = hasMore
+	if p.UseOffset {
+		response["offset"] = p.Offset
+	}
+	if next != nil && hasMore && p.After == nil {
+		response["next_cursor"] = next.Encode()
+	}
+	if prev != nil {
+		response["prev_cursor"] = prev.Encode()
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/repository"
+)
+
+// next_cursor only leads somewhere while older items remain
+func TestAddPaginationOnlyOffersOlderItemsThatExist(t *testing.T) {
+	cursor := repository.NewCursor(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "m1")
+	tests := []struct {
+		name     string
+		page     pageRequest
+		hasMore  bool
+		wantNext bool
+	}{
+		{"newest page with more", pageRequest{Limit: 10}, true, true},
+		{"newest page, nothing older", pageRequest{Limit: 10}, false, false},
+		{"before page with more", pageRequest{Limit: 10, Before: cursor}, true, true},
+		{"last before page", pageRequest{Limit: 10, Before: cursor}, false, false},
+		{"after page with newer items", pageRequest{Limit: 10, After: cursor}, true, false},
+		{"after page caught up", pageRequest{Limit: 10, After: cursor}, false, false},
+		{"offset page with more", pageRequest{Limit: 10, UseOffset: true}, true, true},
+	}
+	for _, test := range tests {
+		response := gin.H{}
+		test.page.addPagination(response, 1, test.hasMore, cursor, cursor)
+		if _, ok := response["next_cursor"]; ok != test.wantNext {
+			t.Errorf("%s: next_cursor present %v, want %v", test.name, ok, test.wantNext)
+		}
+		if _, ok := response["prev_cursor"]; !ok {
+			t.Errorf("%s: prev_cursor missing", test.name)
+		}
+		if response["has_more"] != test.hasMore {
+			t.Errorf("%s: has_more %v, want %v", test.name, response["has_more"], test.hasMore)
+		}
+	}
+}
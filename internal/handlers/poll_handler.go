@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/websocket"
+	"websocket/internal/websocket/handlers/shared"
+)
+
+// maxPollEventSize matches the WebSocket read limit
+const maxPollEventSize = 1024
+
+// PollHandler serves the long-polling fallback transport
+type PollHandler struct {
+	poller *websocket.LongPoller
+}
+
+func NewPollHandler(poller *websocket.LongPoller) *PollHandler {
+	return &PollHandler{
+		poller: poller,
+	}
+}
+
+// CreateSession handles POST /poll/sessions?username=
+func (h *PollHandler) CreateSession(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		username = "anonymous"
+	}
+
+	sessionID, err := h.poller.Create(username, c.ClientIP())
+	if errors.Is(err, websocket.ErrTooManyPollSessions) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many open sessions; close one or let it expire"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"session_id":           sessionID,
+		"username":             username,
+		"idle_timeout_seconds": int(websocket.PollSessionIdle.Seconds()),
+	})
+}
+
+// Poll handles GET /poll/sessions/:id?after=&timeout=. after acknowledges every
+// event up to that seq; timeout is the wait in seconds (default 25, max 30).
+func (h *PollHandler) Poll(c *gin.Context) {
+	var after uint64
+	if raw := c.Query("after"); raw != "" {
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid after parameter"})
+			return
+		}
+		after = n
+	}
+
+	wait := websocket.DefaultPollWait
+	if raw := c.Query("timeout"); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timeout parameter"})
+			return
+		}
+		wait = time.Duration(seconds) * time.Second
+		if wait > websocket.MaxPollWait {
+			wait = websocket.MaxPollWait
+		}
+	}
+
+	events, err := h.poller.Poll(c.Request.Context(), c.Param("id"), after, wait)
+	if err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// Send handles POST /poll/sessions/:id/send with one client event as the body,
+// e.g. {"type":"JOIN_ROOM","room":"general"}. Handler errors come back in the
+// response rather than as ERROR events.
+func (h *PollHandler) Send(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPollEventSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Event too large (max 1024 bytes)"})
+		return
+	}
+
+	if err := h.poller.Send(c.Param("id"), body); err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+}
+
+// CloseSession handles DELETE /poll/sessions/:id
+func (h *PollHandler) CloseSession(c *gin.Context) {
+	if err := h.poller.Close(c.Param("id")); err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session closed"})
+}
+
+// respondSessionError maps poller and event handler errors to HTTP responses
+func (h *PollHandler) respondSessionError(c *gin.Context, err error) {
+	if errors.Is(err, websocket.ErrPollSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found or expired"})
+		return
+	}
+
+	var eventErr *shared.EventError
+	if errors.As(err, &eventErr) {
+		status := http.StatusBadRequest
+		switch eventErr.Code {
+		case shared.ErrCodeRateLimited:
+			status = http.StatusTooManyRequests
+		case shared.ErrCodeForbidden, shared.ErrCodeBanned, shared.ErrCodeMuted:
+			status = http.StatusForbidden
+		case shared.ErrCodeContentRejected:
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{"error": eventErr.Message, "code": eventErr.Code})
+		return
+	}
+
+	var validationErr *shared.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error(), "code": shared.ErrCodeInvalidEvent, "field": validationErr.Field})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
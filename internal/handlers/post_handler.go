@@ -6,51 +6,78 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
 	"websocket/internal/models"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
+	"websocket/internal/websocket"
+	"websocket/internal/websocket/handlers/comments"
+	"websocket/pkg/markdown"
 )
 
 type PostHandler struct {
-	postRepo    *repository.PostRepository
-	commentRepo *repository.CommentRepository
+	hub         *websocket.Hub
+	postRepo    repository.PostStore
+	commentRepo repository.CommentStore
+	unitOfWork  repository.UnitOfWork
+	attachments *attachments.Service
+	moderator   *moderation.Moderator
+	bus         *domain.Bus
 }
 
-func NewPostHandler(postRepo *repository.PostRepository, commentRepo *repository.CommentRepository) *PostHandler {
+func NewPostHandler(hub *websocket.Hub, postRepo repository.PostStore, commentRepo repository.CommentStore, unitOfWork repository.UnitOfWork, attachmentService *attachments.Service, moderator *moderation.Moderator, bus *domain.Bus) *PostHandler {
 	return &PostHandler{
+		hub:         hub,
 		postRepo:    postRepo,
 		commentRepo: commentRepo,
+		unitOfWork:  unitOfWork,
+		attachments: attachmentService,
+		moderator:   moderator,
+		bus:         bus,
 	}
 }
 
-// GetAllPosts retrieves all posts with pagination
+// GetAllPosts retrieves posts newest first. next_cursor (used as before) continues to
+// older posts and prev_cursor (used as after) fetches newer ones; offset is still accepted.
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+	page, ok := parsePageRequest(c, 10, 100)
+	if !ok {
 		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
-		return
+	var posts []*models.Post
+	var hasMore bool
+	var err error
+	switch {
+	case page.UseOffset:
+		posts, err = h.postRepo.GetAllPosts(page.Limit+1, page.Offset)
+		if hasMore = len(posts) > page.Limit; hasMore {
+			posts = posts[:page.Limit]
+		}
+	case page.After != nil:
+		posts, hasMore, err = h.postRepo.GetPostsAfter(page.After, page.Limit)
+	default:
+		posts, hasMore, err = h.postRepo.GetPostsBefore(page.Before, page.Limit)
 	}
-
-	posts, err := h.postRepo.GetAllPosts(limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"posts":  posts,
-		"limit":  limit,
-		"offset": offset,
-		"count":  len(posts),
-	})
+	response := gin.H{
+		"posts": posts,
+	}
+
+	var next, prev *repository.Cursor
+	if len(posts) > 0 {
+		newest, oldest := posts[0], posts[len(posts)-1]
+		next = repository.NewCursor(oldest.CreatedAt, oldest.ID)
+		prev = repository.NewCursor(newest.CreatedAt, newest.ID)
+	}
+	page.addPagination(response, len(posts), hasMore, next, prev)
+
+	respondCached(c, response)
 }
 
 // GetPostByID retrieves a single post by ID
@@ -63,7 +90,7 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"post": post})
+	respondCached(c, gin.H{"post": post})
 }
 
 // CreatePost creates a new post
@@ -95,6 +122,14 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
+	h.bus.Publish(domain.Event{
+		Type:   domain.PostCreated,
+		PostID: post.ID,
+		Post:   post,
+		Actor:  post.AuthorID,
+		Source: domain.SourceREST,
+	})
+
 	c.JSON(http.StatusCreated, gin.H{"post": post})
 }
 
@@ -131,6 +166,13 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	h.bus.Publish(domain.Event{
+		Type:   domain.PostUpdated,
+		PostID: post.ID,
+		Post:   post,
+		Source: domain.SourceREST,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"post": post})
 }
 
@@ -144,29 +186,31 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if err := h.postRepo.DeletePost(postID); err != nil {
+	// Comments, their attachments, reactions and mentions go with the post
+	removed, err := repository.DeletePostWithComments(h.unitOfWork, postID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
 		return
 	}
+	h.attachments.RemoveBlobs(removed)
+
+	h.bus.Publish(domain.Event{
+		Type:   domain.PostDeleted,
+		PostID: postID,
+		Source: domain.SourceREST,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
 }
 
-// GetCommentsByPostID retrieves all comments for a post
+// GetCommentsByPostID retrieves a page of a post's comments, oldest first. Without a cursor
+// it returns the newest page; next_cursor (used as before) scrolls back to older comments
+// and prev_cursor (used as after) catches up on newer ones. offset is still accepted.
 func (h *PostHandler) GetCommentsByPostID(c *gin.Context) {
 	postID := c.Param("id")
-	limitStr := c.DefaultQuery("limit", "50")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
-		return
-	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+	page, ok := parsePageRequest(c, 50, 100)
+	if !ok {
 		return
 	}
 
@@ -176,19 +220,39 @@ func (h *PostHandler) GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.commentRepo.GetCommentsByPostID(postID, limit, offset)
+	var comments []*models.Comment
+	var hasMore bool
+	var err error
+	switch {
+	case page.UseOffset:
+		comments, err = h.commentRepo.GetCommentsByPostID(postID, page.Limit+1, page.Offset)
+		if hasMore = len(comments) > page.Limit; hasMore {
+			comments = comments[:page.Limit]
+		}
+	case page.After != nil:
+		comments, hasMore, err = h.commentRepo.GetCommentsAfter(postID, page.After, page.Limit)
+	default:
+		comments, hasMore, err = h.commentRepo.GetCommentsBefore(postID, page.Before, page.Limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"comments": comments,
 		"post_id":  postID,
-		"limit":    limit,
-		"offset":   offset,
-		"count":    len(comments),
-	})
+	}
+
+	var next, prev *repository.Cursor
+	if len(comments) > 0 {
+		oldest, newest := comments[0], comments[len(comments)-1]
+		next = repository.NewCursor(oldest.CreatedAt, oldest.ID)
+		prev = repository.NewCursor(newest.CreatedAt, newest.ID)
+	}
+	page.addPagination(response, len(comments), hasMore, next, prev)
+
+	respondCached(c, response)
 }
 
 // GetRecentCommentsByPostID retrieves recent comments for a post
@@ -214,13 +278,206 @@ func (h *PostHandler) GetRecentCommentsByPostID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondCached(c, gin.H{
 		"comments": comments,
 		"post_id":  postID,
 		"count":    len(comments),
 	})
 }
 
+// Comment tree defaults and caps
+const (
+	defaultTreeDepth    = 3
+	maxTreeDepth        = 10
+	defaultRepliesLimit = 5
+	maxRepliesLimit     = 50
+)
+
+// GetCommentTree returns a page of top-level comments with nested replies.
+// depth counts tree levels including the top level; replies_limit caps replies per node.
+func (h *PostHandler) GetCommentTree(c *gin.Context) {
+	postID := c.Param("id")
+
+	limit, offset, depth, repliesLimit, ok := parseTreeParams(c)
+	if !ok {
+		return
+	}
+
+	// Check if post exists
+	if _, err := h.postRepo.GetPostByID(postID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	roots, total, err := h.commentRepo.GetTopLevelComments(postID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	tree, err := h.commentRepo.GetCommentTree(roots, depth, repliesLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch replies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": tree,
+		"post_id":  postID,
+		"limit":    limit,
+		"offset":   offset,
+		"depth":    depth,
+		"count":    len(tree),
+		"total":    total,
+		"has_more": offset+len(tree) < total,
+	})
+}
+
+// GetCommentReplies pages through the replies of a single comment thread
+func (h *PostHandler) GetCommentReplies(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+
+	limit, offset, depth, repliesLimit, ok := parseTreeParams(c)
+	if !ok {
+		return
+	}
+
+	parent, err := h.commentRepo.GetCommentByID(commentID)
+	if err != nil || parent.PostID != postID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	replies, total, err := h.commentRepo.GetReplies(commentID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch replies"})
+		return
+	}
+
+	tree, err := h.commentRepo.GetCommentTree(replies, depth, repliesLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch replies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replies":   tree,
+		"post_id":   postID,
+		"parent_id": commentID,
+		"limit":     limit,
+		"offset":    offset,
+		"depth":     depth,
+		"count":     len(tree),
+		"total":     total,
+		"has_more":  offset+len(tree) < total,
+	})
+}
+
+// parseTreeParams reads pagination and tree shape parameters, writing a 400 on bad input
+func parseTreeParams(c *gin.Context) (limit, offset, depth, repliesLimit int, ok bool) {
+	params := []struct {
+		name  string
+		value *int
+		def   int
+		min   int
+		max   int
+	}{
+		{"limit", &limit, 20, 1, 100},
+		{"offset", &offset, 0, 0, -1},
+		{"depth", &depth, defaultTreeDepth, 1, maxTreeDepth},
+		{"replies_limit", &repliesLimit, defaultRepliesLimit, 0, maxRepliesLimit},
+	}
+
+	for _, p := range params {
+		*p.value = p.def
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < p.min {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name + " parameter"})
+			return 0, 0, 0, 0, false
+		}
+		if p.max >= 0 && n > p.max {
+			n = p.max
+		}
+		*p.value = n
+	}
+
+	return limit, offset, depth, repliesLimit, true
+}
+
+// UpdateComment edits any comment for an admin and notifies post subscribers.
+// Authors edit their own comments over WebSocket, where the connection identifies them.
+func (h *PostHandler) UpdateComment(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+
+	var req struct {
+		Content string `json:"content" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.Content) > 2000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment too long (max 2000 characters)"})
+		return
+	}
+
+	comment, err := h.commentRepo.GetCommentByID(commentID)
+	if err != nil || comment.PostID != postID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	verdict := h.moderator.CheckComment(req.Content)
+	if rejection := comments.EditRejection(verdict); rejection != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": rejection.Message, "code": rejection.Code})
+		return
+	}
+
+	comment.Content = verdict.Content
+	comment.ContentHTML = markdown.Render(verdict.Content)
+	if err := h.commentRepo.UpdateComment(comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	h.hub.BroadcastToPostSubscribers(postID, comments.NewCommentUpdatedEvent(comment))
+
+	c.JSON(http.StatusOK, gin.H{"comment": comment})
+}
+
+// DeleteComment deletes any comment and its replies for an admin and notifies post
+// subscribers. Authors delete their own comments over WebSocket.
+func (h *PostHandler) DeleteComment(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+	deletedBy := c.DefaultQuery("username", "admin")
+
+	comment, err := h.commentRepo.GetCommentByID(commentID)
+	if err != nil || comment.PostID != postID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	removed, err := repository.DeletePostComment(h.unitOfWork, comment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+	h.attachments.RemoveBlobs(removed)
+
+	h.hub.BroadcastToPostSubscribers(postID, comments.NewCommentDeletedEvent(comment, deletedBy))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
 func generateID() string {
 	return time.Now().Format("20060102150405") + "-" + randomString(6)
 }
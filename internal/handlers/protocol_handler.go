@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"websocket/pkg/protocol"
+)
+
+// ProtocolHandler serves the machine-readable WebSocket protocol: an AsyncAPI
+// document and a JSON Schema per event, all generated from pkg/protocol
+type ProtocolHandler struct{}
+
+func NewProtocolHandler() *ProtocolHandler {
+	return &ProtocolHandler{}
+}
+
+// GetDocument handles GET /protocol with the AsyncAPI document
+func (h *ProtocolHandler) GetDocument(c *gin.Context) {
+	c.JSON(http.StatusOK, protocol.AsyncAPI(c.Request.Host))
+}
+
+// ListSchemas handles GET /protocol/schemas
+func (h *ProtocolHandler) ListSchemas(c *gin.Context) {
+	schemas := make([]gin.H, 0, len(protocol.Events))
+	for _, spec := range protocol.Events {
+		name := protocol.SchemaName(spec)
+		schemas = append(schemas, gin.H{
+			"name":      name,
+			"type":      spec.Type,
+			"direction": spec.Direction,
+			"url":       "/api/v1/protocol/schemas/" + name,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schemas": schemas,
+		"count":   len(schemas),
+	})
+}
+
+// GetSchema handles GET /protocol/schemas/:name, where name is e.g.
+// client.JOIN_ROOM, or just the event type when only one side sends it
+func (h *ProtocolHandler) GetSchema(c *gin.Context) {
+	name := c.Param("name")
+
+	var matches []protocol.EventSpec
+	for _, spec := range protocol.Events {
+		if protocol.SchemaName(spec) == name {
+			matches = []protocol.EventSpec{spec}
+			break
+		}
+		if spec.Type == name {
+			matches = append(matches, spec)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown event type"})
+	case 1:
+		c.JSON(http.StatusOK, protocol.EventSchema(matches[0]))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Both sides send " + name + "; ask for client." + name + " or server." + name,
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/retention"
+)
+
+// RetentionHandler exposes the message retention purger to admins
+type RetentionHandler struct {
+	purger *retention.Purger
+}
+
+func NewRetentionHandler(purger *retention.Purger) *RetentionHandler {
+	return &RetentionHandler{
+		purger: purger,
+	}
+}
+
+// GetStatus returns the active retention configuration and purge metrics
+func (h *RetentionHandler) GetStatus(c *gin.Context) {
+	config := h.purger.Config()
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": config != nil,
+		"config":  config,
+		"metrics": h.purger.Metrics(),
+	})
+}
+
+// Preview reports what a retention configuration would delete, without deleting
+// anything. The body is a configuration to try; without one the active
+// configuration is previewed.
+func (h *RetentionHandler) Preview(c *gin.Context) {
+	config := h.purger.Config()
+
+	var posted retention.Config
+	err := c.ShouldBindJSON(&posted)
+	switch {
+	case errors.Is(err, io.EOF):
+		if config == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Retention is not configured; post a configuration to preview it"})
+			return
+		}
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	default:
+		if err := posted.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		config = &posted
+	}
+
+	preview, err := h.purger.Preview(config)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview retention"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dry_run": true,
+		"config":  config,
+		"preview": preview,
+	})
+}
+
+// RunNow applies the active retention configuration immediately
+func (h *RetentionHandler) RunNow(c *gin.Context) {
+	if h.purger.Config() == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Retention is not configured"})
+		return
+	}
+
+	report, err := h.purger.RunOnce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Retention purge failed",
+			"report": report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
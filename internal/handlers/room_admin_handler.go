@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// RoomAdminHandler manages who may moderate each room. Bans and mutes
+// themselves are issued by those moderators over the WebSocket.
+type RoomAdminHandler struct {
+	roomModerationRepo *repository.RoomModerationRepository
+}
+
+func NewRoomAdminHandler(roomModerationRepo *repository.RoomModerationRepository) *RoomAdminHandler {
+	return &RoomAdminHandler{
+		roomModerationRepo: roomModerationRepo,
+	}
+}
+
+// SetRoleRequest grants a room role
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required"` // "owner" or "moderator"
+}
+
+// GetRoles lists a room's owners and moderators
+func (h *RoomAdminHandler) GetRoles(c *gin.Context) {
+	room := c.Param("room")
+
+	roles, err := h.roomModerationRepo.ListRoles(room)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch room roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room":  room,
+		"roles": roles,
+		"count": len(roles),
+	})
+}
+
+// SetRole makes a user an owner or moderator of a room (?username= names the admin)
+func (h *RoomAdminHandler) SetRole(c *gin.Context) {
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Role != models.RoomRoleOwner && req.Role != models.RoomRoleModerator {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be owner or moderator"})
+		return
+	}
+
+	role := &models.RoomRole{
+		RoomID:    c.Param("room"),
+		Username:  c.Param("username"),
+		Role:      req.Role,
+		GrantedBy: c.DefaultQuery("username", "admin"),
+		CreatedAt: time.Now(),
+	}
+	if err := h.roomModerationRepo.SetRole(role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set room role"})
+		return
+	}
+
+	log.Printf("🛡️ %s made %s %s of room %s", role.GrantedBy, role.Username, role.Role, role.RoomID)
+	c.JSON(http.StatusOK, gin.H{"role": role})
+}
+
+// RemoveRole makes a user a regular member of a room again
+func (h *RoomAdminHandler) RemoveRole(c *gin.Context) {
+	room, username := c.Param("room"), c.Param("username")
+
+	removed, err := h.roomModerationRepo.RemoveRole(room, username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove room role"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User has no role in this room"})
+		return
+	}
+
+	log.Printf("🛡️ Removed %s's role in room %s", username, room)
+	c.JSON(http.StatusOK, gin.H{"message": "Room role removed"})
+}
+
+// GetSanctions lists a room's active bans and mutes
+func (h *RoomAdminHandler) GetSanctions(c *gin.Context) {
+	room := c.Param("room")
+
+	sanctions, err := h.roomModerationRepo.ListActiveSanctions(room)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch room sanctions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room":      room,
+		"sanctions": sanctions,
+		"count":     len(sanctions),
+	})
+}
@@ -7,7 +7,7 @@ import (
 	"websocket/internal/websocket"
 )
 
-func SetupRoutes(hub *websocket.Hub, messageRepo *repository.MessageRepository) *gin.Engine {
+func SetupRoutes(hub *websocket.Hub, messageRepo repository.MessageStore) *gin.Engine {
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// SearchHandler serves full-text search over messages, posts and comments
+type SearchHandler struct {
+	searchRepo *repository.SearchRepository
+}
+
+func NewSearchHandler(searchRepo *repository.SearchRepository) *SearchHandler {
+	return &SearchHandler{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search handles GET /search?q=&scope=&room=&author=&from=&to=&limit=&offset=&username=.
+// Messages from rooms username is banned from are left out.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := models.SearchQuery{
+		Text:   c.Query("q"),
+		Scope:  c.Query("scope"),
+		RoomID: c.Query("room"),
+		Author: c.Query("author"),
+		Viewer: c.Query("username"),
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		if query.From, err = repository.ParseSearchDate(from, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter"})
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = repository.ParseSearchDate(to, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter"})
+			return
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if query.Limit, err = strconv.Atoi(limitStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if query.Offset, err = strconv.Atoi(offsetStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+			return
+		}
+	}
+
+	if err := repository.NormalizeSearchQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.searchRepo.Search(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   query.Text,
+		"scope":   query.Scope,
+		"results": results,
+		"count":   len(results),
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+	})
+}
@@ -4,22 +4,23 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 
+	"websocket/internal/models"
 	"websocket/internal/repository"
 	"websocket/internal/websocket"
 	"websocket/internal/websocket/handlers/comments"
 	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/markdown"
 
 	"github.com/gin-gonic/gin"
 )
 
 type SimpleChatHandler struct {
 	hub         *websocket.Hub
-	messageRepo *repository.MessageRepository
+	messageRepo repository.MessageStore
 }
 
-func NewSimpleChatHandler(hub *websocket.Hub, messageRepo *repository.MessageRepository) *SimpleChatHandler {
+func NewSimpleChatHandler(hub *websocket.Hub, messageRepo repository.MessageStore) *SimpleChatHandler {
 	return &SimpleChatHandler{
 		hub:         hub,
 		messageRepo: messageRepo,
@@ -50,24 +51,36 @@ func (h *SimpleChatHandler) HandleWebSocket(c *gin.Context) {
 	h.hub.HandleWebSocket(c)
 }
 
-// GetRecentMessages returns recent messages for a room from database
+// GetRecentMessages returns a page of messages for a room from database.
+// Without a cursor it returns the newest messages; next_cursor (used as before) scrolls
+// back in time and prev_cursor (used as after) catches up on newer messages.
+// Pages are always oldest first. Passing offset switches to legacy offset paging.
 func (h *SimpleChatHandler) GetRecentMessages(c *gin.Context) {
 	room := c.Param("room")
 	if room == "" {
 		room = c.DefaultQuery("room", "general")
 	}
 
-	limitStr := c.DefaultQuery("limit", "50")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 50
-	}
-	if limit > 100 {
-		limit = 100 // Cap at 100 messages
+	page, ok := parsePageRequest(c, 50, 100) // Cap at 100 messages
+	if !ok {
+		return
 	}
 
 	// Fetch messages from database
-	messages, err := h.messageRepo.GetRecentMessagesByRoom(room, limit)
+	var messages []*models.Message
+	var hasMore bool
+	var err error
+	switch {
+	case page.UseOffset:
+		messages, err = h.messageRepo.GetMessagesByRoom(room, page.Limit+1, page.Offset)
+		if hasMore = len(messages) > page.Limit; hasMore {
+			messages = messages[:page.Limit]
+		}
+	case page.After != nil:
+		messages, hasMore, err = h.messageRepo.GetMessagesAfter(room, page.After, page.Limit)
+	default:
+		messages, hasMore, err = h.messageRepo.GetMessagesBefore(room, page.Before, page.Limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to fetch messages from database",
@@ -76,12 +89,21 @@ func (h *SimpleChatHandler) GetRecentMessages(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"messages": messages,
 		"room":     room,
-		"count":    len(messages),
 		"note":     "Messages fetched from database",
-	})
+	}
+
+	var next, prev *repository.Cursor
+	if len(messages) > 0 {
+		oldest, newest := messages[0], messages[len(messages)-1]
+		next = repository.NewCursor(oldest.Timestamp, oldest.ID)
+		prev = repository.NewCursor(newest.Timestamp, newest.ID)
+	}
+	page.addPagination(response, len(messages), hasMore, next, prev)
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetStats returns simple hub statistics
@@ -138,10 +160,11 @@ func (h *SimpleChatHandler) SendTestComment(c *gin.Context) {
 	user := c.DefaultQuery("user", "system")
 
 	event := &comments.PostCommentEvent{
-		Type:    websocket.EventPostComment,
-		PostID:  postID,
-		User:    user,
-		Comment: comment,
+		Type:        websocket.EventPostComment,
+		PostID:      postID,
+		User:        user,
+		Comment:     comment,
+		ContentHTML: markdown.Render(comment),
 	}
 
 	h.hub.BroadcastToPostSubscribers(postID, event)
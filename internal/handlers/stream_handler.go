@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/repository"
+	"websocket/internal/websocket"
+	"websocket/internal/websocket/handlers/rooms"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/shared"
+)
+
+// maxStreamTargets caps how many rooms and how many posts one stream may follow
+const maxStreamTargets = 20
+
+// StreamHandler serves the read-only Server-Sent Events transport
+type StreamHandler struct {
+	hub                *websocket.Hub
+	postRepo           repository.PostStore
+	roomModerationRepo *repository.RoomModerationRepository
+	roomValidator      *rooms.Validator
+}
+
+func NewStreamHandler(hub *websocket.Hub, postRepo repository.PostStore, roomModerationRepo *repository.RoomModerationRepository) *StreamHandler {
+	return &StreamHandler{
+		hub:                hub,
+		postRepo:           postRepo,
+		roomModerationRepo: roomModerationRepo,
+		roomValidator:      rooms.NewValidator(),
+	}
+}
+
+// Stream handles GET /stream?rooms=a,b&posts=x,y&username=. Resumes from the
+// Last-Event-ID header, or ?last_event_id= for clients that can't set headers.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		username = "anonymous"
+	}
+
+	roomNames := splitList(c.Query("rooms"))
+	postIDs := splitList(c.Query("posts"))
+	if len(roomNames) == 0 && len(postIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rooms or posts is required"})
+		return
+	}
+	if len(roomNames) > maxStreamTargets || len(postIDs) > maxStreamTargets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many rooms or posts (max 20 each)"})
+		return
+	}
+
+	// Same checks as JOIN_ROOM: valid names and no active ban
+	for _, room := range roomNames {
+		if err := h.roomValidator.ValidateJoinRoom(&rooms.JoinRoomEvent{Room: room}); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := sanctions.CheckBanned(h.roomModerationRepo, room, username); err != nil {
+			var eventErr *shared.EventError
+			if errors.As(err, &eventErr) {
+				c.JSON(http.StatusForbidden, gin.H{"error": eventErr.Message, "code": eventErr.Code})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check room access"})
+			return
+		}
+	}
+
+	for _, postID := range postIDs {
+		if _, err := h.postRepo.GetPostByID(postID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found: " + postID})
+			return
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	h.hub.ServeSSE(c, username, roomNames, postIDs, lastEventID)
+}
+
+// splitList parses a comma-separated query value, dropping blanks and duplicates
+func splitList(value string) []string {
+	items := []string{}
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !seen[item] {
+			items = append(items, item)
+			seen[item] = true
+		}
+	}
+	return items
+}
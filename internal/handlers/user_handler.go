@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/repository"
+)
+
+// UserHandler serves per-user endpoints under /me. Like the WebSocket endpoint,
+// the caller identifies themselves with the username query parameter.
+type UserHandler struct {
+	readReceiptRepo *repository.ReadReceiptRepository
+	mentionRepo     *repository.MentionRepository
+}
+
+func NewUserHandler(readReceiptRepo *repository.ReadReceiptRepository, mentionRepo *repository.MentionRepository) *UserHandler {
+	return &UserHandler{
+		readReceiptRepo: readReceiptRepo,
+		mentionRepo:     mentionRepo,
+	}
+}
+
+// GetUnread returns unread message counts for every room the user belongs to
+func (h *UserHandler) GetUnread(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username query parameter is required"})
+		return
+	}
+
+	counts, err := h.readReceiptRepo.GetUnreadCounts(username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unread counts"})
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count.Unread
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"username": username,
+		"rooms":    counts,
+		"total":    total,
+	})
+}
+
+// GetMentions returns the user's mentions newest first; pass next_cursor as before for older ones
+func (h *UserHandler) GetMentions(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username query parameter is required"})
+		return
+	}
+
+	page, ok := parsePageRequest(c, 20, 100)
+	if !ok {
+		return
+	}
+	if page.After != nil || page.UseOffset {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mentions only page backwards with before"})
+		return
+	}
+
+	mentions, hasMore, err := h.mentionRepo.GetMentions(username, page.Before, page.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mentions"})
+		return
+	}
+
+	response := gin.H{
+		"username": username,
+		"mentions": mentions,
+	}
+
+	var next *repository.Cursor
+	if len(mentions) > 0 {
+		oldest := mentions[len(mentions)-1]
+		next = repository.NewCursor(oldest.CreatedAt, oldest.SourceID)
+	}
+	page.addPagination(response, len(mentions), hasMore, next, nil)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,15 @@
+package models
+
+import "websocket/pkg/protocol"
+
+// Attachment target types
+const (
+	AttachmentTargetMessage = "message"
+	AttachmentTargetComment = "comment"
+)
+
+// AttachmentURLPrefix is where attachments are downloaded from
+const AttachmentURLPrefix = protocol.AttachmentURLPrefix
+
+// Attachment is an uploaded file, defined in pkg/protocol because events carry it
+type Attachment = protocol.Attachment
@@ -0,0 +1,12 @@
+package models
+
+import "websocket/pkg/protocol"
+
+// Mention source types
+const (
+	MentionSourceMessage = "message"
+	MentionSourceComment = "comment"
+)
+
+// Mention records that a user was @mentioned, defined in pkg/protocol because events carry it
+type Mention = protocol.Mention
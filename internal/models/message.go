@@ -1,20 +1,11 @@
 package models
 
-import "time"
-
-type Message struct {
-	ID        string    `json:"id" db:"id"`
-	Username  string    `json:"username" db:"username"`
-	Content   string    `json:"content" db:"content"`
-	RoomID    string    `json:"room_id" db:"room_id"`
-	Type      string    `json:"type" db:"type"` // "message", "join", "leave"
-	Timestamp time.Time `json:"timestamp" db:"timestamp"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-}
+import "websocket/pkg/protocol"
 
+// Message is a chat message, defined in pkg/protocol because events carry it
+type Message = protocol.Message
 
 type Client struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
 }
-
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Moderation queue target types
+const (
+	ModerationTargetMessage = "message"
+	ModerationTargetComment = "comment"
+)
+
+// Moderation queue statuses
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
+// ModerationItem is a quarantined message or comment waiting for a moderator.
+// Approving it publishes the content as if it had just been sent.
+type ModerationItem struct {
+	ID            string     `json:"id" db:"id"`
+	TargetType    string     `json:"target_type" db:"target_type"`       // "message" or "comment"
+	RoomID        string     `json:"room,omitempty" db:"room_id"`        // Set for messages
+	PostID        string     `json:"post_id,omitempty" db:"post_id"`     // Set for comments
+	ParentID      string     `json:"parent_id,omitempty" db:"parent_id"` // Set for replies
+	Author        string     `json:"author" db:"author"`                 // Sender username
+	Content       string     `json:"content" db:"content"`               // Content as it would be published
+	AttachmentIDs []string   `json:"attachment_ids,omitempty" db:"-"`    // Uploads to attach on approval
+	Reason        string     `json:"reason" db:"reason"`                 // Why the content was held
+	Filter        string     `json:"filter" db:"filter"`                 // Filter that held it
+	Status        string     `json:"status" db:"status"`                 // "pending", "approved" or "rejected"
+	ReviewedBy    string     `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	PublishedID   string     `json:"published_id,omitempty" db:"published_id"` // Message or comment ID once approved
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
@@ -1,24 +1,24 @@
 package models
 
-import "time"
+import "websocket/pkg/protocol"
 
-type Post struct {
-	ID          string    `json:"id" db:"id"`
-	Title       string    `json:"title" db:"title"`
-	Content     string    `json:"content" db:"content"`
-	AuthorID    string    `json:"author_id" db:"author_id"`
-	AuthorName  string    `json:"author_name" db:"author_name"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-	CommentCount int      `json:"comment_count" db:"comment_count"`
+// Post and Comment are defined in pkg/protocol because events carry them
+type (
+	Post    = protocol.Post
+	Comment = protocol.Comment
+)
+
+// CommentNode is a comment with a page of its nested replies
+type CommentNode struct {
+	*Comment
+	Replies        []*CommentNode `json:"replies"`
+	ReplyCount     int            `json:"reply_count"`      // Total direct replies
+	HasMoreReplies bool           `json:"has_more_replies"` // Replies holds fewer than ReplyCount
 }
 
-type Comment struct {
-	ID        string    `json:"id" db:"id"`
-	PostID    string    `json:"post_id" db:"post_id"`
-	Content   string    `json:"content" db:"content"`
-	AuthorID  string    `json:"author_id" db:"author_id"`
-	AuthorName string   `json:"author_name" db:"author_name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+// CommentCountDrift is a post whose stored comment count disagrees with its comments
+type CommentCountDrift struct {
+	PostID string `json:"post_id"`
+	Stored int    `json:"stored"` // posts.comment_count before reconciliation
+	Actual int    `json:"actual"` // Rows in comments, replies included
+}
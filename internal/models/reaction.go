@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"websocket/pkg/protocol"
+)
+
+// Reaction target types
+const (
+	ReactionTargetMessage = "message"
+	ReactionTargetComment = "comment"
+)
+
+// Reaction is a single user's emoji reaction on a message or comment
+type Reaction struct {
+	TargetType string    `json:"target_type" db:"target_type"` // "message" or "comment"
+	TargetID   string    `json:"target_id" db:"target_id"`
+	Username   string    `json:"username" db:"username"`
+	Emoji      string    `json:"emoji" db:"emoji"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReactionSummary aggregates reactions of one emoji on a target, defined in
+// pkg/protocol because events carry it
+type ReactionSummary = protocol.ReactionSummary
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ReadState is how far a user has read in a room. A row also marks the user as a room member.
+type ReadState struct {
+	Username          string    `json:"username" db:"username"`
+	RoomID            string    `json:"room_id" db:"room_id"`
+	LastReadMessageID string    `json:"last_read_message_id,omitempty" db:"last_read_message_id"`
+	LastReadAt        time.Time `json:"last_read_at,omitempty" db:"last_read_at"` // Timestamp of the last read message
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UnreadCount is the number of unseen messages for a user in one room
+type UnreadCount struct {
+	RoomID            string `json:"room"`
+	Unread            int    `json:"unread"`
+	LastReadMessageID string `json:"last_read_message_id,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Room roles; members without a role row are regular members
+const (
+	RoomRoleOwner     = "owner"
+	RoomRoleModerator = "moderator"
+)
+
+// Room sanction kinds
+const (
+	SanctionBan  = "ban"
+	SanctionMute = "mute"
+)
+
+// RoomRole grants a user moderation powers in one room
+type RoomRole struct {
+	RoomID    string    `json:"room" db:"room_id"`
+	Username  string    `json:"username" db:"username"`
+	Role      string    `json:"role" db:"role"` // "owner" or "moderator"
+	GrantedBy string    `json:"granted_by" db:"granted_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// RoomSanction is an active ban or mute of a user in a room
+type RoomSanction struct {
+	RoomID    string     `json:"room" db:"room_id"`
+	Username  string     `json:"username" db:"username"`
+	Kind      string     `json:"kind" db:"kind"` // "ban" or "mute"
+	Reason    string     `json:"reason,omitempty" db:"reason"`
+	IssuedBy  string     `json:"issued_by" db:"issued_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"` // Nil for permanent bans
+}
+
+// RoleRank orders roles so moderators can only act on users they outrank
+func RoleRank(role string) int {
+	switch role {
+	case RoomRoleOwner:
+		return 2
+	case RoomRoleModerator:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"websocket/pkg/protocol"
+)
+
+// Search scopes
+const (
+	SearchScopeAll      = "all"
+	SearchScopeMessages = "messages"
+	SearchScopePosts    = "posts"
+	SearchScopeComments = "comments"
+)
+
+// Search result types
+const (
+	SearchResultMessage = "message"
+	SearchResultPost    = "post"
+	SearchResultComment = "comment"
+)
+
+// SearchQuery describes a full-text search and its filters
+type SearchQuery struct {
+	Text   string    // User-entered search text
+	Scope  string    // One of the SearchScope constants
+	RoomID string    // Restricts message results to a room
+	Author string    // Username (messages) or author ID/name (posts and comments)
+	From   time.Time // Inclusive lower bound on creation time (zero = unbounded)
+	To     time.Time // Inclusive upper bound on creation time (zero = unbounded)
+	Viewer string    // Who is searching; messages from rooms they are banned from are left out
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a single ranked hit, defined in pkg/protocol because events carry it
+type SearchResult = protocol.SearchResult
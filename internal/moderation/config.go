@@ -0,0 +1,98 @@
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// PipelineConfig configures the built-in filters for one scope. Filters with no
+// settings (no words, links allowed, caps_ratio 0) are left out of the pipeline.
+type PipelineConfig struct {
+	BlockedWords   []string `json:"blocked_words"`
+	WordAction     string   `json:"word_action"` // Default "mask"
+	BlockLinks     bool     `json:"block_links"`
+	AllowedDomains []string `json:"allowed_domains"`
+	LinkAction     string   `json:"link_action"`      // Default "reject"
+	CapsRatio      float64  `json:"caps_ratio"`       // 0 disables the caps check
+	CapsMinLetters int      `json:"caps_min_letters"` // Default 10
+	CapsAction     string   `json:"caps_action"`      // Default "mask"
+}
+
+// Config is the moderation configuration file. Rooms listed under rooms use their
+// own pipeline instead of the default; comments use comments when it is set.
+type Config struct {
+	Default  PipelineConfig            `json:"default"`
+	Comments *PipelineConfig           `json:"comments,omitempty"`
+	Rooms    map[string]PipelineConfig `json:"rooms,omitempty"`
+}
+
+// DefaultConfig is used when no configuration file is given: it only tames shouting
+func DefaultConfig() *Config {
+	return &Config{
+		Default: PipelineConfig{
+			CapsRatio:      0.8,
+			CapsMinLetters: 10,
+			CapsAction:     "mask",
+		},
+	}
+}
+
+// LoadConfig reads a JSON configuration file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Build turns a pipeline configuration into a pipeline
+func (c PipelineConfig) Build() (*Pipeline, error) {
+	var filters []Filter
+
+	if len(c.BlockedWords) > 0 {
+		action, err := parseActionOr(c.WordAction, Mask)
+		if err != nil {
+			return nil, fmt.Errorf("word_action: %w", err)
+		}
+		filters = append(filters, NewWordListFilter(c.BlockedWords, action))
+	}
+
+	if c.BlockLinks {
+		action, err := parseActionOr(c.LinkAction, Reject)
+		if err != nil {
+			return nil, fmt.Errorf("link_action: %w", err)
+		}
+		filters = append(filters, NewLinkFilter(c.AllowedDomains, action))
+	}
+
+	if c.CapsRatio > 0 {
+		if c.CapsRatio > 1 {
+			return nil, fmt.Errorf("caps_ratio must be between 0 and 1")
+		}
+		action, err := parseActionOr(c.CapsAction, Mask)
+		if err != nil {
+			return nil, fmt.Errorf("caps_action: %w", err)
+		}
+		minLetters := c.CapsMinLetters
+		if minLetters <= 0 {
+			minLetters = 10
+		}
+		filters = append(filters, NewCapsFilter(c.CapsRatio, minLetters, action))
+	}
+
+	return NewPipeline(filters...), nil
+}
+
+func parseActionOr(value string, fallback Action) (Action, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return ParseAction(value)
+}
@@ -0,0 +1,200 @@
+package moderation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// leetReplacements maps look-alike characters to the letters they usually stand for
+var leetReplacements = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'l',
+}
+
+// WordListFilter matches whole words against a block list after lowercasing and
+// undoing common leetspeak substitutions, so "b4dw0rd" matches "badword"
+type WordListFilter struct {
+	words  map[string]bool
+	action Action
+}
+
+// NewWordListFilter creates a filter that applies action to any blocked word
+func NewWordListFilter(words []string, action Action) *WordListFilter {
+	f := &WordListFilter{
+		words:  make(map[string]bool, len(words)),
+		action: action,
+	}
+	for _, word := range words {
+		if normalized := normalizeWord([]rune(strings.TrimSpace(word))); normalized != "" {
+			f.words[normalized] = true
+		}
+	}
+	return f
+}
+
+func (f *WordListFilter) Name() string { return "word_list" }
+
+func (f *WordListFilter) Check(content string) Verdict {
+	if len(f.words) == 0 {
+		return Verdict{Action: Allow, Content: content}
+	}
+
+	runes := []rune(content)
+	matched := false
+	for _, span := range wordSpans(runes) {
+		if !f.words[normalizeWord(runes[span[0]:span[1]])] {
+			continue
+		}
+		matched = true
+		if f.action != Mask {
+			break
+		}
+		for i := span[0]; i < span[1]; i++ {
+			runes[i] = '*'
+		}
+	}
+
+	if !matched {
+		return Verdict{Action: Allow, Content: content}
+	}
+	return Verdict{Action: f.action, Content: string(runes), Reason: "content contains blocked words"}
+}
+
+// wordSpans returns [start, end) rune offsets of each word. Leetspeak symbols count
+// as part of a word; ! and | only when another word character follows.
+func wordSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range runes {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '$'
+		if !inWord && (r == '!' || r == '|') && start >= 0 && i+1 < len(runes) {
+			next := runes[i+1]
+			inWord = unicode.IsLetter(next) || unicode.IsDigit(next)
+		}
+
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
+
+func normalizeWord(runes []rune) string {
+	var b strings.Builder
+	for _, r := range runes {
+		if replacement, ok := leetReplacements[r]; ok {
+			r = replacement
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// linkPattern finds URLs with a scheme or a leading www.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()\[\]"']+`)
+
+// LinkFilter blocks links except to allowed domains (and their subdomains)
+type LinkFilter struct {
+	allowed []string
+	action  Action
+}
+
+// NewLinkFilter creates a filter that applies action to links outside allowedDomains
+func NewLinkFilter(allowedDomains []string, action Action) *LinkFilter {
+	f := &LinkFilter{action: action}
+	for _, domain := range allowedDomains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			f.allowed = append(f.allowed, strings.TrimPrefix(domain, "."))
+		}
+	}
+	return f
+}
+
+func (f *LinkFilter) Name() string { return "links" }
+
+func (f *LinkFilter) Check(content string) Verdict {
+	matched := false
+	masked := linkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		if f.isAllowed(link) {
+			return link
+		}
+		matched = true
+		return "[link removed]"
+	})
+
+	if !matched {
+		return Verdict{Action: Allow, Content: content}
+	}
+	return Verdict{Action: f.action, Content: masked, Reason: "links are not allowed here"}
+}
+
+func (f *LinkFilter) isAllowed(link string) bool {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range f.allowed {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// CapsFilter flags shouting: content with at least minLetters letters of which
+// at least ratio are upper case. Masking lowercases the content.
+type CapsFilter struct {
+	ratio      float64
+	minLetters int
+	action     Action
+}
+
+// NewCapsFilter creates a caps-lock ratio filter
+func NewCapsFilter(ratio float64, minLetters int, action Action) *CapsFilter {
+	return &CapsFilter{
+		ratio:      ratio,
+		minLetters: minLetters,
+		action:     action,
+	}
+}
+
+func (f *CapsFilter) Name() string { return "caps" }
+
+func (f *CapsFilter) Check(content string) Verdict {
+	letters, upper := 0, 0
+	for _, r := range content {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	if letters == 0 || letters < f.minLetters || float64(upper)/float64(letters) < f.ratio {
+		return Verdict{Action: Allow, Content: content}
+	}
+	return Verdict{Action: f.action, Content: strings.ToLower(content), Reason: "too many capital letters"}
+}
@@ -0,0 +1,181 @@
+package moderation
+
+import "testing"
+
+func TestWordListFilter(t *testing.T) {
+	filter := NewWordListFilter([]string{"badword", " Spam ", "h3ck"}, Mask)
+
+	tests := []struct {
+		name    string
+		in      string
+		action  Action
+		content string
+	}{
+		{"clean", "a perfectly fine sentence", Allow, "a perfectly fine sentence"},
+		{"exact", "this is a badword here", Mask, "this is a ******* here"},
+		{"case", "BadWord", Mask, "*******"},
+		{"list entries are normalized", "no SPAM please", Mask, "no **** please"},
+		{"leet digits", "b4dw0rd", Mask, "*******"},
+		{"leet symbols", "b@dword and $pam", Mask, "******* and ****"},
+		{"leet in the list", "heck", Mask, "****"},
+		{"inner bang is a letter", "sp!m sp4m", Mask, "sp!m ****"},
+		{"pipe as l", "badword |ol", Mask, "******* |ol"},
+		{"trailing punctuation", "badword! spam.", Mask, "*******! ****."},
+		{"whole words only", "badwords and spammer", Allow, "badwords and spammer"},
+		{"every occurrence", "spam, spam and spam", Mask, "****, **** and ****"},
+		{"unicode around", "héllo badword wörld", Mask, "héllo ******* wörld"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := filter.Check(tt.in)
+			if verdict.Action != tt.action || verdict.Content != tt.content {
+				t.Errorf("Check(%q) = %v %q, want %v %q", tt.in, verdict.Action, verdict.Content, tt.action, tt.content)
+			}
+			if (verdict.Action == Allow) != (verdict.Reason == "") {
+				t.Errorf("Check(%q) reason %q does not match action %v", tt.in, verdict.Reason, verdict.Action)
+			}
+		})
+	}
+}
+
+func TestWordListFilterKeepsContentUnlessMasking(t *testing.T) {
+	verdict := NewWordListFilter([]string{"badword"}, Quarantine).Check("a b4dword")
+	if verdict.Action != Quarantine || verdict.Content != "a b4dword" {
+		t.Errorf("got %v %q, want the content held unchanged", verdict.Action, verdict.Content)
+	}
+
+	verdict = NewWordListFilter(nil, Reject).Check("anything")
+	if verdict.Action != Allow {
+		t.Errorf("an empty list returned %v", verdict.Action)
+	}
+}
+
+func TestLinkFilter(t *testing.T) {
+	filter := NewLinkFilter([]string{"example.com", " .Docs.Org "}, Mask)
+
+	tests := []struct {
+		name    string
+		in      string
+		action  Action
+		content string
+	}{
+		{"no links", "just text about example.com", Allow, "just text about example.com"},
+		{"http", "see http://evil.com/x", Mask, "see [link removed]"},
+		{"https uppercase", "HTTPS://EVIL.COM", Mask, "[link removed]"},
+		{"www without scheme", "go to www.evil.com now", Mask, "go to [link removed] now"},
+		{"allowed domain", "https://example.com/page", Allow, "https://example.com/page"},
+		{"allowed subdomain", "https://blog.example.com", Allow, "https://blog.example.com"},
+		{"allowed with leading dot", "www.docs.org/guide", Allow, "www.docs.org/guide"},
+		{"look-alike suffix", "https://notexample.com", Mask, "[link removed]"},
+		{"allowed name as subdomain", "https://example.com.evil.com", Mask, "[link removed]"},
+		{"credentials trick", "https://example.com@evil.com", Mask, "[link removed]"},
+		{"mixed", "https://example.com and http://evil.com", Mask, "https://example.com and [link removed]"},
+		{"markdown link", "[x](http://evil.com)", Mask, "[x]([link removed])"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := filter.Check(tt.in)
+			if verdict.Action != tt.action || verdict.Content != tt.content {
+				t.Errorf("Check(%q) = %v %q, want %v %q", tt.in, verdict.Action, verdict.Content, tt.action, tt.content)
+			}
+		})
+	}
+}
+
+func TestCapsFilter(t *testing.T) {
+	filter := NewCapsFilter(0.8, 10, Mask)
+
+	tests := []struct {
+		name    string
+		in      string
+		action  Action
+		content string
+	}{
+		{"normal", "Hello there, how are you?", Allow, "Hello there, how are you?"},
+		{"shouting", "STOP SHOUTING AT ME", Mask, "stop shouting at me"},
+		{"too short to judge", "OK LOL", Allow, "OK LOL"},
+		{"digits and symbols ignored", "WOW!!! 12345 AMAZING", Mask, "wow!!! 12345 amazing"},
+		{"just under the ratio", "ABCDEFGhij", Allow, "ABCDEFGhij"},
+		{"at the ratio", "ABCDEFGHij", Mask, "abcdefghij"},
+		{"non-latin", "ПРИВЕТ ВСЕМ ДРУЗЬЯ", Mask, "привет всем друзья"},
+		{"no letters", "1234567890 !!!", Allow, "1234567890 !!!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := filter.Check(tt.in)
+			if verdict.Action != tt.action || verdict.Content != tt.content {
+				t.Errorf("Check(%q) = %v %q, want %v %q", tt.in, verdict.Action, verdict.Content, tt.action, tt.content)
+			}
+		})
+	}
+}
+
+func TestPipelineStrictestVerdictWins(t *testing.T) {
+	words := NewWordListFilter([]string{"badword"}, Mask)
+	holdWords := NewWordListFilter([]string{"suspicious"}, Quarantine)
+	links := NewLinkFilter(nil, Reject)
+	caps := NewCapsFilter(0.8, 10, Mask)
+
+	tests := []struct {
+		name     string
+		pipeline *Pipeline
+		in       string
+		action   Action
+		content  string
+		filter   string
+	}{
+		{"nil pipeline", nil, "anything", Allow, "anything", ""},
+		{"nothing matches", NewPipeline(words, links, caps), "hello", Allow, "hello", ""},
+		{"masks accumulate", NewPipeline(words, caps), "THIS BADWORD IS LOUD", Mask, "this ******* is loud", "word_list"},
+		{"quarantine beats mask", NewPipeline(words, holdWords), "badword suspicious", Quarantine, "******* suspicious", "word_list"},
+		{"mask after quarantine keeps quarantine", NewPipeline(holdWords, words), "suspicious badword", Quarantine, "suspicious *******", "word_list"},
+		{"reject beats quarantine", NewPipeline(holdWords, links), "suspicious http://x.com", Reject, "suspicious http://x.com", "links"},
+		{"reject keeps earlier masks", NewPipeline(words, links), "badword http://x.com", Reject, "******* http://x.com", "links"},
+		{"reject stops the pipeline", NewPipeline(links, words), "http://x.com badword", Reject, "http://x.com badword", "links"},
+		{"filters see masked content", NewPipeline(words, NewWordListFilter([]string{"*******"}, Reject)), "badword", Mask, "*******", "word_list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := tt.pipeline.Run(tt.in)
+			if verdict.Action != tt.action || verdict.Content != tt.content || verdict.Filter != tt.filter {
+				t.Errorf("Run(%q) = %v %q by %q, want %v %q by %q",
+					tt.in, verdict.Action, verdict.Content, verdict.Filter, tt.action, tt.content, tt.filter)
+			}
+		})
+	}
+}
+
+func TestPipelineConfigBuild(t *testing.T) {
+	pipeline, err := PipelineConfig{
+		BlockedWords: []string{"badword"},
+		WordAction:   "quarantine",
+		BlockLinks:   true,
+		CapsRatio:    0.5,
+	}.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(pipeline.filters) != 3 {
+		t.Fatalf("built %d filters, want 3", len(pipeline.filters))
+	}
+	if verdict := pipeline.Run("b4dword"); verdict.Action != Quarantine {
+		t.Errorf("word_action quarantine gave %v", verdict.Action)
+	}
+	if verdict := pipeline.Run("www.x.com"); verdict.Action != Reject {
+		t.Errorf("links default to reject, got %v", verdict.Action)
+	}
+
+	for _, config := range []PipelineConfig{
+		{BlockedWords: []string{"x"}, WordAction: "delete"},
+		{CapsRatio: 1.5},
+		{BlockLinks: true, LinkAction: "ban"},
+	} {
+		if _, err := config.Build(); err == nil {
+			t.Errorf("Build(%+v) accepted an invalid config", config)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package moderation runs user content through a configurable pipeline of filters
+// before it is stored and broadcast. Each filter can allow, mask, quarantine or
+// reject the content; the strictest outcome wins.
+package moderation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action is a filter's decision about a piece of content, ordered by severity
+type Action int
+
+const (
+	// Allow passes the content through unchanged
+	Allow Action = iota
+	// Mask passes the content through with the offending parts replaced
+	Mask
+	// Quarantine holds the content for a moderator instead of publishing it
+	Quarantine
+	// Reject refuses the content outright
+	Reject
+)
+
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "allow"
+	case Mask:
+		return "mask"
+	case Quarantine:
+		return "quarantine"
+	case Reject:
+		return "reject"
+	default:
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+}
+
+// ParseAction converts a config value such as "mask" into an Action
+func ParseAction(value string) (Action, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "allow":
+		return Allow, nil
+	case "mask":
+		return Mask, nil
+	case "quarantine":
+		return Quarantine, nil
+	case "reject":
+		return Reject, nil
+	default:
+		return Allow, fmt.Errorf("unknown moderation action %q (use allow, mask, quarantine or reject)", value)
+	}
+}
+
+// Verdict is the outcome of moderating one piece of content
+type Verdict struct {
+	Action  Action
+	Content string // Content to publish; differs from the input when masked
+	Reason  string // Human-readable reason, empty when allowed
+	Filter  string // Name of the filter that decided the action
+}
+
+// Filter inspects content and returns a verdict about it
+type Filter interface {
+	Name() string
+	Check(content string) Verdict
+}
+
+// Pipeline runs filters in order. Masks are applied before the next filter runs,
+// a reject stops the pipeline, and the most severe action is returned.
+type Pipeline struct {
+	filters []Filter
+}
+
+// NewPipeline creates a pipeline from filters, run in the given order
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+// Run moderates content. A nil or empty pipeline allows everything.
+func (p *Pipeline) Run(content string) Verdict {
+	result := Verdict{Action: Allow, Content: content}
+	if p == nil {
+		return result
+	}
+
+	for _, filter := range p.filters {
+		verdict := filter.Check(result.Content)
+		verdict.Filter = filter.Name()
+
+		switch verdict.Action {
+		case Reject:
+			verdict.Content = result.Content
+			return verdict
+		case Quarantine:
+			if result.Action < Quarantine {
+				result.Action, result.Reason, result.Filter = Quarantine, verdict.Reason, verdict.Filter
+			}
+		case Mask:
+			result.Content = verdict.Content
+			if result.Action < Mask {
+				result.Action, result.Reason, result.Filter = Mask, verdict.Reason, verdict.Filter
+			}
+		}
+	}
+
+	return result
+}
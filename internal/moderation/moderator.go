@@ -0,0 +1,91 @@
+package moderation
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// Moderator picks the pipeline for a room or for comments and keeps the review queue
+type Moderator struct {
+	defaultPipeline  *Pipeline
+	commentsPipeline *Pipeline
+	roomPipelines    map[string]*Pipeline
+	queue            *repository.ModerationRepository
+}
+
+// NewModerator builds the pipelines described by config
+func NewModerator(config *Config, queue *repository.ModerationRepository) (*Moderator, error) {
+	defaultPipeline, err := config.Default.Build()
+	if err != nil {
+		return nil, fmt.Errorf("default moderation pipeline: %w", err)
+	}
+
+	m := &Moderator{
+		defaultPipeline:  defaultPipeline,
+		commentsPipeline: defaultPipeline,
+		roomPipelines:    make(map[string]*Pipeline, len(config.Rooms)),
+		queue:            queue,
+	}
+
+	if config.Comments != nil {
+		if m.commentsPipeline, err = config.Comments.Build(); err != nil {
+			return nil, fmt.Errorf("comments moderation pipeline: %w", err)
+		}
+	}
+
+	for room, roomConfig := range config.Rooms {
+		if m.roomPipelines[room], err = roomConfig.Build(); err != nil {
+			return nil, fmt.Errorf("moderation pipeline for room %s: %w", room, err)
+		}
+	}
+
+	return m, nil
+}
+
+// NewModeratorFromEnv loads the JSON file named by MODERATION_CONFIG, or uses
+// DefaultConfig when it is unset
+func NewModeratorFromEnv(queue *repository.ModerationRepository) (*Moderator, error) {
+	config := DefaultConfig()
+	if path := os.Getenv("MODERATION_CONFIG"); path != "" {
+		var err error
+		if config, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+		log.Printf("🛡️ Loaded moderation config from %s (%d room overrides)", path, len(config.Rooms))
+	}
+	return NewModerator(config, queue)
+}
+
+// CheckMessage moderates a chat message for room
+func (m *Moderator) CheckMessage(room, content string) Verdict {
+	pipeline, ok := m.roomPipelines[room]
+	if !ok {
+		pipeline = m.defaultPipeline
+	}
+	return pipeline.Run(content)
+}
+
+// CheckComment moderates a post comment
+func (m *Moderator) CheckComment(content string) Verdict {
+	return m.commentsPipeline.Run(content)
+}
+
+// Hold puts quarantined content in the review queue
+func (m *Moderator) Hold(item *models.ModerationItem, verdict Verdict) error {
+	item.ID = fmt.Sprintf("mod_%d", time.Now().UnixNano())
+	item.Content = verdict.Content
+	item.Reason = verdict.Reason
+	item.Filter = verdict.Filter
+
+	if err := m.queue.Enqueue(item); err != nil {
+		return err
+	}
+
+	log.Printf("🛡️ Held %s from %s for review (%s): %s", item.TargetType, item.Author, item.Filter, item.ID)
+	return nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+const attachmentColumns = `id, filename, content_type, size, width, height, storage_key, thumbnail_key,
+	uploaded_by, COALESCE(target_type, ''), COALESCE(target_id, ''), created_at`
+
+// ErrAttachmentNotFound is returned when an attachment ID does not exist
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+type AttachmentRepository struct {
+	db *database.DB
+}
+
+func NewAttachmentRepository(db *database.DB) *AttachmentRepository {
+	return &AttachmentRepository{
+		db: db,
+	}
+}
+
+// CreateAttachment records an uploaded, not yet attached file
+func (r *AttachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, filename, content_type, size, width, height, storage_key, thumbnail_key, uploaded_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.Exec(query,
+		attachment.ID,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Width,
+		attachment.Height,
+		attachment.StorageKey,
+		attachment.ThumbnailKey,
+		attachment.UploadedBy,
+		attachment.CreatedAt.Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	attachment.FillURLs()
+	return nil
+}
+
+// GetAttachmentByID loads a single attachment
+func (r *AttachmentRepository) GetAttachmentByID(id string) (*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = ?`
+
+	attachment, err := scanAttachment(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// GetPendingAttachments loads attachments that uploader may still attach, in the order of ids.
+// It fails if any ID is unknown, belongs to someone else, or is already attached.
+func (r *AttachmentRepository) GetPendingAttachments(ids []string, uploader string) ([]*models.Attachment, error) {
+	attachments := make([]*models.Attachment, 0, len(ids))
+	for _, id := range ids {
+		attachment, err := r.GetAttachmentByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s not found", id)
+		}
+		if attachment.UploadedBy != uploader {
+			return nil, fmt.Errorf("attachment %s was uploaded by another user", id)
+		}
+		if attachment.TargetID != "" {
+			return nil, fmt.Errorf("attachment %s is already in use", id)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// AttachTo links pending attachments to a message or comment. Either all of
+// them are linked or, when one is already in use, none is. Claim them before
+// saving the message or comment, and Release them if saving fails, so a stored
+// row never misses its attachments.
+func (r *AttachmentRepository) AttachTo(attachments []*models.Attachment, targetType, targetID string) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE attachments
+		SET target_type = ?, target_id = ?
+		WHERE id = ? AND uploaded_by = ? AND target_id IS NULL
+	`
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		for _, attachment := range attachments {
+			result, err := tx.Exec(query, targetType, targetID, attachment.ID, attachment.UploadedBy)
+			if err != nil {
+				return fmt.Errorf("failed to attach %s: %w", attachment.ID, err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to attach %s: %w", attachment.ID, err)
+			}
+			if affected == 0 {
+				return fmt.Errorf("attachment %s is already in use", attachment.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		attachment.TargetType = targetType
+		attachment.TargetID = targetID
+	}
+	return nil
+}
+
+// Release returns attachments claimed by AttachTo to their uploader, for when
+// the message or comment they were claimed for could not be saved
+func (r *AttachmentRepository) Release(attachments []*models.Attachment) error {
+	query := `
+		UPDATE attachments
+		SET target_type = NULL, target_id = NULL
+		WHERE id = ? AND target_type = ? AND target_id = ?
+	`
+
+	for _, attachment := range attachments {
+		if _, err := r.db.Exec(query, attachment.ID, attachment.TargetType, attachment.TargetID); err != nil {
+			return fmt.Errorf("failed to release %s: %w", attachment.ID, err)
+		}
+		attachment.TargetType = ""
+		attachment.TargetID = ""
+	}
+	return nil
+}
+
+// loadAttachments fetches attachments for many targets of the same type in one query
+func loadAttachments(db database.Querier, targetType string, targetIDs []string) (map[string][]*models.Attachment, error) {
+	attachments := make(map[string][]*models.Attachment)
+	if len(targetIDs) == 0 {
+		return attachments, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(targetIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM attachments
+		WHERE target_type = ? AND target_id IN (%s)
+		ORDER BY created_at ASC, id ASC
+	`, attachmentColumns, placeholders)
+
+	args := make([]interface{}, 0, len(targetIDs)+1)
+	args = append(args, targetType)
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments[attachment.TargetID] = append(attachments[attachment.TargetID], attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	var createdAtStr string
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Width,
+		&attachment.Height,
+		&attachment.StorageKey,
+		&attachment.ThumbnailKey,
+		&attachment.UploadedBy,
+		&attachment.TargetType,
+		&attachment.TargetID,
+		&createdAtStr,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan attachment: %w", err)
+	}
+
+	if attachment.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	attachment.FillURLs()
+	return attachment, nil
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"websocket/internal/models"
@@ -9,7 +11,7 @@ import (
 )
 
 type CommentRepository struct {
-	db *database.DB
+	db database.Querier
 }
 
 func NewCommentRepository(db *database.DB) *CommentRepository {
@@ -18,10 +20,50 @@ func NewCommentRepository(db *database.DB) *CommentRepository {
 	}
 }
 
+// commentColumns is the column list every comment query selects, in scanComment order
+const commentColumns = `id, post_id, parent_id, content, COALESCE(content_html, ''), author_id, author_name, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{}
+	var parentID sql.NullString
+	var createdAtStr, updatedAtStr string
+
+	err := row.Scan(
+		&comment.ID,
+		&comment.PostID,
+		&parentID,
+		&comment.Content,
+		&comment.ContentHTML,
+		&comment.AuthorID,
+		&comment.AuthorName,
+		&createdAtStr,
+		&updatedAtStr,
+	)
+	if err != nil {
+		return nil, err
+	}
+	comment.ParentID = parentID.String
+
+	// Parse timestamps
+	if comment.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if comment.UpdatedAt, err = parseFlexibleTimestamp(updatedAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+
+	return comment, nil
+}
+
 func (r *CommentRepository) CreateComment(comment *models.Comment) error {
 	query := `
-		INSERT INTO comments (id, post_id, content, author_id, author_name, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO comments (id, post_id, parent_id, content, content_html, author_id, author_name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
@@ -35,7 +77,9 @@ func (r *CommentRepository) CreateComment(comment *models.Comment) error {
 	_, err := r.db.Exec(query,
 		comment.ID,
 		comment.PostID,
+		sql.NullString{String: comment.ParentID, Valid: comment.ParentID != ""},
 		comment.Content,
+		comment.ContentHTML,
 		comment.AuthorID,
 		comment.AuthorName,
 		comment.CreatedAt.Format("2006-01-02 15:04:05"),
@@ -49,103 +93,33 @@ func (r *CommentRepository) CreateComment(comment *models.Comment) error {
 	return nil
 }
 
+// GetCommentsByPostID pages through a post's comments oldest first using LIMIT/OFFSET.
+// Kept for offset-based clients; prefer GetCommentsAfter/GetCommentsBefore.
 func (r *CommentRepository) GetCommentsByPostID(postID string, limit, offset int) ([]*models.Comment, error) {
 	query := `
-		SELECT id, post_id, content, author_id, author_name, created_at, updated_at
-		FROM comments 
+		SELECT ` + commentColumns + `
+		FROM comments
 		WHERE post_id = ?
-		ORDER BY created_at ASC
+		ORDER BY created_at ASC, id ASC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.Query(query, postID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query comments: %w", err)
-	}
-	defer rows.Close()
-
-	var comments []*models.Comment
-	for rows.Next() {
-		comment := &models.Comment{}
-		var createdAtStr, updatedAtStr string
-
-		err := rows.Scan(
-			&comment.ID,
-			&comment.PostID,
-			&comment.Content,
-			&comment.AuthorID,
-			&comment.AuthorName,
-			&createdAtStr,
-			&updatedAtStr,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan comment: %w", err)
-		}
-
-		// Parse timestamps
-		if comment.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
-		}
-		if comment.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
-			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
-		}
-
-		comments = append(comments, comment)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate comments: %w", err)
-	}
-
-	return comments, nil
+	return r.queryComments(query, postID, limit, offset)
 }
 
+// GetRecentCommentsByPostID returns a post's newest comments, oldest first
 func (r *CommentRepository) GetRecentCommentsByPostID(postID string, limit int) ([]*models.Comment, error) {
 	query := `
-		SELECT id, post_id, content, author_id, author_name, created_at, updated_at
-		FROM comments 
+		SELECT ` + commentColumns + `
+		FROM comments
 		WHERE post_id = ?
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT ?
 	`
 
-	rows, err := r.db.Query(query, postID, limit)
+	comments, err := r.queryComments(query, postID, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query recent comments: %w", err)
-	}
-	defer rows.Close()
-
-	var comments []*models.Comment
-	for rows.Next() {
-		comment := &models.Comment{}
-		var createdAtStr, updatedAtStr string
-
-		err := rows.Scan(
-			&comment.ID,
-			&comment.PostID,
-			&comment.Content,
-			&comment.AuthorID,
-			&comment.AuthorName,
-			&createdAtStr,
-			&updatedAtStr,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan comment: %w", err)
-		}
-
-		// Parse timestamps
-		if comment.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
-		}
-		if comment.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
-			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
-		}
-
-		comments = append(comments, comment)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate recent comments: %w", err)
+		return nil, err
 	}
 
 	// Reverse to get chronological order (oldest first)
@@ -158,8 +132,8 @@ func (r *CommentRepository) GetRecentCommentsByPostID(postID string, limit int)
 
 func (r *CommentRepository) UpdateComment(comment *models.Comment) error {
 	query := `
-		UPDATE comments 
-		SET content = ?, updated_at = ?
+		UPDATE comments
+		SET content = ?, content_html = ?, updated_at = ?
 		WHERE id = ?
 	`
 
@@ -167,6 +141,7 @@ func (r *CommentRepository) UpdateComment(comment *models.Comment) error {
 
 	_, err := r.db.Exec(query,
 		comment.Content,
+		comment.ContentHTML,
 		comment.UpdatedAt.Format("2006-01-02 15:04:05"),
 		comment.ID,
 	)
@@ -178,48 +153,418 @@ func (r *CommentRepository) UpdateComment(comment *models.Comment) error {
 	return nil
 }
 
-func (r *CommentRepository) DeleteComment(id string) error {
-	query := `DELETE FROM comments WHERE id = ?`
+// DeleteComment deletes a comment together with all of its replies and their
+// attachment records; their reactions and mentions go with them by trigger. It
+// returns how many comments were removed and the removed attachments, whose
+// blobs the caller should delete once the deletion is committed.
+func (r *CommentRepository) DeleteComment(id string) (int, []*models.Attachment, error) {
+	// Outside a unit of work, the thread and its attachments still go together
+	if db, ok := r.db.(*database.DB); ok {
+		var deleted int
+		var attachments []*models.Attachment
+		err := db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			deleted, attachments, err = (&CommentRepository{db: tx}).DeleteComment(id)
+			return err
+		})
+		return deleted, attachments, err
+	}
+
+	return r.deleteSelected(`
+		WITH RECURSIVE thread(id) AS (
+			SELECT ?
+			UNION ALL
+			SELECT c.id FROM comments c JOIN thread t ON c.parent_id = t.id
+		)
+		SELECT id FROM comments WHERE id IN (SELECT id FROM thread)
+	`, id)
+}
+
+// DeleteCommentsByPostID deletes every comment on a post with their attachment
+// records, like DeleteComment does for one thread
+func (r *CommentRepository) DeleteCommentsByPostID(postID string) (int, []*models.Attachment, error) {
+	if db, ok := r.db.(*database.DB); ok {
+		var deleted int
+		var attachments []*models.Attachment
+		err := db.Transaction(func(tx *sql.Tx) error {
+			var err error
+			deleted, attachments, err = (&CommentRepository{db: tx}).DeleteCommentsByPostID(postID)
+			return err
+		})
+		return deleted, attachments, err
+	}
+
+	return r.deleteSelected(`SELECT id FROM comments WHERE post_id = ?`, postID)
+}
+
+// deleteSelected deletes the comments whose IDs query selects, and their attachment records
+func (r *CommentRepository) deleteSelected(query string, args ...interface{}) (int, []*models.Attachment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to select comments: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var commentID string
+		if err := rows.Scan(&commentID); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan comment id: %w", err)
+		}
+		ids = append(ids, commentID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	byComment, err := loadAttachments(r.db, models.AttachmentTargetComment, ids)
+	if err != nil {
+		return 0, nil, err
+	}
+	var attachments []*models.Attachment
+	for _, commentID := range ids {
+		attachments = append(attachments, byComment[commentID]...)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	idArgs := make([]interface{}, 0, len(ids))
+	for _, commentID := range ids {
+		idArgs = append(idArgs, commentID)
+	}
 
-	_, err := r.db.Exec(query, id)
+	if _, err := r.db.Exec(`DELETE FROM attachments WHERE target_type = ? AND target_id IN (`+placeholders+`)`,
+		append([]interface{}{models.AttachmentTargetComment}, idArgs...)...); err != nil {
+		return 0, nil, fmt.Errorf("failed to delete comment attachments: %w", err)
+	}
+
+	result, err := r.db.Exec(`DELETE FROM comments WHERE id IN (`+placeholders+`)`, idArgs...)
 	if err != nil {
-		return fmt.Errorf("failed to delete comment: %w", err)
+		return 0, nil, fmt.Errorf("failed to delete comment: %w", err)
 	}
 
-	return nil
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return int(deleted), attachments, nil
 }
 
 func (r *CommentRepository) GetCommentByID(id string) (*models.Comment, error) {
 	query := `
-		SELECT id, post_id, content, author_id, author_name, created_at, updated_at
-		FROM comments 
+		SELECT ` + commentColumns + `
+		FROM comments
 		WHERE id = ?
 	`
 
-	comment := &models.Comment{}
-	var createdAtStr, updatedAtStr string
+	comments, err := r.queryComments(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("failed to get comment: %w", sql.ErrNoRows)
+	}
 
-	err := r.db.QueryRow(query, id).Scan(
-		&comment.ID,
-		&comment.PostID,
-		&comment.Content,
-		&comment.AuthorID,
-		&comment.AuthorName,
-		&createdAtStr,
-		&updatedAtStr,
-	)
+	return comments[0], nil
+}
 
+// GetTopLevelComments returns a page of a post's root comments (those that are not replies)
+func (r *CommentRepository) GetTopLevelComments(postID string, limit, offset int) ([]*models.Comment, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM comments WHERE post_id = ? AND parent_id IS NULL`, postID).Scan(&total)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comment: %w", err)
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
 	}
 
-	// Parse timestamps
-	if comment.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
-		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE post_id = ? AND parent_id IS NULL
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	comments, err := r.queryComments(query, postID, limit, offset)
+	if err != nil {
+		return nil, 0, err
 	}
-	if comment.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
-		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+
+	return comments, total, nil
+}
+
+// GetReplies returns a page of direct replies to a comment
+func (r *CommentRepository) GetReplies(parentID string, limit, offset int) ([]*models.Comment, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM comments WHERE parent_id = ?`, parentID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count replies: %w", err)
 	}
 
-	return comment, nil
-}
\ No newline at end of file
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE parent_id = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	comments, err := r.queryComments(query, parentID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// GetCommentsBefore returns up to limit comments on a post older than before (or the
+// newest comments when before is nil), oldest first. The bool reports whether older comments remain.
+func (r *CommentRepository) GetCommentsBefore(postID string, before *Cursor, limit int) ([]*models.Comment, bool, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE post_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+	args := []interface{}{postID, limit + 1}
+	if before != nil {
+		query = `
+			SELECT ` + commentColumns + `
+			FROM comments
+			WHERE post_id = ? AND (created_at < ? OR (created_at = ? AND id < ?))
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		`
+		args = []interface{}{postID, before.sortKey(), before.sortKey(), before.ID, limit + 1}
+	}
+
+	comments, err := r.queryComments(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	// Reverse to get chronological order (oldest first)
+	for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+		comments[i], comments[j] = comments[j], comments[i]
+	}
+
+	return comments, hasMore, nil
+}
+
+// GetCommentsAfter returns up to limit comments on a post newer than after (or the
+// oldest comments when after is nil), oldest first. The bool reports whether newer comments remain.
+func (r *CommentRepository) GetCommentsAfter(postID string, after *Cursor, limit int) ([]*models.Comment, bool, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE post_id = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`
+	args := []interface{}{postID, limit + 1}
+	if after != nil {
+		query = `
+			SELECT ` + commentColumns + `
+			FROM comments
+			WHERE post_id = ? AND (created_at > ? OR (created_at = ? AND id > ?))
+			ORDER BY created_at ASC, id ASC
+			LIMIT ?
+		`
+		args = []interface{}{postID, after.sortKey(), after.sortKey(), after.ID, limit + 1}
+	}
+
+	comments, err := r.queryComments(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	return comments, hasMore, nil
+}
+
+// GetCommentTree nests up to depth levels of replies under the given root comments.
+// Each node carries at most repliesLimit replies; ReplyCount and HasMoreReplies let
+// clients page the rest of a thread through GetReplies.
+func (r *CommentRepository) GetCommentTree(roots []*models.Comment, depth, repliesLimit int) ([]*models.CommentNode, error) {
+	nodes := make([]*models.CommentNode, len(roots))
+	level := make(map[string]*models.CommentNode, len(roots))
+	for i, comment := range roots {
+		nodes[i] = &models.CommentNode{Comment: comment, Replies: []*models.CommentNode{}}
+		level[comment.ID] = nodes[i]
+	}
+
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		parentIDs := make([]string, 0, len(level))
+		for id := range level {
+			parentIDs = append(parentIDs, id)
+		}
+
+		// The last level only needs counts, not the replies themselves
+		limit := repliesLimit
+		if d == depth {
+			limit = 0
+		}
+
+		replies, totals, err := r.getRepliesForParents(parentIDs, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		next := make(map[string]*models.CommentNode, len(replies))
+		for id, node := range level {
+			node.ReplyCount = totals[id]
+		}
+		for _, reply := range replies {
+			parent := level[reply.ParentID]
+			child := &models.CommentNode{Comment: reply, Replies: []*models.CommentNode{}}
+			parent.Replies = append(parent.Replies, child)
+			next[reply.ID] = child
+		}
+		for _, node := range level {
+			node.HasMoreReplies = len(node.Replies) < node.ReplyCount
+		}
+		level = next
+	}
+
+	return nodes, nil
+}
+
+// getRepliesForParents loads the first limit replies of each parent plus per-parent reply totals
+func (r *CommentRepository) getRepliesForParents(parentIDs []string, limit int) ([]*models.Comment, map[string]int, error) {
+	totals := make(map[string]int, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return nil, totals, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(parentIDs)), ",")
+	args := make([]interface{}, 0, len(parentIDs)+1)
+	for _, id := range parentIDs {
+		args = append(args, id)
+	}
+
+	countQuery := fmt.Sprintf(`
+		SELECT parent_id, COUNT(*)
+		FROM comments
+		WHERE parent_id IN (%s)
+		GROUP BY parent_id
+	`, placeholders)
+
+	rows, err := r.db.Query(countQuery, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count replies: %w", err)
+	}
+	for rows.Next() {
+		var parentID string
+		var count int
+		if err := rows.Scan(&parentID, &count); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to scan reply count: %w", err)
+		}
+		totals[parentID] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate reply counts: %w", err)
+	}
+
+	if limit <= 0 {
+		return nil, totals, nil
+	}
+
+	// ROW_NUMBER keeps the per-thread limit in a single query
+	query := fmt.Sprintf(`
+		SELECT %s FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at ASC, id ASC) AS rn
+			FROM comments
+			WHERE parent_id IN (%s)
+		)
+		WHERE rn <= ?
+		ORDER BY created_at ASC, id ASC
+	`, commentColumns, placeholders)
+
+	replies, err := r.queryComments(query, append(args, limit)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return replies, totals, nil
+}
+
+// queryComments runs a comment SELECT and attaches reactions to the result
+func (r *CommentRepository) queryComments(query string, args ...interface{}) ([]*models.Comment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+
+	if err := r.attachReactions(comments); err != nil {
+		return nil, err
+	}
+	if err := r.attachFiles(comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// attachReactions fills in reaction summaries for a page of comments
+func (r *CommentRepository) attachReactions(comments []*models.Comment) error {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	summaries, err := loadReactionSummaries(r.db, models.ReactionTargetComment, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		comment.Reactions = summaries[comment.ID]
+	}
+	return nil
+}
+
+// attachFiles fills in attachment metadata for a page of comments
+func (r *CommentRepository) attachFiles(comments []*models.Comment) error {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	attachments, err := loadAttachments(r.db, models.AttachmentTargetComment, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		comment.Attachments = attachments[comment.ID]
+	}
+	return nil
+}
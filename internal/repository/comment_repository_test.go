@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"websocket/internal/models"
+)
+
+// Every comment read carries reaction summaries and attachment metadata
+func TestCommentReadsIncludeReactionsAndAttachments(t *testing.T) {
+	db := newTestDB(t)
+	comments := NewCommentRepository(db)
+	if err := NewPostRepository(db).CreatePost(&models.Post{ID: "p", Title: "p", Content: "p", AuthorID: "alice", AuthorName: "alice"}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b"} {
+		created := base.Add(time.Duration(i) * time.Second)
+		comment := &models.Comment{ID: id, PostID: "p", Content: id, AuthorID: "alice", AuthorName: "alice", CreatedAt: created, UpdatedAt: created}
+		if err := comments.CreateComment(comment); err != nil {
+			t.Fatalf("CreateComment: %v", err)
+		}
+	}
+
+	reaction := &models.Reaction{TargetType: models.ReactionTargetComment, TargetID: "b", Username: "bob", Emoji: "👍"}
+	if _, err := NewReactionRepository(db).AddReaction(reaction); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	attachments := NewAttachmentRepository(db)
+	attachment := &models.Attachment{ID: "att", Filename: "a.txt", ContentType: "text/plain", Size: 1, StorageKey: "att", UploadedBy: "alice"}
+	if err := attachments.CreateAttachment(attachment); err != nil {
+		t.Fatalf("CreateAttachment: %v", err)
+	}
+	if err := attachments.AttachTo([]*models.Attachment{attachment}, models.AttachmentTargetComment, "b"); err != nil {
+		t.Fatalf("AttachTo: %v", err)
+	}
+
+	describe := func(comment *models.Comment) string {
+		var files []string
+		for _, attachment := range comment.Attachments {
+			files = append(files, attachment.ID+" "+attachment.URL)
+		}
+		return fmt.Sprint(comment.ID, " ", comment.Reactions, " ", files)
+	}
+	want := "b [{👍 1 [bob]}] [att /api/v1/attachments/att]"
+
+	recent, err := comments.GetRecentCommentsByPostID("p", 10)
+	if err != nil {
+		t.Fatalf("GetRecentCommentsByPostID: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ID != "a" {
+		t.Fatalf("recent comments %v, want [a b]", recent)
+	}
+	if got := describe(recent[1]); got != want {
+		t.Errorf("recent comment %q, want %q", got, want)
+	}
+
+	comment, err := comments.GetCommentByID("b")
+	if err != nil {
+		t.Fatalf("GetCommentByID: %v", err)
+	}
+	if got := describe(comment); got != want {
+		t.Errorf("comment by ID %q, want %q", got, want)
+	}
+
+	if _, err := comments.GetCommentByID("missing"); err == nil {
+		t.Error("GetCommentByID found a missing comment")
+	}
+}
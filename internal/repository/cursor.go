@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cursor is a keyset position: the sort timestamp plus the row ID as a tiebreaker.
+// Clients only ever see it encoded, so its shape can change without breaking them.
+type Cursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+type cursorPayload struct {
+	T string `json:"t"`
+	I string `json:"i"`
+}
+
+// NewCursor returns the cursor pointing at a row
+func NewCursor(timestamp time.Time, id string) *Cursor {
+	return &Cursor{Timestamp: timestamp, ID: id}
+}
+
+// Encode returns the opaque string form handed to clients
+func (c *Cursor) Encode() string {
+	payload, _ := json.Marshal(cursorPayload{T: c.Timestamp.Format("2006-01-02 15:04:05"), I: c.ID})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// sortKey is the timestamp in the same text form the database stores
+func (c *Cursor) sortKey() string {
+	return c.Timestamp.Format("2006-01-02 15:04:05")
+}
+
+// DecodeCursor parses a cursor previously produced by Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.I == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	timestamp, err := parseFlexibleTimestamp(payload.T)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &Cursor{Timestamp: timestamp, ID: payload.I}, nil
+}
@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// CommentStore is a thread-safe in-memory repository.CommentStore
+type CommentStore struct {
+	mu       sync.RWMutex
+	comments map[string]*models.Comment
+	posts    map[string][]*models.Comment // Every comment on a post, oldest first
+	replies  map[string][]*models.Comment // Direct replies to a comment, oldest first
+}
+
+var _ repository.CommentStore = (*CommentStore)(nil)
+
+func NewCommentStore() *CommentStore {
+	return &CommentStore{
+		comments: make(map[string]*models.Comment),
+		posts:    make(map[string][]*models.Comment),
+		replies:  make(map[string][]*models.Comment),
+	}
+}
+
+func commentPosition(comment *models.Comment) position {
+	return at(comment.CreatedAt, comment.ID)
+}
+
+func copyComment(comment *models.Comment) *models.Comment {
+	clone := *comment
+	clone.Reactions = nil
+	clone.Attachments = nil
+	return &clone
+}
+
+// copyComments copies a page, returning an empty slice rather than nil like the repository
+func copyComments(comments []*models.Comment) []*models.Comment {
+	copies := make([]*models.Comment, len(comments))
+	for i, comment := range comments {
+		copies[i] = copyComment(comment)
+	}
+	return copies
+}
+
+// insertComment adds a comment to a slice kept in (created_at, id) order
+func insertComment(comments []*models.Comment, comment *models.Comment) []*models.Comment {
+	pos := commentPosition(comment)
+	i := sort.Search(len(comments), func(i int) bool { return pos.before(commentPosition(comments[i])) })
+	comments = append(comments, nil)
+	copy(comments[i+1:], comments[i:])
+	comments[i] = comment
+	return comments
+}
+
+// removeComment drops a comment from an ordered slice
+func removeComment(comments []*models.Comment, comment *models.Comment) []*models.Comment {
+	for i, candidate := range comments {
+		if candidate == comment {
+			return append(comments[:i], comments[i+1:]...)
+		}
+	}
+	return comments
+}
+
+// paginate returns comments[offset:offset+limit], clamped to the slice
+func paginate(comments []*models.Comment, limit, offset int) []*models.Comment {
+	if offset >= len(comments) {
+		return nil
+	}
+	end := len(comments)
+	if offset+limit < end {
+		end = offset + limit
+	}
+	return comments[offset:end]
+}
+
+func (s *CommentStore) CreateComment(comment *models.Comment) error {
+	now := time.Now()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = now
+	}
+	if comment.UpdatedAt.IsZero() {
+		comment.UpdatedAt = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.comments[comment.ID]; exists {
+		return fmt.Errorf("failed to create comment: %w", errDuplicateID)
+	}
+
+	stored := copyComment(comment)
+	s.comments[stored.ID] = stored
+	s.posts[stored.PostID] = insertComment(s.posts[stored.PostID], stored)
+	if stored.ParentID != "" {
+		s.replies[stored.ParentID] = insertComment(s.replies[stored.ParentID], stored)
+	}
+
+	return nil
+}
+
+func (s *CommentStore) GetCommentByID(id string) (*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, notFound("comment")
+	}
+	return copyComment(comment), nil
+}
+
+// GetCommentsByPostID pages through a post's comments oldest first using an offset
+func (s *CommentStore) GetCommentsByPostID(postID string, limit, offset int) ([]*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return copyComments(paginate(s.posts[postID], limit, offset)), nil
+}
+
+// GetRecentCommentsByPostID returns the newest limit comments, oldest first
+func (s *CommentStore) GetRecentCommentsByPostID(postID string, limit int) ([]*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comments := s.posts[postID]
+	start := 0
+	if len(comments) > limit {
+		start = len(comments) - limit
+	}
+
+	var recent []*models.Comment
+	for _, comment := range comments[start:] {
+		recent = append(recent, copyComment(comment))
+	}
+	return recent, nil
+}
+
+// GetTopLevelComments returns a page of a post's root comments (those that are not replies)
+func (s *CommentStore) GetTopLevelComments(postID string, limit, offset int) ([]*models.Comment, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var roots []*models.Comment
+	for _, comment := range s.posts[postID] {
+		if comment.ParentID == "" {
+			roots = append(roots, comment)
+		}
+	}
+
+	return copyComments(paginate(roots, limit, offset)), len(roots), nil
+}
+
+// GetReplies returns a page of direct replies to a comment
+func (s *CommentStore) GetReplies(parentID string, limit, offset int) ([]*models.Comment, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	replies := s.replies[parentID]
+	return copyComments(paginate(replies, limit, offset)), len(replies), nil
+}
+
+// GetCommentsBefore returns up to limit comments on a post older than before (or the
+// newest comments when before is nil), oldest first. The bool reports whether older comments remain.
+func (s *CommentStore) GetCommentsBefore(postID string, before *repository.Cursor, limit int) ([]*models.Comment, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comments := s.posts[postID]
+	end := len(comments)
+	if before != nil {
+		pos := at(before.Timestamp, before.ID)
+		end = sort.Search(len(comments), func(i int) bool { return !commentPosition(comments[i]).before(pos) })
+	}
+	start := 0
+	if end > limit {
+		start = end - limit
+	}
+
+	return copyComments(comments[start:end]), start > 0, nil
+}
+
+// GetCommentsAfter returns up to limit comments on a post newer than after (or the
+// oldest comments when after is nil), oldest first. The bool reports whether newer comments remain.
+func (s *CommentStore) GetCommentsAfter(postID string, after *repository.Cursor, limit int) ([]*models.Comment, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comments := s.posts[postID]
+	start := 0
+	if after != nil {
+		pos := at(after.Timestamp, after.ID)
+		start = sort.Search(len(comments), func(i int) bool { return pos.before(commentPosition(comments[i])) })
+	}
+	end := len(comments)
+	if end-start > limit {
+		end = start + limit
+	}
+
+	return copyComments(comments[start:end]), end < len(comments), nil
+}
+
+// GetCommentTree nests up to depth levels of replies under the given root comments.
+// Each node carries at most repliesLimit replies; ReplyCount and HasMoreReplies let
+// clients page the rest of a thread through GetReplies.
+func (s *CommentStore) GetCommentTree(roots []*models.Comment, depth, repliesLimit int) ([]*models.CommentNode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := make([]*models.CommentNode, len(roots))
+	level := make([]*models.CommentNode, len(roots))
+	for i, comment := range roots {
+		nodes[i] = &models.CommentNode{Comment: comment, Replies: []*models.CommentNode{}}
+		level[i] = nodes[i]
+	}
+
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		// The last level only needs counts, not the replies themselves
+		limit := repliesLimit
+		if d == depth {
+			limit = 0
+		}
+
+		var next []*models.CommentNode
+		for _, node := range level {
+			replies := s.replies[node.ID]
+			node.ReplyCount = len(replies)
+			for _, reply := range paginate(replies, limit, 0) {
+				child := &models.CommentNode{Comment: copyComment(reply), Replies: []*models.CommentNode{}}
+				node.Replies = append(node.Replies, child)
+				next = append(next, child)
+			}
+			node.HasMoreReplies = len(node.Replies) < node.ReplyCount
+		}
+		level = next
+	}
+
+	return nodes, nil
+}
+
+func (s *CommentStore) UpdateComment(comment *models.Comment) error {
+	comment.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.comments[comment.ID]; ok {
+		stored.Content = comment.Content
+		stored.ContentHTML = comment.ContentHTML
+		stored.UpdatedAt = comment.UpdatedAt
+	}
+	return nil
+}
+
+// DeleteComment deletes a comment together with all of its replies and
+// returns how many comments were removed. The memory store keeps no
+// attachments, so none are returned.
+func (s *CommentStore) DeleteComment(id string) (int, []*models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comment, ok := s.comments[id]
+	if !ok {
+		return 0, nil, nil
+	}
+	if comment.ParentID != "" {
+		s.replies[comment.ParentID] = removeComment(s.replies[comment.ParentID], comment)
+	}
+
+	deleted := 0
+	thread := []*models.Comment{comment}
+	for len(thread) > 0 {
+		current := thread[0]
+		thread = append(thread[1:], s.replies[current.ID]...)
+
+		delete(s.comments, current.ID)
+		delete(s.replies, current.ID)
+		s.posts[current.PostID] = removeComment(s.posts[current.PostID], current)
+		deleted++
+	}
+
+	return deleted, nil, nil
+}
+
+// DeleteCommentsByPostID deletes every comment on a post and returns how many
+// were removed
+func (s *CommentStore) DeleteCommentsByPostID(postID string) (int, []*models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comments := s.posts[postID]
+	for _, comment := range comments {
+		delete(s.comments, comment.ID)
+		delete(s.replies, comment.ID)
+	}
+	delete(s.posts, postID)
+
+	return len(comments), nil, nil
+}
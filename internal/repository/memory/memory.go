@@ -0,0 +1,42 @@
+// Package memory keeps messages, posts and comments in process instead of in
+// SQLite, as test doubles for the handlers. The stores order and page
+// exactly like their repository counterparts, including keyset cursors compared
+// at second precision, but they do not fill in reactions or attachments, which
+// still live in the database.
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const timestampLayout = "2006-01-02 15:04:05"
+
+// errDuplicateID mirrors the primary key violation SQLite reports
+var errDuplicateID = errors.New("duplicate id")
+
+// position is a row's place in (timestamp, id) order
+type position struct {
+	key string
+	id  string
+}
+
+// at returns the position of a row with the given sort timestamp
+func at(timestamp time.Time, id string) position {
+	return position{key: timestamp.Format(timestampLayout), id: id}
+}
+
+// before reports whether p sorts ahead of other
+func (p position) before(other position) bool {
+	if p.key != other.key {
+		return p.key < other.key
+	}
+	return p.id < other.id
+}
+
+// notFound mirrors the error a repository returns for an unknown ID
+func notFound(what string) error {
+	return fmt.Errorf("failed to get %s: %w", what, sql.ErrNoRows)
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// MessageStore is a thread-safe in-memory repository.MessageStore
+type MessageStore struct {
+	mu       sync.RWMutex
+	messages map[string]*models.Message
+	rooms    map[string][]*models.Message // Oldest first
+}
+
+var _ repository.MessageStore = (*MessageStore)(nil)
+
+func NewMessageStore() *MessageStore {
+	return &MessageStore{
+		messages: make(map[string]*models.Message),
+		rooms:    make(map[string][]*models.Message),
+	}
+}
+
+func messagePosition(message *models.Message) position {
+	return at(message.Timestamp, message.ID)
+}
+
+// copyMessage detaches a stored message from callers
+func copyMessage(message *models.Message) *models.Message {
+	clone := *message
+	clone.Reactions = nil
+	clone.Attachments = nil
+	return &clone
+}
+
+func (s *MessageStore) SaveMessage(message *models.Message) error {
+	now := time.Now()
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+	if message.Timestamp.IsZero() {
+		message.Timestamp = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.messages[message.ID]; exists {
+		return fmt.Errorf("failed to save message: %w", errDuplicateID)
+	}
+
+	stored := copyMessage(message)
+	s.messages[stored.ID] = stored
+
+	room := s.rooms[stored.RoomID]
+	pos := messagePosition(stored)
+	i := sort.Search(len(room), func(i int) bool { return pos.before(messagePosition(room[i])) })
+	room = append(room, nil)
+	copy(room[i+1:], room[i:])
+	room[i] = stored
+	s.rooms[stored.RoomID] = room
+
+	return nil
+}
+
+func (s *MessageStore) GetMessageByID(id string) (*models.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	message, ok := s.messages[id]
+	if !ok {
+		return nil, notFound("message")
+	}
+	return copyMessage(message), nil
+}
+
+// GetMessagesByRoom pages through a room oldest first using an offset
+func (s *MessageStore) GetMessagesByRoom(roomID string, limit int, offset int) ([]*models.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room := s.rooms[roomID]
+	if offset >= len(room) {
+		return nil, nil
+	}
+	end := len(room)
+	if offset+limit < end {
+		end = offset + limit
+	}
+	return copyMessages(room[offset:end]), nil
+}
+
+// GetRecentMessagesByRoom returns the newest limit messages, oldest first
+func (s *MessageStore) GetRecentMessagesByRoom(roomID string, limit int) ([]*models.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room := s.rooms[roomID]
+	start := 0
+	if len(room) > limit {
+		start = len(room) - limit
+	}
+	return copyMessages(room[start:]), nil
+}
+
+// GetMessagesBefore returns up to limit messages older than before (or the newest
+// messages when before is nil), oldest first. The bool reports whether older messages remain.
+func (s *MessageStore) GetMessagesBefore(roomID string, before *repository.Cursor, limit int) ([]*models.Message, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room := s.rooms[roomID]
+	end := len(room)
+	if before != nil {
+		pos := at(before.Timestamp, before.ID)
+		end = sort.Search(len(room), func(i int) bool { return !messagePosition(room[i]).before(pos) })
+	}
+	start := 0
+	if end > limit {
+		start = end - limit
+	}
+
+	messages := copyMessages(room[start:end])
+	if messages == nil {
+		messages = []*models.Message{}
+	}
+	return messages, start > 0, nil
+}
+
+// GetMessagesAfter returns up to limit messages newer than after (or the oldest
+// messages when after is nil), oldest first. The bool reports whether newer messages remain.
+func (s *MessageStore) GetMessagesAfter(roomID string, after *repository.Cursor, limit int) ([]*models.Message, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	room := s.rooms[roomID]
+	start := 0
+	if after != nil {
+		pos := at(after.Timestamp, after.ID)
+		start = sort.Search(len(room), func(i int) bool { return pos.before(messagePosition(room[i])) })
+	}
+	end := len(room)
+	if end-start > limit {
+		end = start + limit
+	}
+
+	messages := copyMessages(room[start:end])
+	if messages == nil {
+		messages = []*models.Message{}
+	}
+	return messages, end < len(room), nil
+}
+
+func copyMessages(messages []*models.Message) []*models.Message {
+	var copies []*models.Message
+	for _, message := range messages {
+		copies = append(copies, copyMessage(message))
+	}
+	return copies
+}
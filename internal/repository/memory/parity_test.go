@@ -0,0 +1,424 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/pkg/database"
+)
+
+// The stores stand in for the repositories in handler tests, so each test
+// seeds both with the same rows and expects the same IDs, in the same order,
+// from every read. Several rows share a second, which both sides store at
+// second precision, so ties are broken by ID.
+
+var parityBase = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// parityOffsets are deliberately out of order and collide within a second
+var parityOffsets = []time.Duration{
+	3 * time.Second,
+	0,
+	1*time.Second + 700*time.Millisecond,
+	1 * time.Second,
+	5 * time.Second,
+	1*time.Second + 200*time.Millisecond,
+	300 * time.Millisecond,
+	3*time.Second + 900*time.Millisecond,
+}
+
+// parityIDs do not follow the timestamps, so ordering by insertion or by ID alone fails
+var parityIDs = []string{"f", "c", "h", "a", "b", "g", "e", "d"}
+
+func newParityDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewMemoryDatabase()
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// walk follows cursors from an unbounded first page until no more remain and
+// returns the pages as "a,b|c,d". fetch returns a page and the cursor of the
+// row the next page continues from.
+func walk(t *testing.T, fetch func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error)) string {
+	t.Helper()
+	var pages []string
+	var cursor *repository.Cursor
+	for len(pages) < 20 {
+		ids, next, more, err := fetch(cursor)
+		if err != nil {
+			t.Fatalf("fetching page %d: %v", len(pages)+1, err)
+		}
+		pages = append(pages, strings.Join(ids, ","))
+		if !more {
+			return strings.Join(pages, "|")
+		}
+		cursor = next
+	}
+	t.Fatalf("paging did not finish: %s", strings.Join(pages, "|"))
+	return ""
+}
+
+func expectSame(t *testing.T, what string, memory, sqlite interface{}) {
+	t.Helper()
+	if fmt.Sprint(memory) != fmt.Sprint(sqlite) {
+		t.Errorf("%s: memory %v, sqlite %v", what, memory, sqlite)
+	}
+}
+
+func messageIDs(messages []*models.Message) []string {
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+	return ids
+}
+
+func postIDs(posts []*models.Post) []string {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func commentIDs(comments []*models.Comment) []string {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	return ids
+}
+
+// treeIDs flattens a comment tree as "a(b,c(d)+)", with + marking elided replies
+func treeIDs(nodes []*models.CommentNode) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.ID
+		if len(node.Replies) > 0 {
+			parts[i] += "(" + treeIDs(node.Replies) + ")"
+		}
+		if node.HasMoreReplies {
+			parts[i] += "+"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestMessageStoreParity(t *testing.T) {
+	stores := map[string]repository.MessageStore{
+		"memory": NewMessageStore(),
+		"sqlite": repository.NewMessageRepository(newParityDB(t)),
+	}
+	for name, store := range stores {
+		for i, id := range parityIDs {
+			message := &models.Message{ID: id, Username: "alice", Content: id, RoomID: "general", Type: "message", Timestamp: parityBase.Add(parityOffsets[i])}
+			if err := store.SaveMessage(message); err != nil {
+				t.Fatalf("%s SaveMessage: %v", name, err)
+			}
+		}
+		other := &models.Message{ID: "z", Username: "bob", Content: "elsewhere", RoomID: "random", Type: "message", Timestamp: parityBase}
+		if err := store.SaveMessage(other); err != nil {
+			t.Fatalf("%s SaveMessage: %v", name, err)
+		}
+	}
+
+	results := map[string][]string{}
+	for name, store := range stores {
+		var got []string
+		for offset := 0; offset < len(parityIDs); offset += 3 {
+			messages, err := store.GetMessagesByRoom("general", 3, offset)
+			if err != nil {
+				t.Fatalf("%s GetMessagesByRoom: %v", name, err)
+			}
+			got = append(got, fmt.Sprint("offset ", offset, messageIDs(messages)))
+		}
+
+		recent, err := store.GetRecentMessagesByRoom("general", 4)
+		if err != nil {
+			t.Fatalf("%s GetRecentMessagesByRoom: %v", name, err)
+		}
+		got = append(got, fmt.Sprint("recent ", messageIDs(recent)))
+
+		for _, limit := range []int{2, 3} {
+			older := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				messages, more, err := store.GetMessagesBefore("general", cursor, limit)
+				if err != nil || len(messages) == 0 {
+					return nil, nil, false, err
+				}
+				return messageIDs(messages), repository.NewCursor(messages[0].Timestamp, messages[0].ID), more, nil
+			})
+			newer := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				messages, more, err := store.GetMessagesAfter("general", cursor, limit)
+				if err != nil || len(messages) == 0 {
+					return nil, nil, false, err
+				}
+				last := messages[len(messages)-1]
+				return messageIDs(messages), repository.NewCursor(last.Timestamp, last.ID), more, nil
+			})
+			got = append(got, fmt.Sprintf("before/%d %s", limit, older), fmt.Sprintf("after/%d %s", limit, newer))
+		}
+		results[name] = got
+	}
+
+	for i := range results["memory"] {
+		expectSame(t, "messages", results["memory"][i], results["sqlite"][i])
+	}
+
+	// Spot-check the shared order itself, so both sides cannot agree on a wrong one
+	want := "before/2 f,b|h,d|a,g|c,e"
+	if got := results["sqlite"][4]; got != want {
+		t.Errorf("sqlite paging %q, want %q", got, want)
+	}
+}
+
+func TestPostStoreParity(t *testing.T) {
+	stores := map[string]repository.PostStore{
+		"memory": NewPostStore(),
+		"sqlite": repository.NewPostRepository(newParityDB(t)),
+	}
+	for name, store := range stores {
+		for i, id := range parityIDs {
+			created := parityBase.Add(parityOffsets[i])
+			post := &models.Post{ID: id, Title: id, Content: id, AuthorID: "alice", AuthorName: "alice", CreatedAt: created, UpdatedAt: created}
+			if err := store.CreatePost(post); err != nil {
+				t.Fatalf("%s CreatePost: %v", name, err)
+			}
+		}
+	}
+
+	results := map[string][]string{}
+	for name, store := range stores {
+		var got []string
+		for offset := 0; offset < len(parityIDs); offset += 3 {
+			posts, err := store.GetAllPosts(3, offset)
+			if err != nil {
+				t.Fatalf("%s GetAllPosts: %v", name, err)
+			}
+			got = append(got, fmt.Sprint("offset ", offset, postIDs(posts)))
+		}
+
+		for _, limit := range []int{2, 3} {
+			older := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				posts, more, err := store.GetPostsBefore(cursor, limit)
+				if err != nil || len(posts) == 0 {
+					return nil, nil, false, err
+				}
+				last := posts[len(posts)-1]
+				return postIDs(posts), repository.NewCursor(last.CreatedAt, last.ID), more, nil
+			})
+			newer := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				posts, more, err := store.GetPostsAfter(cursor, limit)
+				if err != nil || len(posts) == 0 {
+					return nil, nil, false, err
+				}
+				first := posts[0]
+				return postIDs(posts), repository.NewCursor(first.CreatedAt, first.ID), more, nil
+			})
+			got = append(got, fmt.Sprintf("before/%d %s", limit, older), fmt.Sprintf("after/%d %s", limit, newer))
+		}
+		results[name] = got
+	}
+
+	for i := range results["memory"] {
+		expectSame(t, "posts", results["memory"][i], results["sqlite"][i])
+	}
+
+	want := "offset 0 [b f d]"
+	if got := results["sqlite"][0]; got != want {
+		t.Errorf("sqlite first page %q, want %q", got, want)
+	}
+}
+
+func TestCommentStoreParity(t *testing.T) {
+	db := newParityDB(t)
+	stores := map[string]repository.CommentStore{
+		"memory": NewCommentStore(),
+		"sqlite": repository.NewCommentRepository(db),
+	}
+	post := &models.Post{ID: "p", Title: "p", Content: "p", AuthorID: "alice", AuthorName: "alice"}
+	if err := repository.NewPostRepository(db).CreatePost(post); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+
+	// a and c are roots; the rest reply to a, or to a's reply b
+	parents := map[string]string{"f": "a", "h": "a", "b": "a", "g": "b", "e": "b", "d": "a"}
+	order := []string{"a", "c", "b", "f", "h", "g", "e", "d"}
+	offsets := map[string]time.Duration{}
+	for i, id := range parityIDs {
+		offsets[id] = parityOffsets[i]
+	}
+	for name, store := range stores {
+		for _, id := range order {
+			created := parityBase.Add(offsets[id])
+			comment := &models.Comment{ID: id, PostID: "p", ParentID: parents[id], Content: id, AuthorID: "alice", AuthorName: "alice", CreatedAt: created, UpdatedAt: created}
+			if err := store.CreateComment(comment); err != nil {
+				t.Fatalf("%s CreateComment %s: %v", name, id, err)
+			}
+		}
+	}
+
+	results := map[string][]string{}
+	for name, store := range stores {
+		var got []string
+		for offset := 0; offset < len(order); offset += 3 {
+			comments, err := store.GetCommentsByPostID("p", 3, offset)
+			if err != nil {
+				t.Fatalf("%s GetCommentsByPostID: %v", name, err)
+			}
+			got = append(got, fmt.Sprint("offset ", offset, commentIDs(comments)))
+		}
+
+		recent, err := store.GetRecentCommentsByPostID("p", 4)
+		if err != nil {
+			t.Fatalf("%s GetRecentCommentsByPostID: %v", name, err)
+		}
+		got = append(got, fmt.Sprint("recent ", commentIDs(recent)))
+
+		roots, total, err := store.GetTopLevelComments("p", 10, 0)
+		if err != nil {
+			t.Fatalf("%s GetTopLevelComments: %v", name, err)
+		}
+		got = append(got, fmt.Sprint("roots ", commentIDs(roots), total))
+
+		replies, total, err := store.GetReplies("a", 2, 1)
+		if err != nil {
+			t.Fatalf("%s GetReplies: %v", name, err)
+		}
+		got = append(got, fmt.Sprint("replies ", commentIDs(replies), total))
+
+		tree, err := store.GetCommentTree(roots, 2, 2)
+		if err != nil {
+			t.Fatalf("%s GetCommentTree: %v", name, err)
+		}
+		got = append(got, "tree "+treeIDs(tree))
+
+		for _, limit := range []int{2, 3} {
+			older := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				comments, more, err := store.GetCommentsBefore("p", cursor, limit)
+				if err != nil || len(comments) == 0 {
+					return nil, nil, false, err
+				}
+				return commentIDs(comments), repository.NewCursor(comments[0].CreatedAt, comments[0].ID), more, nil
+			})
+			newer := walk(t, func(cursor *repository.Cursor) ([]string, *repository.Cursor, bool, error) {
+				comments, more, err := store.GetCommentsAfter("p", cursor, limit)
+				if err != nil || len(comments) == 0 {
+					return nil, nil, false, err
+				}
+				last := comments[len(comments)-1]
+				return commentIDs(comments), repository.NewCursor(last.CreatedAt, last.ID), more, nil
+			})
+			got = append(got, fmt.Sprintf("before/%d %s", limit, older), fmt.Sprintf("after/%d %s", limit, newer))
+		}
+		results[name] = got
+	}
+
+	for i := range results["memory"] {
+		expectSame(t, "comments", results["memory"][i], results["sqlite"][i])
+	}
+}
+
+func TestDeletePostParity(t *testing.T) {
+	db := newParityDB(t)
+	memoryPosts, memoryComments := NewPostStore(), NewCommentStore()
+	type side struct {
+		posts    repository.PostStore
+		comments repository.CommentStore
+		uow      repository.UnitOfWork
+	}
+	sides := map[string]side{
+		"memory": {memoryPosts, memoryComments, NewUnitOfWork(memoryPosts, memoryComments)},
+		"sqlite": {repository.NewPostRepository(db), repository.NewCommentRepository(db), repository.NewUnitOfWork(db)},
+	}
+
+	// p loses its thread; q keeps its comment
+	parents := map[string]string{"b": "a", "c": "b", "d": ""}
+	for name, s := range sides {
+		for _, id := range []string{"p", "q"} {
+			post := &models.Post{ID: id, Title: id, Content: id, AuthorID: "alice", AuthorName: "alice", CreatedAt: parityBase, UpdatedAt: parityBase}
+			if err := s.posts.CreatePost(post); err != nil {
+				t.Fatalf("%s CreatePost: %v", name, err)
+			}
+		}
+		for i, id := range []string{"a", "b", "c", "d", "e"} {
+			postID := "p"
+			if id == "e" {
+				postID = "q"
+			}
+			created := parityBase.Add(time.Duration(i) * time.Second)
+			comment := &models.Comment{ID: id, PostID: postID, ParentID: parents[id], Content: id, AuthorID: "alice", AuthorName: "alice", CreatedAt: created, UpdatedAt: created}
+			if err := repository.CreatePostComment(s.uow, comment); err != nil {
+				t.Fatalf("%s CreatePostComment %s: %v", name, id, err)
+			}
+		}
+	}
+
+	// Only the repositories keep reactions and attachments
+	reaction := &models.Reaction{TargetType: models.ReactionTargetComment, TargetID: "c", Username: "bob", Emoji: "👍"}
+	if _, err := repository.NewReactionRepository(db).AddReaction(reaction); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	attachment := &models.Attachment{ID: "att", Filename: "a.txt", ContentType: "text/plain", Size: 1, StorageKey: "att", UploadedBy: "alice"}
+	if err := attachmentRepo.CreateAttachment(attachment); err != nil {
+		t.Fatalf("CreateAttachment: %v", err)
+	}
+	if err := attachmentRepo.AttachTo([]*models.Attachment{attachment}, models.AttachmentTargetComment, "b"); err != nil {
+		t.Fatalf("AttachTo: %v", err)
+	}
+
+	results := map[string][]string{}
+	for name, s := range sides {
+		removed, err := repository.DeletePostWithComments(s.uow, "p")
+		if err != nil {
+			t.Fatalf("%s DeletePostWithComments: %v", name, err)
+		}
+		if name == "sqlite" && (len(removed) != 1 || removed[0].ID != "att") {
+			t.Errorf("sqlite removed attachments %v, want [att]", removed)
+		}
+
+		var got []string
+		_, err = s.posts.GetPostByID("p")
+		got = append(got, fmt.Sprint("post p found ", err == nil))
+		for _, id := range []string{"a", "c", "e"} {
+			_, err := s.comments.GetCommentByID(id)
+			got = append(got, fmt.Sprintf("comment %s found %v", id, err == nil))
+		}
+		for _, postID := range []string{"p", "q"} {
+			comments, err := s.comments.GetCommentsByPostID(postID, 10, 0)
+			if err != nil {
+				t.Fatalf("%s GetCommentsByPostID: %v", name, err)
+			}
+			got = append(got, fmt.Sprint(postID, " comments ", commentIDs(comments)))
+		}
+		replies, total, err := s.comments.GetReplies("a", 10, 0)
+		if err != nil {
+			t.Fatalf("%s GetReplies: %v", name, err)
+		}
+		got = append(got, fmt.Sprint("replies ", commentIDs(replies), total))
+		results[name] = got
+	}
+
+	for i := range results["memory"] {
+		expectSame(t, "delete post", results["memory"][i], results["sqlite"][i])
+	}
+	want := "p comments []"
+	if got := results["sqlite"][4]; got != want {
+		t.Errorf("sqlite %q, want %q", got, want)
+	}
+
+	var orphans int
+	if err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM reactions) + (SELECT COUNT(*) FROM attachments) + (SELECT COUNT(*) FROM comments_fts WHERE comments_fts MATCH 'c')`).Scan(&orphans); err != nil {
+		t.Fatalf("counting leftovers: %v", err)
+	}
+	if orphans != 0 {
+		t.Errorf("%d reactions, attachments or search hits outlived the post", orphans)
+	}
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// PostStore is a thread-safe in-memory repository.PostStore
+type PostStore struct {
+	mu    sync.RWMutex
+	posts map[string]*models.Post
+	order []*models.Post // Oldest first
+}
+
+var _ repository.PostStore = (*PostStore)(nil)
+
+func NewPostStore() *PostStore {
+	return &PostStore{
+		posts: make(map[string]*models.Post),
+	}
+}
+
+func postPosition(post *models.Post) position {
+	return at(post.CreatedAt, post.ID)
+}
+
+func copyPost(post *models.Post) *models.Post {
+	clone := *post
+	return &clone
+}
+
+func (s *PostStore) CreatePost(post *models.Post) error {
+	now := time.Now()
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = now
+	}
+	if post.UpdatedAt.IsZero() {
+		post.UpdatedAt = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.posts[post.ID]; exists {
+		return fmt.Errorf("failed to create post: %w", errDuplicateID)
+	}
+
+	stored := copyPost(post)
+	s.posts[stored.ID] = stored
+
+	pos := postPosition(stored)
+	i := sort.Search(len(s.order), func(i int) bool { return pos.before(postPosition(s.order[i])) })
+	s.order = append(s.order, nil)
+	copy(s.order[i+1:], s.order[i:])
+	s.order[i] = stored
+
+	return nil
+}
+
+func (s *PostStore) GetPostByID(id string) (*models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return nil, notFound("post")
+	}
+	return copyPost(post), nil
+}
+
+// GetAllPosts pages through posts newest first using an offset
+func (s *PostStore) GetAllPosts(limit, offset int) ([]*models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	end := len(s.order) - offset
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	return s.newestFirst(start, end), nil
+}
+
+// GetPostsBefore returns up to limit posts older than before (or the newest posts when
+// before is nil), newest first. The bool reports whether older posts remain.
+func (s *PostStore) GetPostsBefore(before *repository.Cursor, limit int) ([]*models.Post, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	end := len(s.order)
+	if before != nil {
+		pos := at(before.Timestamp, before.ID)
+		end = sort.Search(len(s.order), func(i int) bool { return !postPosition(s.order[i]).before(pos) })
+	}
+	start := 0
+	if end > limit {
+		start = end - limit
+	}
+
+	return s.newestFirst(start, end), start > 0, nil
+}
+
+// GetPostsAfter returns up to limit posts newer than after (or the oldest posts when
+// after is nil), newest first. The bool reports whether even newer posts remain.
+func (s *PostStore) GetPostsAfter(after *repository.Cursor, limit int) ([]*models.Post, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := 0
+	if after != nil {
+		pos := at(after.Timestamp, after.ID)
+		start = sort.Search(len(s.order), func(i int) bool { return pos.before(postPosition(s.order[i])) })
+	}
+	end := len(s.order)
+	if end-start > limit {
+		end = start + limit
+	}
+
+	return s.newestFirst(start, end), end < len(s.order), nil
+}
+
+// newestFirst copies order[start:end] in feed order
+func (s *PostStore) newestFirst(start, end int) []*models.Post {
+	var posts []*models.Post
+	for i := end - 1; i >= start; i-- {
+		posts = append(posts, copyPost(s.order[i]))
+	}
+	return posts
+}
+
+func (s *PostStore) UpdatePost(post *models.Post) error {
+	post.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.posts[post.ID]; ok {
+		stored.Title = post.Title
+		stored.Content = post.Content
+		stored.UpdatedAt = post.UpdatedAt
+	}
+	return nil
+}
+
+func (s *PostStore) DeletePost(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return nil
+	}
+	delete(s.posts, id)
+
+	for i, candidate := range s.order {
+		if candidate == post {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *PostStore) IncrementCommentCount(postID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if post, ok := s.posts[postID]; ok {
+		post.CommentCount++
+	}
+	return nil
+}
+
+// DecrementCommentCount lowers a post's comment count by n, stopping at zero
+func (s *PostStore) DecrementCommentCount(postID string, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if post, ok := s.posts[postID]; ok {
+		post.CommentCount -= n
+		if post.CommentCount < 0 {
+			post.CommentCount = 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"sync"
+
+	"websocket/internal/repository"
+)
+
+// UnitOfWork runs units against in-memory post and comment stores one at a
+// time. The stores cannot roll back, so a unit must finish its checks before
+// its first write, as the repository helpers do.
+type UnitOfWork struct {
+	mu       sync.Mutex
+	posts    *PostStore
+	comments *CommentStore
+}
+
+var _ repository.UnitOfWork = (*UnitOfWork)(nil)
+
+func NewUnitOfWork(posts *PostStore, comments *CommentStore) *UnitOfWork {
+	return &UnitOfWork{
+		posts:    posts,
+		comments: comments,
+	}
+}
+
+func (u *UnitOfWork) Do(fn func(posts repository.PostStore, comments repository.CommentStore) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return fn(u.posts, u.comments)
+}
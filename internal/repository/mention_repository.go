@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// MentionRepository stores @mentions so users can catch up on them later
+type MentionRepository struct {
+	db *database.DB
+}
+
+func NewMentionRepository(db *database.DB) *MentionRepository {
+	return &MentionRepository{
+		db: db,
+	}
+}
+
+// CreateMentions stores mentions, skipping ones already recorded.
+// It returns only the mentions that were new.
+func (r *MentionRepository) CreateMentions(mentions []*models.Mention) ([]*models.Mention, error) {
+	query := `
+		INSERT OR IGNORE INTO mentions (username, source_type, source_id, room_id, post_id, author, excerpt, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var created []*models.Mention
+	for _, mention := range mentions {
+		if mention.CreatedAt.IsZero() {
+			mention.CreatedAt = time.Now()
+		}
+
+		result, err := r.db.Exec(query,
+			mention.Username,
+			mention.SourceType,
+			mention.SourceID,
+			mention.RoomID,
+			mention.PostID,
+			mention.Author,
+			mention.Excerpt,
+			mention.CreatedAt.Format("2006-01-02 15:04:05"),
+		)
+		if err != nil {
+			return created, fmt.Errorf("failed to create mention: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return created, fmt.Errorf("failed to create mention: %w", err)
+		}
+		if affected > 0 {
+			created = append(created, mention)
+		}
+	}
+
+	return created, nil
+}
+
+// GetMentions returns up to limit mentions of a user older than before (or the newest
+// when before is nil), newest first. The bool reports whether older mentions remain.
+func (r *MentionRepository) GetMentions(username string, before *Cursor, limit int) ([]*models.Mention, bool, error) {
+	query := `
+		SELECT username, source_type, source_id, COALESCE(room_id, ''), COALESCE(post_id, ''), author, excerpt, created_at
+		FROM mentions
+		WHERE username = ?
+		ORDER BY created_at DESC, source_id DESC
+		LIMIT ?
+	`
+	args := []interface{}{username, limit + 1}
+	if before != nil {
+		query = `
+			SELECT username, source_type, source_id, COALESCE(room_id, ''), COALESCE(post_id, ''), author, excerpt, created_at
+			FROM mentions
+			WHERE username = ? AND (created_at < ? OR (created_at = ? AND source_id < ?))
+			ORDER BY created_at DESC, source_id DESC
+			LIMIT ?
+		`
+		args = []interface{}{username, before.sortKey(), before.sortKey(), before.ID, limit + 1}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []*models.Mention{}
+	for rows.Next() {
+		mention := &models.Mention{}
+		var createdAtStr string
+
+		err := rows.Scan(
+			&mention.Username,
+			&mention.SourceType,
+			&mention.SourceID,
+			&mention.RoomID,
+			&mention.PostID,
+			&mention.Author,
+			&mention.Excerpt,
+			&createdAtStr,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan mention: %w", err)
+		}
+
+		if mention.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, false, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		mentions = append(mentions, mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to iterate mentions: %w", err)
+	}
+
+	hasMore := len(mentions) > limit
+	if hasMore {
+		mentions = mentions[:limit]
+	}
+
+	return mentions, hasMore, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"websocket/internal/models"
@@ -20,8 +21,8 @@ func NewMessageRepository(db *database.DB) *MessageRepository {
 
 func (r *MessageRepository) SaveMessage(message *models.Message) error {
 	query := `
-		INSERT INTO messages (id, username, content, room_id, type, timestamp, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO messages (id, username, content, content_html, room_id, type, timestamp, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
@@ -36,6 +37,7 @@ func (r *MessageRepository) SaveMessage(message *models.Message) error {
 		message.ID,
 		message.Username,
 		message.Content,
+		message.ContentHTML,
 		message.RoomID,
 		message.Type,
 		message.Timestamp.Format("2006-01-02 15:04:05"),
@@ -49,12 +51,14 @@ func (r *MessageRepository) SaveMessage(message *models.Message) error {
 	return nil
 }
 
+// GetMessagesByRoom pages through a room oldest first using LIMIT/OFFSET.
+// Kept for offset-based clients; prefer GetMessagesBefore/GetMessagesAfter.
 func (r *MessageRepository) GetMessagesByRoom(roomID string, limit int, offset int) ([]*models.Message, error) {
 	query := `
-		SELECT id, username, content, room_id, type, timestamp, created_at
+		SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
 		FROM messages 
 		WHERE room_id = ? 
-		ORDER BY timestamp ASC
+		ORDER BY timestamp ASC, id ASC
 		LIMIT ? OFFSET ?
 	`
 
@@ -73,6 +77,7 @@ func (r *MessageRepository) GetMessagesByRoom(roomID string, limit int, offset i
 			&message.ID,
 			&message.Username,
 			&message.Content,
+			&message.ContentHTML,
 			&message.RoomID,
 			&message.Type,
 			&timestampStr,
@@ -104,10 +109,10 @@ func (r *MessageRepository) GetMessagesByRoom(roomID string, limit int, offset i
 
 func (r *MessageRepository) GetRecentMessagesByRoom(roomID string, limit int) ([]*models.Message, error) {
 	query := `
-		SELECT id, username, content, room_id, type, timestamp, created_at
+		SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
 		FROM messages 
 		WHERE room_id = ? 
-		ORDER BY timestamp DESC
+		ORDER BY timestamp DESC, id DESC
 		LIMIT ?
 	`
 
@@ -126,6 +131,7 @@ func (r *MessageRepository) GetRecentMessagesByRoom(roomID string, limit int) ([
 			&message.ID,
 			&message.Username,
 			&message.Content,
+			&message.ContentHTML,
 			&message.RoomID,
 			&message.Type,
 			&timestampStr,
@@ -157,9 +163,212 @@ func (r *MessageRepository) GetRecentMessagesByRoom(roomID string, limit int) ([
 		messages[i], messages[j] = messages[j], messages[i]
 	}
 
+	if err := r.attachReactions(messages); err != nil {
+		return nil, err
+	}
+	if err := r.attachFiles(messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
 
+func (r *MessageRepository) GetMessageByID(id string) (*models.Message, error) {
+	query := `
+		SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
+		FROM messages 
+		WHERE id = ?
+	`
+
+	message := &models.Message{}
+	var timestampStr, createdAtStr string
+
+	err := r.db.QueryRow(query, id).Scan(
+		&message.ID,
+		&message.Username,
+		&message.Content,
+		&message.ContentHTML,
+		&message.RoomID,
+		&message.Type,
+		&timestampStr,
+		&createdAtStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if message.Timestamp, err = parseFlexibleTimestamp(timestampStr); err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+	if message.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	return message, nil
+}
+
+// GetMessagesBefore returns up to limit messages older than before (or the newest
+// messages when before is nil), oldest first. The bool reports whether older messages remain.
+func (r *MessageRepository) GetMessagesBefore(roomID string, before *Cursor, limit int) ([]*models.Message, bool, error) {
+	query := `
+		SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
+		FROM messages
+		WHERE room_id = ?
+		ORDER BY timestamp DESC, id DESC
+		LIMIT ?
+	`
+	args := []interface{}{roomID, limit + 1}
+	if before != nil {
+		query = `
+			SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
+			FROM messages
+			WHERE room_id = ? AND (timestamp < ? OR (timestamp = ? AND id < ?))
+			ORDER BY timestamp DESC, id DESC
+			LIMIT ?
+		`
+		args = []interface{}{roomID, before.sortKey(), before.sortKey(), before.ID, limit + 1}
+	}
+
+	messages, err := r.queryMessages(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	// Reverse to get chronological order (oldest first)
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, hasMore, nil
+}
+
+// GetMessagesAfter returns up to limit messages newer than after (or the oldest
+// messages when after is nil), oldest first. The bool reports whether newer messages remain.
+func (r *MessageRepository) GetMessagesAfter(roomID string, after *Cursor, limit int) ([]*models.Message, bool, error) {
+	query := `
+		SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
+		FROM messages
+		WHERE room_id = ?
+		ORDER BY timestamp ASC, id ASC
+		LIMIT ?
+	`
+	args := []interface{}{roomID, limit + 1}
+	if after != nil {
+		query = `
+			SELECT id, username, content, COALESCE(content_html, ''), room_id, type, timestamp, created_at
+			FROM messages
+			WHERE room_id = ? AND (timestamp > ? OR (timestamp = ? AND id > ?))
+			ORDER BY timestamp ASC, id ASC
+			LIMIT ?
+		`
+		args = []interface{}{roomID, after.sortKey(), after.sortKey(), after.ID, limit + 1}
+	}
+
+	messages, err := r.queryMessages(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	return messages, hasMore, nil
+}
+
+// queryMessages runs a message SELECT and attaches reactions to the result
+func (r *MessageRepository) queryMessages(query string, args ...interface{}) ([]*models.Message, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.Message{}
+	for rows.Next() {
+		message := &models.Message{}
+		var timestampStr, createdAtStr string
+
+		err := rows.Scan(
+			&message.ID,
+			&message.Username,
+			&message.Content,
+			&message.ContentHTML,
+			&message.RoomID,
+			&message.Type,
+			&timestampStr,
+			&createdAtStr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+
+		if message.Timestamp, err = parseFlexibleTimestamp(timestampStr); err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+		if message.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate messages: %w", err)
+	}
+
+	if err := r.attachReactions(messages); err != nil {
+		return nil, err
+	}
+	if err := r.attachFiles(messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// attachReactions fills in reaction summaries for a page of messages
+func (r *MessageRepository) attachReactions(messages []*models.Message) error {
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	summaries, err := loadReactionSummaries(r.db, models.ReactionTargetMessage, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.Reactions = summaries[message.ID]
+	}
+	return nil
+}
+
+// attachFiles fills in attachment metadata for a page of messages
+func (r *MessageRepository) attachFiles(messages []*models.Message) error {
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	attachments, err := loadAttachments(r.db, models.AttachmentTargetMessage, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.Attachments = attachments[message.ID]
+	}
+	return nil
+}
+
 func (r *MessageRepository) DeleteOldMessages(roomID string, olderThan time.Time) error {
 	query := `
 		DELETE FROM messages 
@@ -186,6 +395,156 @@ func (r *MessageRepository) GetMessageCount(roomID string) (int, error) {
 	return count, nil
 }
 
+// ListMessageRooms returns every room that has stored messages
+func (r *MessageRepository) ListMessageRooms() ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT room_id FROM messages ORDER BY room_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []string{}
+	for rows.Next() {
+		var room string
+		if err := rows.Scan(&room); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rooms: %w", err)
+	}
+
+	return rooms, nil
+}
+
+// purgeCondition selects the messages of a room that a retention policy drops:
+// those older than olderThan (unless it is zero) and those beyond the keepNewest
+// most recent (unless it is 0)
+func purgeCondition(roomID string, olderThan time.Time, keepNewest int) (string, []interface{}) {
+	var clauses []string
+	args := []interface{}{roomID}
+
+	if !olderThan.IsZero() {
+		clauses = append(clauses, `timestamp < ?`)
+		args = append(args, olderThan.Format("2006-01-02 15:04:05"))
+	}
+	if keepNewest > 0 {
+		clauses = append(clauses, `id IN (
+			SELECT id FROM messages WHERE room_id = ?
+			ORDER BY timestamp DESC, id DESC
+			LIMIT -1 OFFSET ?
+		)`)
+		args = append(args, roomID, keepNewest)
+	}
+	if len(clauses) == 0 {
+		return `room_id = ? AND 0`, args
+	}
+
+	return `room_id = ? AND (` + strings.Join(clauses, ` OR `) + `)`, args
+}
+
+// CountPurgeable reports how many messages a room has and how many a retention
+// policy would delete, without deleting anything
+func (r *MessageRepository) CountPurgeable(roomID string, olderThan time.Time, keepNewest int) (total, purgeable int, err error) {
+	condition, args := purgeCondition(roomID, olderThan, keepNewest)
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM messages WHERE room_id = ?),
+			(SELECT COUNT(*) FROM messages WHERE ` + condition + `)
+	`
+
+	err = r.db.QueryRow(query, append([]interface{}{roomID}, args...)...).Scan(&total, &purgeable)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count purgeable messages: %w", err)
+	}
+	return total, purgeable, nil
+}
+
+// PurgeMessages deletes up to limit of the oldest messages a retention policy
+// drops from a room, together with their attachment records; their reactions
+// and mentions go with them by trigger. It returns how many messages were
+// deleted and the removed attachments, whose blobs the caller should delete.
+// Call it repeatedly until fewer than limit messages are deleted.
+func (r *MessageRepository) PurgeMessages(roomID string, olderThan time.Time, keepNewest, limit int) (int, []*models.Attachment, error) {
+	condition, args := purgeCondition(roomID, olderThan, keepNewest)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin purge: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id FROM messages
+		WHERE `+condition+`
+		ORDER BY timestamp ASC, id ASC
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to select messages to purge: %w", err)
+	}
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan message id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to iterate messages to purge: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	targetArgs := append([]interface{}{models.AttachmentTargetMessage}, ids...)
+
+	attachmentRows, err := tx.Query(`
+		SELECT `+attachmentColumns+`
+		FROM attachments
+		WHERE target_type = ? AND target_id IN (`+placeholders+`)
+	`, targetArgs...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query purged attachments: %w", err)
+	}
+	var attachments []*models.Attachment
+	for attachmentRows.Next() {
+		attachment, err := scanAttachment(attachmentRows)
+		if err != nil {
+			attachmentRows.Close()
+			return 0, nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	attachmentRows.Close()
+	if err := attachmentRows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to iterate purged attachments: %w", err)
+	}
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`DELETE FROM attachments WHERE target_type = ? AND target_id IN (` + placeholders + `)`, targetArgs},
+		{`DELETE FROM messages WHERE id IN (` + placeholders + `)`, ids},
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return 0, nil, fmt.Errorf("failed to purge messages: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return len(ids), attachments, nil
+}
+
 // parseFlexibleTimestamp tries multiple timestamp formats
 func parseFlexibleTimestamp(timestampStr string) (time.Time, error) {
 	// Common timestamp formats
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// ErrModerationItemNotFound is returned when a queue item does not exist
+var ErrModerationItemNotFound = errors.New("moderation item not found")
+
+// ErrModerationItemReviewed is returned when a queue item was already approved or rejected
+var ErrModerationItemReviewed = errors.New("moderation item already reviewed")
+
+// moderationColumns is the column list every queue query selects, in scanModerationItem order
+const moderationColumns = `id, target_type, COALESCE(room_id, ''), COALESCE(post_id, ''), COALESCE(parent_id, ''),
+	author, content, attachment_ids, reason, filter, status, COALESCE(reviewed_by, ''), COALESCE(published_id, ''),
+	created_at, reviewed_at`
+
+// ModerationRepository stores quarantined content for moderator review
+type ModerationRepository struct {
+	db *database.DB
+}
+
+func NewModerationRepository(db *database.DB) *ModerationRepository {
+	return &ModerationRepository{
+		db: db,
+	}
+}
+
+// Enqueue adds a pending item to the review queue
+func (r *ModerationRepository) Enqueue(item *models.ModerationItem) error {
+	query := `
+		INSERT INTO moderation_queue (id, target_type, room_id, post_id, parent_id, author, content, attachment_ids, reason, filter, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	item.Status = models.ModerationPending
+
+	_, err := r.db.Exec(query,
+		item.ID,
+		item.TargetType,
+		item.RoomID,
+		item.PostID,
+		item.ParentID,
+		item.Author,
+		item.Content,
+		strings.Join(item.AttachmentIDs, ","),
+		item.Reason,
+		item.Filter,
+		item.Status,
+		item.CreatedAt.Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue moderation item: %w", err)
+	}
+
+	return nil
+}
+
+// GetItem returns a queue item by ID
+func (r *ModerationRepository) GetItem(id string) (*models.ModerationItem, error) {
+	query := `SELECT ` + moderationColumns + ` FROM moderation_queue WHERE id = ?`
+
+	item, err := scanModerationItem(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrModerationItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation item: %w", err)
+	}
+
+	return item, nil
+}
+
+// ListByStatus returns up to limit items with the given status older than before
+// (or the newest when before is nil), newest first. The bool reports whether more remain.
+func (r *ModerationRepository) ListByStatus(status string, before *Cursor, limit int) ([]*models.ModerationItem, bool, error) {
+	query := `
+		SELECT ` + moderationColumns + `
+		FROM moderation_queue
+		WHERE status = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+	args := []interface{}{status, limit + 1}
+	if before != nil {
+		query = `
+			SELECT ` + moderationColumns + `
+			FROM moderation_queue
+			WHERE status = ? AND (created_at < ? OR (created_at = ? AND id < ?))
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		`
+		args = []interface{}{status, before.sortKey(), before.sortKey(), before.ID, limit + 1}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query moderation queue: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*models.ModerationItem{}
+	for rows.Next() {
+		item, err := scanModerationItem(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan moderation item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to iterate moderation queue: %w", err)
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	return items, hasMore, nil
+}
+
+// Review moves a pending item to status. Only one reviewer can win: a second call
+// for the same item returns ErrModerationItemReviewed.
+func (r *ModerationRepository) Review(id, status, reviewer string) (*models.ModerationItem, error) {
+	query := `
+		UPDATE moderation_queue
+		SET status = ?, reviewed_by = ?, reviewed_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := r.db.Exec(query, status, reviewer, time.Now().Format("2006-01-02 15:04:05"), id, models.ModerationPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review moderation item: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to review moderation item: %w", err)
+	}
+
+	item, err := r.GetItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return item, ErrModerationItemReviewed
+	}
+	return item, nil
+}
+
+// Reopen returns an item to the pending state, e.g. when publishing it failed
+func (r *ModerationRepository) Reopen(id string) error {
+	query := `
+		UPDATE moderation_queue
+		SET status = ?, reviewed_by = NULL, reviewed_at = NULL
+		WHERE id = ?
+	`
+
+	if _, err := r.db.Exec(query, models.ModerationPending, id); err != nil {
+		return fmt.Errorf("failed to reopen moderation item: %w", err)
+	}
+	return nil
+}
+
+// SetPublishedID records the message or comment an approved item became
+func (r *ModerationRepository) SetPublishedID(id, publishedID string) error {
+	if _, err := r.db.Exec(`UPDATE moderation_queue SET published_id = ? WHERE id = ?`, publishedID, id); err != nil {
+		return fmt.Errorf("failed to record published ID: %w", err)
+	}
+	return nil
+}
+
+func scanModerationItem(row rowScanner) (*models.ModerationItem, error) {
+	item := &models.ModerationItem{}
+	var attachmentIDs, createdAtStr string
+	var reviewedAtStr sql.NullString
+
+	err := row.Scan(
+		&item.ID,
+		&item.TargetType,
+		&item.RoomID,
+		&item.PostID,
+		&item.ParentID,
+		&item.Author,
+		&item.Content,
+		&attachmentIDs,
+		&item.Reason,
+		&item.Filter,
+		&item.Status,
+		&item.ReviewedBy,
+		&item.PublishedID,
+		&createdAtStr,
+		&reviewedAtStr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachmentIDs != "" {
+		item.AttachmentIDs = strings.Split(attachmentIDs, ",")
+	}
+	if item.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if reviewedAtStr.Valid {
+		reviewedAt, err := parseFlexibleTimestamp(reviewedAtStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reviewed_at: %w", err)
+		}
+		item.ReviewedAt = &reviewedAt
+	}
+
+	return item, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type PostRepository struct {
-	db *database.DB
+	db database.Querier
 }
 
 func NewPostRepository(db *database.DB) *PostRepository {
@@ -76,25 +76,105 @@ func (r *PostRepository) GetPostByID(id string) (*models.Post, error) {
 	}
 
 	// Parse timestamps
-	if post.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+	if post.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
 		return nil, fmt.Errorf("failed to parse created_at: %w", err)
 	}
-	if post.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+	if post.UpdatedAt, err = parseFlexibleTimestamp(updatedAtStr); err != nil {
 		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
 	}
 
 	return post, nil
 }
 
+// GetAllPosts pages through posts newest first using LIMIT/OFFSET.
+// Kept for offset-based clients; prefer GetPostsBefore/GetPostsAfter.
 func (r *PostRepository) GetAllPosts(limit, offset int) ([]*models.Post, error) {
 	query := `
 		SELECT id, title, content, author_id, author_name, comment_count, created_at, updated_at
 		FROM posts 
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.Query(query, limit, offset)
+	return r.queryPosts(query, limit, offset)
+}
+
+// GetPostsBefore returns up to limit posts older than before (or the newest posts when
+// before is nil), newest first. The bool reports whether older posts remain.
+func (r *PostRepository) GetPostsBefore(before *Cursor, limit int) ([]*models.Post, bool, error) {
+	query := `
+		SELECT id, title, content, author_id, author_name, comment_count, created_at, updated_at
+		FROM posts
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+	args := []interface{}{limit + 1}
+	if before != nil {
+		query = `
+			SELECT id, title, content, author_id, author_name, comment_count, created_at, updated_at
+			FROM posts
+			WHERE created_at < ? OR (created_at = ? AND id < ?)
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		`
+		args = []interface{}{before.sortKey(), before.sortKey(), before.ID, limit + 1}
+	}
+
+	posts, err := r.queryPosts(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	return posts, hasMore, nil
+}
+
+// GetPostsAfter returns up to limit posts newer than after (or the oldest posts when
+// after is nil), newest first. The bool reports whether even newer posts remain.
+func (r *PostRepository) GetPostsAfter(after *Cursor, limit int) ([]*models.Post, bool, error) {
+	query := `
+		SELECT id, title, content, author_id, author_name, comment_count, created_at, updated_at
+		FROM posts
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`
+	args := []interface{}{limit + 1}
+	if after != nil {
+		query = `
+			SELECT id, title, content, author_id, author_name, comment_count, created_at, updated_at
+			FROM posts
+			WHERE created_at > ? OR (created_at = ? AND id > ?)
+			ORDER BY created_at ASC, id ASC
+			LIMIT ?
+		`
+		args = []interface{}{after.sortKey(), after.sortKey(), after.ID, limit + 1}
+	}
+
+	posts, err := r.queryPosts(query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	// Reverse to keep feed order (newest first)
+	for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+		posts[i], posts[j] = posts[j], posts[i]
+	}
+
+	return posts, hasMore, nil
+}
+
+// queryPosts runs a post SELECT and scans every row
+func (r *PostRepository) queryPosts(query string, args ...interface{}) ([]*models.Post, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query posts: %w", err)
 	}
@@ -120,10 +200,10 @@ func (r *PostRepository) GetAllPosts(limit, offset int) ([]*models.Post, error)
 		}
 
 		// Parse timestamps
-		if post.CreatedAt, err = time.Parse("2006-01-02 15:04:05", createdAtStr); err != nil {
+		if post.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
 			return nil, fmt.Errorf("failed to parse created_at: %w", err)
 		}
-		if post.UpdatedAt, err = time.Parse("2006-01-02 15:04:05", updatedAtStr); err != nil {
+		if post.UpdatedAt, err = parseFlexibleTimestamp(updatedAtStr); err != nil {
 			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
 		}
 
@@ -182,10 +262,11 @@ func (r *PostRepository) IncrementCommentCount(postID string) error {
 	return nil
 }
 
-func (r *PostRepository) DecrementCommentCount(postID string) error {
-	query := `UPDATE posts SET comment_count = comment_count - 1 WHERE id = ? AND comment_count > 0`
+// DecrementCommentCount lowers a post's comment count by n, stopping at zero
+func (r *PostRepository) DecrementCommentCount(postID string, n int) error {
+	query := `UPDATE posts SET comment_count = MAX(comment_count - ?, 0) WHERE id = ?`
 
-	_, err := r.db.Exec(query, postID)
+	_, err := r.db.Exec(query, n, postID)
 	if err != nil {
 		return fmt.Errorf("failed to decrement comment count: %w", err)
 	}
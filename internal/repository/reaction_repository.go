@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+type ReactionRepository struct {
+	db *database.DB
+}
+
+func NewReactionRepository(db *database.DB) *ReactionRepository {
+	return &ReactionRepository{
+		db: db,
+	}
+}
+
+// AddReaction stores a reaction, reporting false if the user already reacted with that emoji
+func (r *ReactionRepository) AddReaction(reaction *models.Reaction) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO reactions (target_type, target_id, username, emoji, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	if reaction.CreatedAt.IsZero() {
+		reaction.CreatedAt = time.Now()
+	}
+
+	result, err := r.db.Exec(query,
+		reaction.TargetType,
+		reaction.TargetID,
+		reaction.Username,
+		reaction.Emoji,
+		reaction.CreatedAt.Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// RemoveReaction deletes a reaction, reporting false if it did not exist
+func (r *ReactionRepository) RemoveReaction(targetType, targetID, username, emoji string) (bool, error) {
+	query := `
+		DELETE FROM reactions
+		WHERE target_type = ? AND target_id = ? AND username = ? AND emoji = ?
+	`
+
+	result, err := r.db.Exec(query, targetType, targetID, username, emoji)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetReactionSummary returns aggregated reaction counts for a single target
+func (r *ReactionRepository) GetReactionSummary(targetType, targetID string) ([]models.ReactionSummary, error) {
+	summaries, err := loadReactionSummaries(r.db, targetType, []string{targetID})
+	if err != nil {
+		return nil, err
+	}
+
+	if summaries[targetID] == nil {
+		return []models.ReactionSummary{}, nil
+	}
+	return summaries[targetID], nil
+}
+
+// loadReactionSummaries aggregates reactions for many targets of the same type in one query
+func loadReactionSummaries(db database.Querier, targetType string, targetIDs []string) (map[string][]models.ReactionSummary, error) {
+	summaries := make(map[string][]models.ReactionSummary)
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(targetIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT target_id, emoji, username
+		FROM reactions
+		WHERE target_type = ? AND target_id IN (%s)
+		ORDER BY target_id, created_at ASC, username ASC
+	`, placeholders)
+
+	args := make([]interface{}, 0, len(targetIDs)+1)
+	args = append(args, targetType)
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reactions: %w", err)
+	}
+	defer rows.Close()
+
+	// Keep emojis in order of first use on each target
+	for rows.Next() {
+		var targetID, emoji, username string
+		if err := rows.Scan(&targetID, &emoji, &username); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+
+		list := summaries[targetID]
+		found := false
+		for i := range list {
+			if list[i].Emoji == emoji {
+				list[i].Count++
+				list[i].Users = append(list[i].Users, username)
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, models.ReactionSummary{Emoji: emoji, Count: 1, Users: []string{username}})
+		}
+		summaries[targetID] = list
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reactions: %w", err)
+	}
+
+	return summaries, nil
+}
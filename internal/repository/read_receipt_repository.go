@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// ReadReceiptRepository tracks per-(user, room) read positions and room membership
+type ReadReceiptRepository struct {
+	db *database.DB
+}
+
+func NewReadReceiptRepository(db *database.DB) *ReadReceiptRepository {
+	return &ReadReceiptRepository{
+		db: db,
+	}
+}
+
+// unreadCondition matches messages in room r.room_id newer than the reader's position,
+// ignoring the reader's own messages
+const unreadCondition = `
+	m.room_id = r.room_id
+	AND m.username != r.username
+	AND (
+		r.last_read_at IS NULL
+		OR m.timestamp > r.last_read_at
+		OR (m.timestamp = r.last_read_at AND m.id > r.last_read_message_id)
+	)`
+
+// EnsureMember registers a user in a room. New members start with everything already read.
+func (r *ReadReceiptRepository) EnsureMember(username, roomID string) error {
+	var lastID sql.NullString
+	var lastAt sql.NullString
+
+	latest, err := r.latestMessage(roomID)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		lastID = sql.NullString{String: latest.ID, Valid: true}
+		lastAt = sql.NullString{String: latest.Timestamp.Format("2006-01-02 15:04:05"), Valid: true}
+	}
+
+	query := `
+		INSERT OR IGNORE INTO room_reads (username, room_id, last_read_message_id, last_read_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.Exec(query, username, roomID, lastID, lastAt, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("failed to register room member: %w", err)
+	}
+
+	return nil
+}
+
+// MarkRead moves a user's read position in a room forward to messageID, or to the newest
+// message when messageID is empty. Positions never move backwards; the returned bool
+// reports whether the position changed, and the state is nil when the room has no messages.
+func (r *ReadReceiptRepository) MarkRead(username, roomID, messageID string) (*models.ReadState, bool, error) {
+	var target *models.Message
+	var err error
+	if messageID == "" {
+		target, err = r.latestMessage(roomID)
+	} else {
+		target, err = r.messageInRoom(roomID, messageID)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if target == nil {
+		// An empty room is already fully read
+		return nil, false, nil
+	}
+
+	now := time.Now()
+	lastReadAt := target.Timestamp.Format("2006-01-02 15:04:05")
+
+	query := `
+		INSERT INTO room_reads (username, room_id, last_read_message_id, last_read_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (username, room_id) DO UPDATE SET
+			last_read_message_id = excluded.last_read_message_id,
+			last_read_at = excluded.last_read_at,
+			updated_at = excluded.updated_at
+		WHERE room_reads.last_read_at IS NULL
+			OR excluded.last_read_at > room_reads.last_read_at
+			OR (excluded.last_read_at = room_reads.last_read_at
+				AND excluded.last_read_message_id > room_reads.last_read_message_id)
+	`
+
+	result, err := r.db.Exec(query, username, roomID, target.ID, lastReadAt, now.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to mark room as read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to mark room as read: %w", err)
+	}
+
+	state := &models.ReadState{
+		Username:          username,
+		RoomID:            roomID,
+		LastReadMessageID: target.ID,
+		LastReadAt:        target.Timestamp,
+		UpdatedAt:         now,
+	}
+	return state, affected > 0, nil
+}
+
+// GetUnreadCount returns how many messages a member has not seen in a room
+func (r *ReadReceiptRepository) GetUnreadCount(username, roomID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM room_reads r
+		JOIN messages m ON ` + unreadCondition + `
+		WHERE r.username = ? AND r.room_id = ?
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, username, roomID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetUnreadCounts returns unread counts for every room the user belongs to
+func (r *ReadReceiptRepository) GetUnreadCounts(username string) ([]models.UnreadCount, error) {
+	query := `
+		SELECT r.room_id, COALESCE(r.last_read_message_id, ''),
+			(SELECT COUNT(*) FROM messages m WHERE ` + unreadCondition + `)
+		FROM room_reads r
+		WHERE r.username = ?
+		ORDER BY r.room_id ASC
+	`
+
+	rows, err := r.db.Query(query, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.UnreadCount{}
+	for rows.Next() {
+		var count models.UnreadCount
+		if err := rows.Scan(&count.RoomID, &count.LastReadMessageID, &count.Unread); err != nil {
+			return nil, fmt.Errorf("failed to scan unread count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate unread counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetRoomUnreadCounts returns every member of a room with their unread count,
+// counted in one grouped query
+func (r *ReadReceiptRepository) GetRoomUnreadCounts(roomID string) (map[string]int, error) {
+	query := `
+		SELECT r.username, COUNT(m.id)
+		FROM room_reads r
+		LEFT JOIN messages m ON ` + unreadCondition + `
+		WHERE r.room_id = ?
+		GROUP BY r.username
+	`
+
+	rows, err := r.db.Query(query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var username string
+		var unread int
+		if err := rows.Scan(&username, &unread); err != nil {
+			return nil, fmt.Errorf("failed to scan room unread count: %w", err)
+		}
+		counts[username] = unread
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate room unread counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// latestMessage returns the newest message in a room, or nil if the room is empty
+func (r *ReadReceiptRepository) latestMessage(roomID string) (*models.Message, error) {
+	query := `
+		SELECT id, timestamp
+		FROM messages
+		WHERE room_id = ?
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1
+	`
+
+	message, err := r.scanPosition(r.db.QueryRow(query, roomID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return message, err
+}
+
+// messageInRoom loads a message's position, making sure it belongs to the room
+func (r *ReadReceiptRepository) messageInRoom(roomID, messageID string) (*models.Message, error) {
+	query := `SELECT id, timestamp FROM messages WHERE id = ? AND room_id = ?`
+
+	message, err := r.scanPosition(r.db.QueryRow(query, messageID, roomID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("message %s not found in room %s", messageID, roomID)
+	}
+	return message, err
+}
+
+func (r *ReadReceiptRepository) scanPosition(row *sql.Row) (*models.Message, error) {
+	message := &models.Message{}
+	var timestampStr string
+
+	if err := row.Scan(&message.ID, &timestampStr); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load message position: %w", err)
+	}
+
+	var err error
+	if message.Timestamp, err = parseFlexibleTimestamp(timestampStr); err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	return message, nil
+}
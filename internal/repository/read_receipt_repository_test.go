@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"websocket/internal/models"
+)
+
+// Marking an empty room read is a no-op, while unknown message IDs are still rejected
+func TestMarkReadInEmptyRoom(t *testing.T) {
+	db := newTestDB(t)
+	receipts := NewReadReceiptRepository(db)
+
+	state, changed, err := receipts.MarkRead("alice", "quiet", "")
+	if err != nil || changed || state != nil {
+		t.Fatalf("MarkRead in an empty room = %v, %v, %v; want nil, false, nil", state, changed, err)
+	}
+	if _, _, err := receipts.MarkRead("alice", "quiet", "missing"); err == nil {
+		t.Error("MarkRead accepted a message that is not in the room")
+	}
+
+	message := &models.Message{ID: "m1", Username: "bob", Content: "hi", RoomID: "quiet", Type: "message", Timestamp: time.Now()}
+	if err := NewMessageRepository(db).SaveMessage(message); err != nil {
+		t.Fatalf("SaveMessage: %v", err)
+	}
+	state, changed, err = receipts.MarkRead("alice", "quiet", "")
+	if err != nil || !changed || state == nil || state.LastReadMessageID != "m1" {
+		t.Fatalf("MarkRead after a message = %v, %v, %v; want m1, true, nil", state, changed, err)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// ReconcileCommentCounts recomputes every post's comment count from the comments
+// table and returns the posts that were off. With dryRun nothing is written.
+// orphaned counts comments whose post no longer exists; they are left alone.
+func ReconcileCommentCounts(db *database.DB, dryRun bool) (drifts []models.CommentCountDrift, orphaned int, err error) {
+	err = db.Transaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+			SELECT p.id, p.comment_count, COUNT(c.id)
+			FROM posts p
+			LEFT JOIN comments c ON c.post_id = p.id
+			GROUP BY p.id
+			HAVING p.comment_count != COUNT(c.id)
+			ORDER BY p.id
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to count comments: %w", err)
+		}
+		for rows.Next() {
+			var drift models.CommentCountDrift
+			if err := rows.Scan(&drift.PostID, &drift.Stored, &drift.Actual); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan comment count: %w", err)
+			}
+			drifts = append(drifts, drift)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate comment counts: %w", err)
+		}
+
+		err = tx.QueryRow(`
+			SELECT COUNT(*) FROM comments
+			WHERE post_id NOT IN (SELECT id FROM posts)
+		`).Scan(&orphaned)
+		if err != nil {
+			return fmt.Errorf("failed to count orphaned comments: %w", err)
+		}
+
+		if dryRun {
+			return nil
+		}
+		for _, drift := range drifts {
+			if _, err := tx.Exec(`UPDATE posts SET comment_count = ? WHERE id = ?`, drift.Actual, drift.PostID); err != nil {
+				return fmt.Errorf("failed to fix comment count of post %s: %w", drift.PostID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return drifts, orphaned, nil
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// sanctionColumns is the column list every sanction query selects, in scanSanction order
+const sanctionColumns = `room_id, username, kind, reason, issued_by, created_at, expires_at`
+
+// RoomModerationRepository stores room roles and the bans and mutes moderators issue
+type RoomModerationRepository struct {
+	db *database.DB
+}
+
+func NewRoomModerationRepository(db *database.DB) *RoomModerationRepository {
+	return &RoomModerationRepository{
+		db: db,
+	}
+}
+
+// GetRole returns a user's role in a room, or "" for regular members
+func (r *RoomModerationRepository) GetRole(roomID, username string) (string, error) {
+	var role string
+	err := r.db.QueryRow(`SELECT role FROM room_roles WHERE room_id = ? AND username = ?`, roomID, username).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get room role: %w", err)
+	}
+	return role, nil
+}
+
+// SetRole grants or changes a user's role in a room
+func (r *RoomModerationRepository) SetRole(role *models.RoomRole) error {
+	query := `
+		INSERT INTO room_roles (room_id, username, role, granted_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(room_id, username) DO UPDATE SET
+			role = excluded.role,
+			granted_by = excluded.granted_by,
+			created_at = excluded.created_at
+	`
+
+	if role.CreatedAt.IsZero() {
+		role.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.Exec(query, role.RoomID, role.Username, role.Role, role.GrantedBy, role.CreatedAt.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("failed to set room role: %w", err)
+	}
+	return nil
+}
+
+// RemoveRole makes a user a regular member again. The bool reports whether they had a role.
+func (r *RoomModerationRepository) RemoveRole(roomID, username string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM room_roles WHERE room_id = ? AND username = ?`, roomID, username)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove room role: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove room role: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListRoles returns the owners and moderators of a room
+func (r *RoomModerationRepository) ListRoles(roomID string) ([]*models.RoomRole, error) {
+	query := `
+		SELECT room_id, username, role, granted_by, created_at
+		FROM room_roles
+		WHERE room_id = ?
+		ORDER BY role DESC, username ASC
+	`
+
+	rows, err := r.db.Query(query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []*models.RoomRole{}
+	for rows.Next() {
+		role := &models.RoomRole{}
+		var createdAtStr string
+		if err := rows.Scan(&role.RoomID, &role.Username, &role.Role, &role.GrantedBy, &createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to scan room role: %w", err)
+		}
+		if role.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate room roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Sanction bans or mutes a user, replacing any earlier sanction of the same kind
+func (r *RoomModerationRepository) Sanction(sanction *models.RoomSanction) error {
+	query := `
+		INSERT INTO room_sanctions (room_id, username, kind, reason, issued_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(room_id, username, kind) DO UPDATE SET
+			reason = excluded.reason,
+			issued_by = excluded.issued_by,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+	`
+
+	if sanction.CreatedAt.IsZero() {
+		sanction.CreatedAt = time.Now()
+	}
+	var expiresAt sql.NullString
+	if sanction.ExpiresAt != nil {
+		expiresAt = sql.NullString{String: sanction.ExpiresAt.Format("2006-01-02 15:04:05"), Valid: true}
+	}
+
+	_, err := r.db.Exec(query,
+		sanction.RoomID,
+		sanction.Username,
+		sanction.Kind,
+		sanction.Reason,
+		sanction.IssuedBy,
+		sanction.CreatedAt.Format("2006-01-02 15:04:05"),
+		expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save room sanction: %w", err)
+	}
+	return nil
+}
+
+// LiftSanction removes a ban or mute. The bool reports whether an active one was lifted.
+func (r *RoomModerationRepository) LiftSanction(roomID, username, kind string) (bool, error) {
+	active, err := r.GetActiveSanction(roomID, username, kind)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM room_sanctions WHERE room_id = ? AND username = ? AND kind = ?`, roomID, username, kind); err != nil {
+		return false, fmt.Errorf("failed to lift room sanction: %w", err)
+	}
+	return active != nil, nil
+}
+
+// GetActiveSanction returns the unexpired sanction of kind for a user, or nil if there is none
+func (r *RoomModerationRepository) GetActiveSanction(roomID, username, kind string) (*models.RoomSanction, error) {
+	query := `
+		SELECT ` + sanctionColumns + `
+		FROM room_sanctions
+		WHERE room_id = ? AND username = ? AND kind = ? AND (expires_at IS NULL OR expires_at > ?)
+	`
+
+	sanction, err := scanSanction(r.db.QueryRow(query, roomID, username, kind, time.Now().Format("2006-01-02 15:04:05")))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room sanction: %w", err)
+	}
+	return sanction, nil
+}
+
+// ListActiveSanctions returns the unexpired bans and mutes in a room, newest first
+func (r *RoomModerationRepository) ListActiveSanctions(roomID string) ([]*models.RoomSanction, error) {
+	query := `
+		SELECT ` + sanctionColumns + `
+		FROM room_sanctions
+		WHERE room_id = ? AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC, username ASC
+	`
+
+	rows, err := r.db.Query(query, roomID, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query room sanctions: %w", err)
+	}
+	defer rows.Close()
+
+	sanctions := []*models.RoomSanction{}
+	for rows.Next() {
+		sanction, err := scanSanction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan room sanction: %w", err)
+		}
+		sanctions = append(sanctions, sanction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate room sanctions: %w", err)
+	}
+
+	return sanctions, nil
+}
+
+func scanSanction(row rowScanner) (*models.RoomSanction, error) {
+	sanction := &models.RoomSanction{}
+	var createdAtStr string
+	var expiresAtStr sql.NullString
+
+	err := row.Scan(
+		&sanction.RoomID,
+		&sanction.Username,
+		&sanction.Kind,
+		&sanction.Reason,
+		&sanction.IssuedBy,
+		&createdAtStr,
+		&expiresAtStr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sanction.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if expiresAtStr.Valid {
+		expiresAt, err := parseFlexibleTimestamp(expiresAtStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse expires_at: %w", err)
+		}
+		sanction.ExpiresAt = &expiresAt
+	}
+
+	return sanction, nil
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// Snippet highlight markers. They pass through HTML escaping untouched and are swapped
+// for <mark> tags afterwards, so user text in a snippet is always escaped.
+const (
+	highlightStart = "\x02"
+	highlightEnd   = "\x03"
+)
+
+// Search limits
+const (
+	MaxSearchQueryLength = 200
+	DefaultSearchLimit   = 20
+	MaxSearchLimit       = 50
+	MaxSearchOffset      = 1000 // Every scope loads offset+limit hits, so deep pages are refused
+)
+
+// SearchRepository runs full-text queries against the FTS5 indexes
+type SearchRepository struct {
+	db *database.DB
+}
+
+func NewSearchRepository(db *database.DB) *SearchRepository {
+	return &SearchRepository{
+		db: db,
+	}
+}
+
+// Search returns ranked hits for the query across the requested scope
+func (r *SearchRepository) Search(query models.SearchQuery) ([]models.SearchResult, error) {
+	match := buildMatchQuery(query.Text)
+	if match == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	// Each scope is fetched up to offset+limit so merged results can be paged together
+	fetch := query.Offset + query.Limit
+	results := []models.SearchResult{}
+
+	if query.Scope == models.SearchScopeAll || query.Scope == models.SearchScopeMessages {
+		hits, err := r.searchMessages(match, query, fetch)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, hits...)
+	}
+	if query.Scope == models.SearchScopeAll || query.Scope == models.SearchScopePosts {
+		hits, err := r.searchPosts(match, query, fetch)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, hits...)
+	}
+	if query.Scope == models.SearchScopeAll || query.Scope == models.SearchScopeComments {
+		hits, err := r.searchComments(match, query, fetch)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, hits...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank < results[j].Rank
+	})
+
+	if query.Offset >= len(results) {
+		return []models.SearchResult{}, nil
+	}
+	results = results[query.Offset:]
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results, nil
+}
+
+func (r *SearchRepository) searchMessages(match string, query models.SearchQuery, limit int) ([]models.SearchResult, error) {
+	sqlQuery := `
+		SELECT m.id, m.username, m.room_id, m.timestamp,
+			snippet(messages_fts, 0, ?, ?, '…', 16), bm25(messages_fts)
+		FROM messages_fts
+		JOIN messages m ON m.rowid = messages_fts.rowid
+		WHERE messages_fts MATCH ?
+	`
+	args := []interface{}{highlightStart, highlightEnd, match}
+
+	if query.RoomID != "" {
+		sqlQuery += ` AND m.room_id = ?`
+		args = append(args, query.RoomID)
+	}
+	if query.Author != "" {
+		sqlQuery += ` AND m.username = ?`
+		args = append(args, query.Author)
+	}
+	if query.Viewer != "" {
+		sqlQuery += ` AND m.room_id NOT IN (
+			SELECT room_id FROM room_sanctions
+			WHERE username = ? AND kind = ? AND (expires_at IS NULL OR expires_at > ?)
+		)`
+		args = append(args, query.Viewer, models.SanctionBan, time.Now().Format("2006-01-02 15:04:05"))
+	}
+	sqlQuery, args = addDateRange(sqlQuery, args, "m.timestamp", query)
+	sqlQuery += ` ORDER BY bm25(messages_fts) LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchResultMessage}
+		var createdAtStr, snippet string
+		if err := rows.Scan(&result.ID, &result.Author, &result.RoomID, &createdAtStr, &snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan message hit: %w", err)
+		}
+		if result.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+		result.Snippet = highlightSnippet(snippet)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate message hits: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *SearchRepository) searchPosts(match string, query models.SearchQuery, limit int) ([]models.SearchResult, error) {
+	// Title matches weigh more than body matches; the snippet picks whichever column matched best
+	sqlQuery := `
+		SELECT p.id, p.author_name, p.title, p.created_at,
+			snippet(posts_fts, -1, ?, ?, '…', 16), bm25(posts_fts, 5.0, 1.0)
+		FROM posts_fts
+		JOIN posts p ON p.rowid = posts_fts.rowid
+		WHERE posts_fts MATCH ?
+	`
+	args := []interface{}{highlightStart, highlightEnd, match}
+
+	if query.Author != "" {
+		sqlQuery += ` AND (p.author_id = ? OR p.author_name = ?)`
+		args = append(args, query.Author, query.Author)
+	}
+	sqlQuery, args = addDateRange(sqlQuery, args, "p.created_at", query)
+	sqlQuery += ` ORDER BY bm25(posts_fts, 5.0, 1.0) LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchResultPost}
+		var createdAtStr, snippet string
+		if err := rows.Scan(&result.ID, &result.Author, &result.Title, &createdAtStr, &snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan post hit: %w", err)
+		}
+		if result.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		result.PostID = result.ID
+		result.Snippet = highlightSnippet(snippet)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate post hits: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *SearchRepository) searchComments(match string, query models.SearchQuery, limit int) ([]models.SearchResult, error) {
+	sqlQuery := `
+		SELECT c.id, c.post_id, c.author_name, c.created_at,
+			snippet(comments_fts, 0, ?, ?, '…', 16), bm25(comments_fts)
+		FROM comments_fts
+		JOIN comments c ON c.rowid = comments_fts.rowid
+		WHERE comments_fts MATCH ?
+	`
+	args := []interface{}{highlightStart, highlightEnd, match}
+
+	if query.Author != "" {
+		sqlQuery += ` AND (c.author_id = ? OR c.author_name = ?)`
+		args = append(args, query.Author, query.Author)
+	}
+	sqlQuery, args = addDateRange(sqlQuery, args, "c.created_at", query)
+	sqlQuery += ` ORDER BY bm25(comments_fts) LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search comments: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchResultComment}
+		var createdAtStr, snippet string
+		if err := rows.Scan(&result.ID, &result.PostID, &result.Author, &createdAtStr, &snippet, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan comment hit: %w", err)
+		}
+		if result.CreatedAt, err = parseFlexibleTimestamp(createdAtStr); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		result.Snippet = highlightSnippet(snippet)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comment hits: %w", err)
+	}
+
+	return results, nil
+}
+
+// NormalizeSearchQuery validates a query and fills in the default scope and limit.
+// A room narrows message hits only; with scope all, posts and comments are not filtered by it.
+func NormalizeSearchQuery(query *models.SearchQuery) error {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return fmt.Errorf("search query is required")
+	}
+	if len(query.Text) > MaxSearchQueryLength {
+		return fmt.Errorf("search query too long (max %d characters)", MaxSearchQueryLength)
+	}
+
+	switch query.Scope {
+	case "":
+		query.Scope = models.SearchScopeAll
+	case models.SearchScopeAll, models.SearchScopeMessages, models.SearchScopePosts, models.SearchScopeComments:
+	default:
+		return fmt.Errorf("scope must be one of all, messages, posts or comments")
+	}
+
+	if query.RoomID != "" && (query.Scope == models.SearchScopePosts || query.Scope == models.SearchScopeComments) {
+		return fmt.Errorf("room only applies to message search; use scope messages or all")
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return fmt.Errorf("to must not be before from")
+	}
+
+	if query.Limit < 0 || query.Offset < 0 {
+		return fmt.Errorf("limit and offset must not be negative")
+	}
+	if query.Offset > MaxSearchOffset {
+		return fmt.Errorf("offset must not exceed %d", MaxSearchOffset)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit > MaxSearchLimit {
+		query.Limit = MaxSearchLimit
+	}
+
+	return nil
+}
+
+// addDateRange appends the query's date bounds on column
+func addDateRange(sqlQuery string, args []interface{}, column string, query models.SearchQuery) (string, []interface{}) {
+	if !query.From.IsZero() {
+		sqlQuery += fmt.Sprintf(` AND %s >= ?`, column)
+		args = append(args, query.From.Local().Format("2006-01-02 15:04:05"))
+	}
+	if !query.To.IsZero() {
+		sqlQuery += fmt.Sprintf(` AND %s <= ?`, column)
+		args = append(args, query.To.Local().Format("2006-01-02 15:04:05"))
+	}
+	return sqlQuery, args
+}
+
+// buildMatchQuery turns free text into an FTS5 query that matches every word.
+// Each word is quoted so FTS5 operators in user input are treated as plain text.
+func buildMatchQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// highlightSnippet escapes a raw FTS snippet and turns the match markers into <mark> tags
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightEnd, "</mark>")
+}
+
+// ParseSearchDate parses a search date bound given as RFC3339 or YYYY-MM-DD.
+// A bare date used as an upper bound covers the whole day. Timestamps are stored in
+// server local time, so bounds are converted to it.
+func ParseSearchDate(value string, endOfDay bool) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			return day.Add(24*time.Hour - time.Second), nil
+		}
+		return day, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC3339)", value)
+	}
+	return parsed.Local(), nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewMemoryDatabase()
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func searchRooms(t *testing.T, search *SearchRepository, query models.SearchQuery) map[string]int {
+	t.Helper()
+	if err := NormalizeSearchQuery(&query); err != nil {
+		t.Fatalf("NormalizeSearchQuery: %v", err)
+	}
+	results, err := search.Search(query)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	rooms := map[string]int{}
+	for _, result := range results {
+		rooms[result.RoomID]++
+	}
+	return rooms
+}
+
+func TestSearchLeavesOutRoomsTheViewerIsBannedFrom(t *testing.T) {
+	db := newTestDB(t)
+	messages := NewMessageRepository(db)
+	moderation := NewRoomModerationRepository(db)
+	search := NewSearchRepository(db)
+
+	for i, room := range []string{"general", "secret", "secret"} {
+		message := &models.Message{ID: room + string(rune('a'+i)), Username: "alice", Content: "launch plans", RoomID: room, Type: "message", Timestamp: time.Now()}
+		if err := messages.SaveMessage(message); err != nil {
+			t.Fatalf("SaveMessage: %v", err)
+		}
+	}
+	expired := time.Now().Add(-time.Hour)
+	sanctions := []*models.RoomSanction{
+		{RoomID: "secret", Username: "mallory", Kind: models.SanctionBan, IssuedBy: "alice"},
+		{RoomID: "secret", Username: "bob", Kind: models.SanctionMute, IssuedBy: "alice"},
+		{RoomID: "secret", Username: "carol", Kind: models.SanctionBan, IssuedBy: "alice", ExpiresAt: &expired},
+	}
+	for _, sanction := range sanctions {
+		if err := moderation.Sanction(sanction); err != nil {
+			t.Fatalf("Sanction: %v", err)
+		}
+	}
+
+	tests := []struct {
+		viewer string
+		room   string
+		want   map[string]int
+	}{
+		{"mallory", "", map[string]int{"general": 1}},
+		{"mallory", "secret", map[string]int{}},
+		{"bob", "", map[string]int{"general": 1, "secret": 2}},
+		{"carol", "", map[string]int{"general": 1, "secret": 2}},
+		{"", "", map[string]int{"general": 1, "secret": 2}},
+	}
+	for _, test := range tests {
+		got := searchRooms(t, search, models.SearchQuery{Text: "launch", Scope: models.SearchScopeMessages, RoomID: test.room, Viewer: test.viewer})
+		if len(got) != len(test.want) {
+			t.Errorf("%q in %q: hits per room %v, want %v", test.viewer, test.room, got, test.want)
+			continue
+		}
+		for room, n := range test.want {
+			if got[room] != n {
+				t.Errorf("%q in %q: hits per room %v, want %v", test.viewer, test.room, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNormalizeSearchQueryRejectsDeepPagesAndMisplacedRooms(t *testing.T) {
+	tests := []struct {
+		query models.SearchQuery
+		ok    bool
+	}{
+		{models.SearchQuery{Text: "x", Offset: MaxSearchOffset}, true},
+		{models.SearchQuery{Text: "x", Offset: MaxSearchOffset + 1}, false},
+		{models.SearchQuery{Text: "x", Offset: int(^uint(0) >> 1)}, false},
+		{models.SearchQuery{Text: "x", RoomID: "general"}, true},
+		{models.SearchQuery{Text: "x", RoomID: "general", Scope: models.SearchScopeMessages}, true},
+		{models.SearchQuery{Text: "x", RoomID: "general", Scope: models.SearchScopePosts}, false},
+		{models.SearchQuery{Text: "x", RoomID: "general", Scope: models.SearchScopeComments}, false},
+	}
+	for _, test := range tests {
+		query := test.query
+		err := NormalizeSearchQuery(&query)
+		if (err == nil) != test.ok {
+			t.Errorf("offset %d, room %q, scope %q: error %v, want ok %v", test.query.Offset, test.query.RoomID, test.query.Scope, err, test.ok)
+		}
+	}
+}
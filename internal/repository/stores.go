@@ -0,0 +1,51 @@
+package repository
+
+import "websocket/internal/models"
+
+// MessageStore is the chat message storage the handlers depend on.
+// MessageRepository implements it on SQLite; the memory package keeps it in
+// process for tests. Lookups of unknown IDs return an error wrapping
+// sql.ErrNoRows in both.
+type MessageStore interface {
+	SaveMessage(message *models.Message) error
+	GetMessageByID(id string) (*models.Message, error)
+	GetMessagesByRoom(roomID string, limit int, offset int) ([]*models.Message, error)
+	GetRecentMessagesByRoom(roomID string, limit int) ([]*models.Message, error)
+	GetMessagesBefore(roomID string, before *Cursor, limit int) ([]*models.Message, bool, error)
+	GetMessagesAfter(roomID string, after *Cursor, limit int) ([]*models.Message, bool, error)
+}
+
+// PostStore is the post storage the handlers depend on
+type PostStore interface {
+	CreatePost(post *models.Post) error
+	GetPostByID(id string) (*models.Post, error)
+	GetAllPosts(limit, offset int) ([]*models.Post, error)
+	GetPostsBefore(before *Cursor, limit int) ([]*models.Post, bool, error)
+	GetPostsAfter(after *Cursor, limit int) ([]*models.Post, bool, error)
+	UpdatePost(post *models.Post) error
+	DeletePost(id string) error
+	IncrementCommentCount(postID string) error
+	DecrementCommentCount(postID string, n int) error
+}
+
+// CommentStore is the comment storage the handlers depend on
+type CommentStore interface {
+	CreateComment(comment *models.Comment) error
+	GetCommentByID(id string) (*models.Comment, error)
+	GetCommentsByPostID(postID string, limit, offset int) ([]*models.Comment, error)
+	GetRecentCommentsByPostID(postID string, limit int) ([]*models.Comment, error)
+	GetTopLevelComments(postID string, limit, offset int) ([]*models.Comment, int, error)
+	GetReplies(parentID string, limit, offset int) ([]*models.Comment, int, error)
+	GetCommentsBefore(postID string, before *Cursor, limit int) ([]*models.Comment, bool, error)
+	GetCommentsAfter(postID string, after *Cursor, limit int) ([]*models.Comment, bool, error)
+	GetCommentTree(roots []*models.Comment, depth, repliesLimit int) ([]*models.CommentNode, error)
+	UpdateComment(comment *models.Comment) error
+	DeleteComment(id string) (int, []*models.Attachment, error)
+	DeleteCommentsByPostID(postID string) (int, []*models.Attachment, error)
+}
+
+var (
+	_ MessageStore = (*MessageRepository)(nil)
+	_ PostStore    = (*PostRepository)(nil)
+	_ CommentStore = (*CommentRepository)(nil)
+)
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"websocket/internal/models"
+	"websocket/pkg/database"
+)
+
+// ErrPostNotFound is returned when a comment is written against a post that does not exist
+var ErrPostNotFound = errors.New("post not found")
+
+// UnitOfWork groups post and comment writes that must succeed or fail
+// together, such as a comment and its post's comment count
+type UnitOfWork interface {
+	// Do runs fn against stores bound to one unit. Writes made through them are
+	// kept only if fn returns nil.
+	Do(fn func(posts PostStore, comments CommentStore) error) error
+}
+
+// txUnitOfWork runs each unit in a database transaction
+type txUnitOfWork struct {
+	db *database.DB
+}
+
+// NewUnitOfWork returns a UnitOfWork backed by SQLite transactions
+func NewUnitOfWork(db *database.DB) UnitOfWork {
+	return &txUnitOfWork{
+		db: db,
+	}
+}
+
+func (u *txUnitOfWork) Do(fn func(posts PostStore, comments CommentStore) error) error {
+	return u.db.Transaction(func(tx *sql.Tx) error {
+		return fn(&PostRepository{db: tx}, &CommentRepository{db: tx})
+	})
+}
+
+// CreatePostComment saves a comment and counts it on its post in one unit.
+// Comments on posts that do not exist fail with ErrPostNotFound.
+func CreatePostComment(uow UnitOfWork, comment *models.Comment) error {
+	return uow.Do(func(posts PostStore, comments CommentStore) error {
+		if _, err := posts.GetPostByID(comment.PostID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrPostNotFound, comment.PostID)
+			}
+			return err
+		}
+		if err := comments.CreateComment(comment); err != nil {
+			return err
+		}
+		return posts.IncrementCommentCount(comment.PostID)
+	})
+}
+
+// DeletePostComment deletes a comment with its replies and takes all of them
+// off its post's comment count in one unit. It returns the thread's removed
+// attachments, whose blobs the caller should delete.
+func DeletePostComment(uow UnitOfWork, comment *models.Comment) ([]*models.Attachment, error) {
+	var removed []*models.Attachment
+	err := uow.Do(func(posts PostStore, comments CommentStore) error {
+		deleted, attachments, err := comments.DeleteComment(comment.ID)
+		if err != nil {
+			return err
+		}
+		removed = attachments
+		return posts.DecrementCommentCount(comment.PostID, deleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// DeletePostWithComments deletes a post together with all of its comments in
+// one unit. It returns the comments' removed attachments, whose blobs the
+// caller should delete.
+func DeletePostWithComments(uow UnitOfWork, postID string) ([]*models.Attachment, error) {
+	var removed []*models.Attachment
+	err := uow.Do(func(posts PostStore, comments CommentStore) error {
+		_, attachments, err := comments.DeleteCommentsByPostID(postID)
+		if err != nil {
+			return err
+		}
+		removed = attachments
+		return posts.DeletePost(postID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
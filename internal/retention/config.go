@@ -0,0 +1,136 @@
+package retention
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Vacuum modes for reclaiming disk space after a purge
+const (
+	VacuumIncremental = "incremental"
+	VacuumFull        = "full"
+	VacuumOff         = "off"
+)
+
+// Defaults used when the configuration leaves a setting out
+const (
+	defaultInterval  = time.Hour
+	defaultBatchSize = 500
+	minInterval      = time.Minute
+)
+
+// Policy decides which chat messages of a room are kept. A message is deleted
+// when it breaks either limit; a zero limit is not applied.
+type Policy struct {
+	MaxAgeDays  int `json:"max_age_days,omitempty"` // Delete messages older than this
+	MaxMessages int `json:"max_messages,omitempty"` // Keep only the newest this many
+}
+
+// IsZero reports whether the policy keeps everything
+func (p Policy) IsZero() bool {
+	return p.MaxAgeDays == 0 && p.MaxMessages == 0
+}
+
+// Cutoff returns the timestamp before which messages are too old, or the zero
+// time when the policy has no age limit
+func (p Policy) Cutoff(now time.Time) time.Time {
+	if p.MaxAgeDays == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -p.MaxAgeDays)
+}
+
+func (p Policy) validate() error {
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("max_age_days cannot be negative")
+	}
+	if p.MaxMessages < 0 {
+		return fmt.Errorf("max_messages cannot be negative")
+	}
+	return nil
+}
+
+// Config is the retention configuration file. Rooms listed under rooms use their
+// own policy instead of the default; an empty room policy keeps that room forever.
+type Config struct {
+	Default   Policy            `json:"default"`
+	Rooms     map[string]Policy `json:"rooms,omitempty"`
+	Interval  string            `json:"interval,omitempty"`   // How often to purge, e.g. "30m" (default "1h")
+	Vacuum    string            `json:"vacuum,omitempty"`     // "incremental" (default), "full" or "off"
+	BatchSize int               `json:"batch_size,omitempty"` // Messages deleted per transaction (default 500)
+}
+
+// LoadConfig reads and validates a JSON configuration file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retention config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse retention config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retention config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks the policies and fills in defaults
+func (c *Config) Validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for room, policy := range c.Rooms {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("room %s: %w", room, err)
+		}
+	}
+
+	if c.Interval == "" {
+		c.Interval = defaultInterval.String()
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("interval: %w", err)
+	}
+	if interval < minInterval {
+		return fmt.Errorf("interval must be at least %s", minInterval)
+	}
+
+	switch c.Vacuum {
+	case "":
+		c.Vacuum = VacuumIncremental
+	case VacuumIncremental, VacuumFull, VacuumOff:
+	default:
+		return fmt.Errorf("vacuum must be incremental, full or off")
+	}
+
+	if c.BatchSize < 0 {
+		return fmt.Errorf("batch_size cannot be negative")
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	return nil
+}
+
+// PolicyFor returns the policy that applies to room
+func (c *Config) PolicyFor(room string) Policy {
+	if policy, ok := c.Rooms[room]; ok {
+		return policy
+	}
+	return c.Default
+}
+
+// IntervalDuration returns the validated purge interval
+func (c *Config) IntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return defaultInterval
+	}
+	return interval
+}
@@ -0,0 +1,280 @@
+// Package retention deletes chat messages that have outlived their room's
+// retention policy and reclaims the space they used.
+package retention
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"websocket/internal/attachments"
+	"websocket/internal/repository"
+	"websocket/pkg/database"
+)
+
+// RoomPreview is what a policy would delete from one room
+type RoomPreview struct {
+	Room      string     `json:"room"`
+	Policy    Policy     `json:"policy"`
+	Messages  int        `json:"messages"`         // Messages currently stored
+	Purgeable int        `json:"purgeable"`        // Messages the policy would delete
+	Cutoff    *time.Time `json:"cutoff,omitempty"` // Messages older than this are too old
+}
+
+// Preview is the dry-run result of a configuration
+type Preview struct {
+	Rooms     []*RoomPreview `json:"rooms"`
+	Purgeable int            `json:"purgeable"`
+}
+
+// RunReport describes one purge
+type RunReport struct {
+	StartedAt          time.Time      `json:"started_at"`
+	Duration           string         `json:"duration"`
+	MessagesDeleted    int            `json:"messages_deleted"`
+	AttachmentsDeleted int            `json:"attachments_deleted"`
+	RoomsPurged        map[string]int `json:"rooms_purged,omitempty"`
+	Vacuum             string         `json:"vacuum,omitempty"` // Vacuum mode run afterwards, if any
+	BytesReclaimed     int64          `json:"bytes_reclaimed"`
+	Error              string         `json:"error,omitempty"`
+}
+
+// Metrics are cumulative counters since the server started
+type Metrics struct {
+	Runs               int                  `json:"runs"`
+	FailedRuns         int                  `json:"failed_runs"`
+	MessagesDeleted    int                  `json:"messages_deleted"`
+	AttachmentsDeleted int                  `json:"attachments_deleted"`
+	Vacuums            int                  `json:"vacuums"`
+	BytesReclaimed     int64                `json:"bytes_reclaimed"`
+	LastRun            *RunReport           `json:"last_run,omitempty"`
+	NextRunAt          *time.Time           `json:"next_run_at,omitempty"`
+	Database           *database.SpaceStats `json:"database,omitempty"`
+}
+
+// Purger applies the retention configuration on a schedule
+type Purger struct {
+	config            *Config
+	db                *database.DB
+	messageRepo       *repository.MessageRepository
+	attachmentService *attachments.Service
+
+	runMutex     sync.Mutex // Only one purge at a time
+	metricsMutex sync.Mutex
+	metrics      Metrics
+}
+
+// NewPurger creates a purger. A nil config disables scheduled purges, but
+// previews of other configurations still work.
+func NewPurger(config *Config, db *database.DB, messageRepo *repository.MessageRepository, attachmentService *attachments.Service) *Purger {
+	return &Purger{
+		config:            config,
+		db:                db,
+		messageRepo:       messageRepo,
+		attachmentService: attachmentService,
+	}
+}
+
+// NewPurgerFromEnv loads the JSON file named by RETENTION_CONFIG. Without it
+// messages are kept forever.
+func NewPurgerFromEnv(db *database.DB, messageRepo *repository.MessageRepository, attachmentService *attachments.Service) (*Purger, error) {
+	var config *Config
+	if path := os.Getenv("RETENTION_CONFIG"); path != "" {
+		var err error
+		if config, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+		log.Printf("🧹 Loaded retention config from %s (%d room overrides, every %s)", path, len(config.Rooms), config.Interval)
+	}
+	return NewPurger(config, db, messageRepo, attachmentService), nil
+}
+
+// Config returns the active configuration, or nil when retention is disabled
+func (p *Purger) Config() *Config {
+	return p.config
+}
+
+// Run purges at startup and then on the configured interval until the process
+// exits. It returns immediately when retention is disabled.
+func (p *Purger) Run() {
+	if p.config == nil {
+		log.Println("🧹 Message retention disabled (set RETENTION_CONFIG to enable)")
+		return
+	}
+
+	interval := p.config.IntervalDuration()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.RunOnce()
+		p.setNextRun(time.Now().Add(interval))
+		<-ticker.C
+	}
+}
+
+// RunOnce applies the configuration now and records the outcome in the metrics
+func (p *Purger) RunOnce() (*RunReport, error) {
+	if p.config == nil {
+		return nil, fmt.Errorf("retention is not configured")
+	}
+
+	p.runMutex.Lock()
+	defer p.runMutex.Unlock()
+
+	report := &RunReport{StartedAt: time.Now(), RoomsPurged: map[string]int{}}
+	err := p.purge(report)
+	if err == nil && report.MessagesDeleted > 0 {
+		err = p.reclaim(report)
+	}
+	report.Duration = time.Since(report.StartedAt).Round(time.Millisecond).String()
+	if err != nil {
+		report.Error = err.Error()
+		log.Printf("❌ Retention purge failed after deleting %d messages: %v", report.MessagesDeleted, err)
+	} else {
+		log.Printf("🧹 Retention purge deleted %d messages and %d attachments in %s", report.MessagesDeleted, report.AttachmentsDeleted, report.Duration)
+	}
+
+	p.record(report, err)
+	return report, err
+}
+
+// purge deletes expired messages room by room, in batches so that chat traffic
+// isn't blocked behind one long transaction
+func (p *Purger) purge(report *RunReport) error {
+	rooms, err := p.messageRepo.ListMessageRooms()
+	if err != nil {
+		return err
+	}
+
+	for _, room := range rooms {
+		policy := p.config.PolicyFor(room)
+		if policy.IsZero() {
+			continue
+		}
+		cutoff := policy.Cutoff(report.StartedAt)
+
+		for {
+			deleted, removed, err := p.messageRepo.PurgeMessages(room, cutoff, policy.MaxMessages, p.config.BatchSize)
+			if err != nil {
+				return fmt.Errorf("room %s: %w", room, err)
+			}
+			p.attachmentService.RemoveBlobs(removed)
+
+			report.MessagesDeleted += deleted
+			report.AttachmentsDeleted += len(removed)
+			if deleted > 0 {
+				report.RoomsPurged[room] += deleted
+			}
+			if deleted < p.config.BatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// reclaim hands the pages freed by a purge back to the file system
+func (p *Purger) reclaim(report *RunReport) error {
+	if p.config.Vacuum == VacuumOff {
+		return nil
+	}
+
+	before, err := p.db.SpaceStats()
+	if err != nil {
+		return err
+	}
+
+	switch p.config.Vacuum {
+	case VacuumFull:
+		err = p.db.Vacuum()
+	case VacuumIncremental:
+		// Databases created before incremental auto_vacuum need one full VACUUM first
+		if before.AutoVacuum != "incremental" {
+			log.Printf("🧹 Switching database to incremental auto_vacuum (one-off full VACUUM)")
+			if err = p.db.EnableIncrementalVacuum(); err != nil {
+				break
+			}
+		}
+		// Return the pages freed by the purge (and by any search index rebuild)
+		err = p.db.IncrementalVacuum()
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := p.db.SpaceStats()
+	if err != nil {
+		return err
+	}
+	report.Vacuum = p.config.Vacuum
+	report.BytesReclaimed = before.SizeBytes - after.SizeBytes
+	return nil
+}
+
+// Preview reports what config would delete right now without deleting anything
+func (p *Purger) Preview(config *Config) (*Preview, error) {
+	rooms, err := p.messageRepo.ListMessageRooms()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	preview := &Preview{Rooms: []*RoomPreview{}}
+	for _, room := range rooms {
+		policy := config.PolicyFor(room)
+		cutoff := policy.Cutoff(now)
+
+		total, purgeable, err := p.messageRepo.CountPurgeable(room, cutoff, policy.MaxMessages)
+		if err != nil {
+			return nil, err
+		}
+
+		roomPreview := &RoomPreview{Room: room, Policy: policy, Messages: total, Purgeable: purgeable}
+		if !cutoff.IsZero() {
+			roomPreview.Cutoff = &cutoff
+		}
+		preview.Rooms = append(preview.Rooms, roomPreview)
+		preview.Purgeable += purgeable
+	}
+	return preview, nil
+}
+
+// Metrics returns a snapshot of the purge counters and current database size
+func (p *Purger) Metrics() Metrics {
+	p.metricsMutex.Lock()
+	snapshot := p.metrics
+	p.metricsMutex.Unlock()
+
+	if stats, err := p.db.SpaceStats(); err == nil {
+		snapshot.Database = stats
+	} else {
+		log.Printf("❌ Failed to read database size: %v", err)
+	}
+	return snapshot
+}
+
+func (p *Purger) record(report *RunReport, err error) {
+	p.metricsMutex.Lock()
+	defer p.metricsMutex.Unlock()
+
+	p.metrics.Runs++
+	if err != nil {
+		p.metrics.FailedRuns++
+	}
+	p.metrics.MessagesDeleted += report.MessagesDeleted
+	p.metrics.AttachmentsDeleted += report.AttachmentsDeleted
+	if report.Vacuum != "" {
+		p.metrics.Vacuums++
+		p.metrics.BytesReclaimed += report.BytesReclaimed
+	}
+	p.metrics.LastRun = report
+}
+
+func (p *Purger) setNextRun(at time.Time) {
+	p.metricsMutex.Lock()
+	defer p.metricsMutex.Unlock()
+	p.metrics.NextRunAt = &at
+}
@@ -18,5 +18,6 @@ type Client struct {
 
 	// Connection state
 	isConnected bool
+	isClosed    bool // send has been closed
 	mutex       sync.Mutex
 }
@@ -1,6 +1,8 @@
 package websocket
 
 import (
+	"errors"
+
 	"websocket/internal/websocket/handlers/shared"
 )
 
@@ -13,6 +15,37 @@ func (c *Client) GetID() string {
 	return c.id
 }
 
+// Make Client implement Conn
+func (c *Client) Transport() string {
+	return TransportWebSocket
+}
+
+func (c *Client) Deliver(message Outbound) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isClosed {
+		return false
+	}
+	select {
+	case c.send <- message.Data:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the send queue, which makes writePump close the socket
+func (c *Client) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isClosed {
+		c.isClosed = true
+		close(c.send)
+	}
+}
+
 func (c *Client) GetHub() shared.HubInterface {
 	return c.hub
 }
@@ -22,6 +55,24 @@ func (c *Client) SendError(message string) {
 	c.hub.SendToClient(c, errorEvent)
 }
 
+// sendHandlerError reports a failed event back to the client, keeping any error code,
+// the offending field of an invalid event and the event's request_id so the client
+// can tell which request failed
+func (c *Client) sendHandlerError(err error, messageBytes []byte) {
+	errorEvent := shared.NewErrorEvent(err.Error())
+	var eventErr *shared.EventError
+	if errors.As(err, &eventErr) {
+		errorEvent = shared.NewErrorEventWithCode(eventErr.Message, eventErr.Code)
+	}
+	var validationErr *shared.ValidationError
+	if errors.As(err, &validationErr) {
+		errorEvent = shared.NewErrorEventWithCode(validationErr.Error(), shared.ErrCodeInvalidEvent)
+		errorEvent.Field = validationErr.Field
+	}
+	errorEvent.RequestID = shared.RequestID(messageBytes)
+	c.hub.SendToClient(c, errorEvent)
+}
+
 // handleEvent routes events using the event router
 func (c *Client) handleEvent(messageBytes []byte) error {
 	return eventRouter.routeEvent(c, messageBytes)
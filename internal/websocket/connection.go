@@ -75,7 +75,7 @@ func (c *Client) readPump() {
 		// Handle the event
 		if err := c.handleEvent(messageBytes); err != nil {
 			log.Printf("❌ Error handling event: %v", err)
-			c.SendError(err.Error())
+			c.sendHandlerError(err, messageBytes)
 		}
 	}
 }
@@ -125,10 +125,8 @@ func (c *Client) writePump() {
 
 // Send sends raw bytes to the client
 func (c *Client) Send(data []byte) error {
-	select {
-	case c.send <- data:
-		return nil
-	default:
+	if !c.Deliver(Outbound{Data: data}) {
 		return fmt.Errorf("client send buffer is full")
 	}
+	return nil
 }
@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventLogSize is how many recent broadcasts the hub keeps for resumption
+const eventLogSize = 1000
+
+// Broadcast channels recorded in the event log
+const feedChannel = "feed"
+
+func roomChannel(roomName string) string { return "room:" + roomName }
+
+func postChannel(postID string) string { return "post:" + postID }
+
+// EventLog keeps the most recent broadcasts so SSE clients can resume after a
+// reconnect with Last-Event-ID. IDs look like "<epoch>-<seq>"; the epoch is
+// fixed when the hub starts, so IDs handed out by an earlier process are
+// recognised as unresumable instead of silently skipping events.
+type EventLog struct {
+	mutex   sync.Mutex
+	epoch   string
+	seq     uint64
+	entries []loggedEvent // Ring buffer, oldest at start once full
+	start   int
+}
+
+type loggedEvent struct {
+	seq      uint64
+	channels []string
+	data     []byte
+}
+
+// NewEventLog creates a log that keeps the last capacity broadcasts
+func NewEventLog(capacity int) *EventLog {
+	return &EventLog{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		entries: make([]loggedEvent, 0, capacity),
+	}
+}
+
+// Append records a broadcast to the given channels and returns its event ID
+func (l *EventLog) Append(data []byte, channels ...string) string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.seq++
+	entry := loggedEvent{seq: l.seq, channels: channels, data: data}
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, entry)
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % len(l.entries)
+	}
+	return l.epoch + "-" + strconv.FormatUint(l.seq, 10)
+}
+
+// Since returns the logged broadcasts after lastID on any of the given channels,
+// oldest first. ok is false when lastID can't be resumed from: it is malformed,
+// comes from another process, or events after it have already been evicted.
+func (l *EventLog) Since(lastID string, channels map[string]bool) (events []Outbound, ok bool) {
+	lastSeq, ok := l.parse(lastID)
+	if !ok {
+		return nil, false
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if lastSeq > l.seq {
+		return nil, false
+	}
+	if len(l.entries) > 0 && l.entries[l.start].seq > lastSeq+1 {
+		return nil, false
+	}
+
+	for i := range l.entries {
+		entry := l.entries[(l.start+i)%len(l.entries)]
+		if entry.seq <= lastSeq {
+			continue
+		}
+		for _, channel := range entry.channels {
+			if channels[channel] {
+				events = append(events, Outbound{ID: l.epoch + "-" + strconv.FormatUint(entry.seq, 10), Data: entry.data})
+				break
+			}
+		}
+	}
+	return events, true
+}
+
+// Seq returns the sequence number of an ID from this log, or 0 if it isn't one
+func (l *EventLog) Seq(id string) uint64 {
+	seq, ok := l.parse(id)
+	if !ok {
+		return 0
+	}
+	return seq
+}
+
+func (l *EventLog) parse(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != l.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Current returns the ID of the latest broadcast, so a client that has seen
+// nothing yet can still resume from the moment it connected
+func (l *EventLog) Current() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.epoch + "-" + strconv.FormatUint(l.seq, 10)
+}
@@ -5,11 +5,21 @@ import (
 	"fmt"
 	"log"
 
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
 	"websocket/internal/websocket/handlers/chat"
 	"websocket/internal/websocket/handlers/comments"
+	"websocket/internal/websocket/handlers/history"
+	"websocket/internal/websocket/handlers/reactions"
+	"websocket/internal/websocket/handlers/receipts"
 	"websocket/internal/websocket/handlers/rooms"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/search"
 	"websocket/internal/websocket/handlers/shared"
+	"websocket/internal/websocket/handlers/subscriptions"
+	"websocket/pkg/protocol"
 )
 
 // eventRouter will be initialized with repositories
@@ -17,17 +27,43 @@ var eventRouter *EventRouter
 
 // EventRouter handles routing of WebSocket events to appropriate handlers
 type EventRouter struct {
-	chatHandler    *chat.Handler
-	commentHandler *comments.Handler
-	roomHandler    *rooms.Handler
+	chatHandler         *chat.Handler
+	commentHandler      *comments.Handler
+	roomHandler         *rooms.Handler
+	reactionHandler     *reactions.Handler
+	receiptHandler      *receipts.Handler
+	historyHandler      *history.Handler
+	searchHandler       *search.Handler
+	sanctionHandler     *sanctions.Handler
+	subscriptionHandler *subscriptions.Handler
 }
 
 // InitializeEventRouter initializes the global event router with repositories
-func InitializeEventRouter(messageRepo *repository.MessageRepository, commentRepo *repository.CommentRepository) {
+func InitializeEventRouter(
+	messageRepo repository.MessageStore,
+	postRepo repository.PostStore,
+	commentRepo repository.CommentStore,
+	unitOfWork repository.UnitOfWork,
+	reactionRepo *repository.ReactionRepository,
+	readReceiptRepo *repository.ReadReceiptRepository,
+	searchRepo *repository.SearchRepository,
+	mentionRepo *repository.MentionRepository,
+	attachmentRepo *repository.AttachmentRepository,
+	attachmentService *attachments.Service,
+	moderator *moderation.Moderator,
+	roomModerationRepo *repository.RoomModerationRepository,
+	bus *domain.Bus,
+) {
 	eventRouter = &EventRouter{
-		chatHandler:    chat.NewHandler(messageRepo),
-		commentHandler: comments.NewHandler(commentRepo),
-		roomHandler:    rooms.NewHandler(),
+		chatHandler:         chat.NewHandler(messageRepo, readReceiptRepo, mentionRepo, attachmentRepo, moderator, roomModerationRepo),
+		commentHandler:      comments.NewHandler(commentRepo, unitOfWork, mentionRepo, attachmentRepo, attachmentService, moderator, bus),
+		roomHandler:         rooms.NewHandler(readReceiptRepo, roomModerationRepo),
+		reactionHandler:     reactions.NewHandler(reactionRepo, messageRepo, commentRepo, roomModerationRepo),
+		receiptHandler:      receipts.NewHandler(readReceiptRepo, roomModerationRepo),
+		historyHandler:      history.NewHandler(messageRepo, commentRepo, roomModerationRepo),
+		searchHandler:       search.NewHandler(searchRepo, roomModerationRepo),
+		sanctionHandler:     sanctions.NewHandler(roomModerationRepo),
+		subscriptionHandler: subscriptions.NewHandler(postRepo, commentRepo),
 	}
 }
 
@@ -43,6 +79,11 @@ func (r *EventRouter) routeEvent(client shared.ClientInterface, messageBytes []b
 
 	log.Printf("📨 Routing event type: %s from client %s", baseEvent.Type, client.GetUsername())
 
+	// Check the event against its generated schema before any handler sees it
+	if err := protocol.Validate(messageBytes); err != nil {
+		return err
+	}
+
 	// Route to appropriate handler using constants
 	switch baseEvent.Type {
 	case EventJoinRoom:
@@ -51,6 +92,36 @@ func (r *EventRouter) routeEvent(client shared.ClientInterface, messageBytes []b
 		return r.chatHandler.HandleChatMessage(client, messageBytes)
 	case EventPostComment:
 		return r.commentHandler.HandlePostComment(client, messageBytes)
+	case EventEditComment:
+		return r.commentHandler.HandleEditComment(client, messageBytes)
+	case EventDeleteComment:
+		return r.commentHandler.HandleDeleteComment(client, messageBytes)
+	case EventReactionAdd:
+		return r.reactionHandler.HandleAddReaction(client, messageBytes)
+	case EventReactionRemove:
+		return r.reactionHandler.HandleRemoveReaction(client, messageBytes)
+	case EventMarkRead:
+		return r.receiptHandler.HandleMarkRead(client, messageBytes)
+	case EventFetchHistory:
+		return r.historyHandler.HandleFetchHistory(client, messageBytes)
+	case EventSearch:
+		return r.searchHandler.HandleSearch(client, messageBytes)
+	case EventMuteUser:
+		return r.sanctionHandler.HandleMuteUser(client, messageBytes)
+	case EventUnmuteUser:
+		return r.sanctionHandler.HandleUnmuteUser(client, messageBytes)
+	case EventBanUser:
+		return r.sanctionHandler.HandleBanUser(client, messageBytes)
+	case EventUnbanUser:
+		return r.sanctionHandler.HandleUnbanUser(client, messageBytes)
+	case EventSubscribePost:
+		return r.subscriptionHandler.HandleSubscribePost(client, messageBytes)
+	case EventUnsubscribePost:
+		return r.subscriptionHandler.HandleUnsubscribePost(client, messageBytes)
+	case EventSubscribeFeed:
+		return r.subscriptionHandler.HandleSubscribeFeed(client, messageBytes)
+	case EventUnsubscribeFeed:
+		return r.subscriptionHandler.HandleUnsubscribeFeed(client, messageBytes)
 	default:
 		return fmt.Errorf("unknown event type: %s", baseEvent.Type)
 	}
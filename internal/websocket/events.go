@@ -1,16 +1,72 @@
 package websocket
 
-// Event type constants - used by handlers
+import "websocket/pkg/protocol"
+
+// Event type constants - used by handlers, defined in pkg/protocol
 const (
-	EventJoinRoom    = "JOIN_ROOM"
-	EventChatMessage = "CHAT_MESSAGE"
-	EventPostComment = "POST_COMMENT"
-	EventRoomJoined  = "ROOM_JOINED"
-	EventError       = "ERROR"
+	EventJoinRoom    = protocol.EventJoinRoom
+	EventChatMessage = protocol.EventChatMessage
+	EventPostComment = protocol.EventPostComment
+	EventRoomJoined  = protocol.EventRoomJoined
+	EventError       = protocol.EventError
+
+	// Comment lifecycle events
+	EventEditComment    = protocol.EventEditComment
+	EventDeleteComment  = protocol.EventDeleteComment
+	EventCommentUpdated = protocol.EventCommentUpdated
+	EventCommentDeleted = protocol.EventCommentDeleted
+
+	// Post feed events, fanned out from the domain bus
+	EventPostCreated    = protocol.EventPostCreated
+	EventPostUpdated    = protocol.EventPostUpdated
+	EventPostDeleted    = protocol.EventPostDeleted
+	EventCommentCreated = protocol.EventCommentCreated
+
+	// Subscription events
+	EventSubscribePost    = protocol.EventSubscribePost
+	EventUnsubscribePost  = protocol.EventUnsubscribePost
+	EventSubscribeFeed    = protocol.EventSubscribeFeed
+	EventUnsubscribeFeed  = protocol.EventUnsubscribeFeed
+	EventPostSubscribed   = protocol.EventPostSubscribed
+	EventPostUnsubscribed = protocol.EventPostUnsubscribed
+	EventFeedSubscribed   = protocol.EventFeedSubscribed
+	EventFeedUnsubscribed = protocol.EventFeedUnsubscribed
+	EventPostSubscribers  = protocol.EventPostSubscribers
+
+	// Reaction events
+	EventReactionAdd     = protocol.EventReactionAdd
+	EventReactionRemove  = protocol.EventReactionRemove
+	EventReactionUpdated = protocol.EventReactionUpdated
+
+	// Read receipt events
+	EventMarkRead     = protocol.EventMarkRead
+	EventReadReceipt  = protocol.EventReadReceipt
+	EventUnreadUpdate = protocol.EventUnreadUpdate
+
+	// History events
+	EventFetchHistory = protocol.EventFetchHistory
+	EventHistoryPage  = protocol.EventHistoryPage
+
+	// Mention events
+	EventMention = protocol.EventMention
+
+	// Search events
+	EventSearch        = protocol.EventSearch
+	EventSearchResults = protocol.EventSearchResults
+
+	// Moderation events
+	EventContentHeld = protocol.EventContentHeld
+
+	// Room sanction events
+	EventMuteUser     = protocol.EventMuteUser
+	EventUnmuteUser   = protocol.EventUnmuteUser
+	EventBanUser      = protocol.EventBanUser
+	EventUnbanUser    = protocol.EventUnbanUser
+	EventUserMuted    = protocol.EventUserMuted
+	EventUserUnmuted  = protocol.EventUserUnmuted
+	EventUserBanned   = protocol.EventUserBanned
+	EventUserUnbanned = protocol.EventUserUnbanned
 )
 
 // Event interface - all events must implement this
-type Event interface {
-	GetType() string
-	GetUser() string
-}
+type Event = protocol.Event
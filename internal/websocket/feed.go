@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+
+	"websocket/internal/domain"
+	"websocket/internal/websocket/handlers/comments"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	PostEvent            = protocol.PostEvent
+	CommentCreatedEvent  = protocol.CommentCreatedEvent
+	PostSubscribersEvent = protocol.PostSubscribersEvent
+)
+
+// SubscribeToFeed adds a client to the global posts feed, which receives every
+// post and comment domain event regardless of post subscriptions
+func (h *Hub) SubscribeToFeed(client shared.ClientInterface) {
+	conn, ok := client.(Conn)
+	if !ok {
+		log.Printf("❌ Invalid client type in SubscribeToFeed")
+		return
+	}
+
+	h.feedMutex.Lock()
+	h.feedSubscribers[conn] = true
+	h.feedMutex.Unlock()
+
+	log.Printf("📰 Client %s subscribed to the posts feed", client.GetUsername())
+}
+
+// UnsubscribeFromFeed removes a client from the posts feed
+func (h *Hub) UnsubscribeFromFeed(client shared.ClientInterface) {
+	conn, ok := client.(Conn)
+	if !ok {
+		log.Printf("❌ Invalid client type in UnsubscribeFromFeed")
+		return
+	}
+
+	h.feedMutex.Lock()
+	delete(h.feedSubscribers, conn)
+	h.feedMutex.Unlock()
+
+	log.Printf("📰 Client %s unsubscribed from the posts feed", client.GetUsername())
+}
+
+// HandleDomainEvent fans a committed post or comment change out to the
+// post's subscribers and to the posts feed. Subscribe it to the domain bus.
+func (h *Hub) HandleDomainEvent(event domain.Event) {
+	switch event.Type {
+	case domain.PostCreated:
+		// Nobody can be subscribed to a post that didn't exist yet
+		h.deliver(h.feedClients(), newPostEvent(event), feedChannel)
+
+	case domain.PostUpdated:
+		h.deliver(h.postAndFeedClients(event.PostID), newPostEvent(event), postChannel(event.PostID), feedChannel)
+
+	case domain.PostDeleted:
+		h.deliver(h.postAndFeedClients(event.PostID), newPostEvent(event), postChannel(event.PostID), feedChannel)
+
+		// The post is gone, so its subscriptions are too
+		h.postMutex.Lock()
+		delete(h.postSubscribers, event.PostID)
+		h.postMutex.Unlock()
+
+	case domain.CommentCreated:
+		// Post subscribers get new comments as POST_COMMENT, as they always have
+		h.BroadcastToPostSubscribers(event.PostID, comments.NewPostCommentEvent(event.Comment))
+		h.deliver(h.feedClients(), &CommentCreatedEvent{
+			Type:    EventCommentCreated,
+			PostID:  event.PostID,
+			Comment: event.Comment,
+			User:    event.Comment.AuthorName,
+		}, feedChannel)
+
+	default:
+		log.Printf("⚠️ Ignoring unknown domain event %s", event.Type)
+	}
+}
+
+func newPostEvent(event domain.Event) *PostEvent {
+	return &PostEvent{
+		Type:      event.Type,
+		PostID:    event.PostID,
+		Post:      event.Post,
+		User:      event.Actor,
+		Timestamp: event.OccurredAt,
+	}
+}
+
+// broadcastSubscriberCount sends a post's subscriber count to its subscribers and the posts feed
+func (h *Hub) broadcastSubscriberCount(postID string, subscribers int) {
+	h.deliver(h.postAndFeedClients(postID), &PostSubscribersEvent{
+		Type:        EventPostSubscribers,
+		PostID:      postID,
+		Subscribers: subscribers,
+	}, postChannel(postID), feedChannel)
+}
+
+// countSubscribers counts the distinct users among a post's subscribed connections
+func countSubscribers(postClients map[Conn]bool) int {
+	users := make(map[string]bool, len(postClients))
+	for client := range postClients {
+		users[client.GetUsername()] = true
+	}
+	return len(users)
+}
+
+// feedClients snapshots the posts feed subscribers
+func (h *Hub) feedClients() []Conn {
+	h.feedMutex.RLock()
+	defer h.feedMutex.RUnlock()
+
+	clients := make([]Conn, 0, len(h.feedSubscribers))
+	for client := range h.feedSubscribers {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// postAndFeedClients snapshots a post's subscribers plus the feed subscribers,
+// each client once even if it is both
+func (h *Hub) postAndFeedClients(postID string) []Conn {
+	h.postMutex.RLock()
+	clients := make([]Conn, 0, len(h.postSubscribers[postID]))
+	seen := make(map[Conn]bool, len(h.postSubscribers[postID]))
+	for client := range h.postSubscribers[postID] {
+		clients = append(clients, client)
+		seen[client] = true
+	}
+	h.postMutex.RUnlock()
+
+	for _, client := range h.feedClients() {
+		if !seen[client] {
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
+
+// deliver logs an event under the given channels and sends it to each client,
+// skipping those whose send buffer is full
+func (h *Hub) deliver(clients []Conn, event interface{}, channels ...string) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("❌ Error marshaling feed event: %v", err)
+		return
+	}
+	message := Outbound{ID: h.events.Append(eventBytes, channels...), Data: eventBytes}
+
+	for _, client := range clients {
+		if !client.Deliver(message) {
+			log.Printf("⚠️ Dropped feed event for %s: send buffer full", client.GetUsername())
+		}
+	}
+}
@@ -7,37 +7,57 @@ import (
 	"time"
 
 	"websocket/internal/models"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/mentions"
+	"websocket/internal/websocket/handlers/receipts"
+	"websocket/internal/websocket/handlers/sanctions"
 	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/markdown"
+	"websocket/pkg/protocol"
+)
+
+// Chat messages have their own budget, separate from reactions
+const (
+	messageRateLimit  = 30
+	messageRateWindow = 10 * time.Second
 )
 
 // Handler handles chat-related WebSocket events
 type Handler struct {
-	validator         *Validator
-	messageRepository *repository.MessageRepository
+	validator             *Validator
+	limiter               *shared.RateLimiter
+	messageRepository     repository.MessageStore
+	readReceiptRepository *repository.ReadReceiptRepository
+	mentionRepository     *repository.MentionRepository
+	attachmentRepository  *repository.AttachmentRepository
+	moderator             *moderation.Moderator
+	roomModerationRepo    *repository.RoomModerationRepository
 }
 
 // NewHandler creates a new chat handler
-func NewHandler(messageRepo *repository.MessageRepository) *Handler {
+func NewHandler(
+	messageRepo repository.MessageStore,
+	readReceiptRepo *repository.ReadReceiptRepository,
+	mentionRepo *repository.MentionRepository,
+	attachmentRepo *repository.AttachmentRepository,
+	moderator *moderation.Moderator,
+	roomModerationRepo *repository.RoomModerationRepository,
+) *Handler {
 	return &Handler{
-		validator:         NewValidator(),
-		messageRepository: messageRepo,
+		validator:             NewValidator(),
+		limiter:               shared.NewRateLimiter(messageRateLimit, messageRateWindow),
+		messageRepository:     messageRepo,
+		readReceiptRepository: readReceiptRepo,
+		mentionRepository:     mentionRepo,
+		attachmentRepository:  attachmentRepo,
+		moderator:             moderator,
+		roomModerationRepo:    roomModerationRepo,
 	}
 }
 
-// ChatMessageEvent represents a chat message event
-type ChatMessageEvent struct {
-	Type    string `json:"type"`    // "CHAT_MESSAGE"
-	Room    string `json:"room"`    // Target room
-	User    string `json:"user"`    // Sender username
-	Message string `json:"message"` // Message content
-}
-
-// GetType returns the event type
-func (e *ChatMessageEvent) GetType() string { return e.Type }
-
-// GetUser returns the user
-func (e *ChatMessageEvent) GetUser() string { return e.User }
+// ChatMessageEvent is defined in pkg/protocol so Go clients share it
+type ChatMessageEvent = protocol.ChatMessageEvent
 
 // HandleChatMessage processes chat message events with database persistence
 func (h *Handler) HandleChatMessage(client shared.ClientInterface, messageBytes []byte) error {
@@ -52,39 +72,120 @@ func (h *Handler) HandleChatMessage(client shared.ClientInterface, messageBytes
 		return err
 	}
 
-	// Set user from client if not provided
-	if event.User == "" {
-		event.User = client.GetUsername()
+	if !h.limiter.Allow(client.GetUsername()) {
+		return shared.NewEventError(shared.ErrCodeRateLimited, "too many messages, slow down")
 	}
 
+	// Banned and muted users can't post to the room
+	if err := sanctions.CheckBanned(h.roomModerationRepo, event.Room, client.GetUsername()); err != nil {
+		return err
+	}
+	if err := sanctions.CheckMuted(h.roomModerationRepo, event.Room, client.GetUsername()); err != nil {
+		return err
+	}
+
+	// Messages always come from the connected user, whom sanctions and limits apply to
+	event.User = client.GetUsername()
+
 	log.Printf("💬 Processing chat message from %s in room %s: %s", event.User, event.Room, event.Message)
 
+	// Attachments must be the sender's own, unused uploads
+	var attachments []*models.Attachment
+	if len(event.AttachmentIDs) > 0 {
+		var err error
+		if attachments, err = h.attachmentRepository.GetPendingAttachments(event.AttachmentIDs, client.GetUsername()); err != nil {
+			return err
+		}
+	}
+
+	// Moderate before anything is stored or broadcast; masking rewrites the text
+	verdict := h.moderator.CheckMessage(event.Room, event.Message)
+	switch verdict.Action {
+	case moderation.Reject:
+		log.Printf("🛡️ Rejected message from %s in room %s (%s)", event.User, event.Room, verdict.Filter)
+		return shared.NewEventError(shared.ErrCodeContentRejected, verdict.Reason)
+	case moderation.Quarantine:
+		return h.holdMessage(client, &event, verdict)
+	}
+	event.Message = verdict.Content
+
 	// STEP 1: Save to database first
 	now := time.Now()
 	message := &models.Message{
-		ID:        generateMessageID(),
-		Username:  event.User,
-		Content:   event.Message,
-		RoomID:    event.Room,
-		Type:      "chat",
-		Timestamp: now,
-		CreatedAt: now,
+		ID:          generateMessageID(),
+		Username:    event.User,
+		Content:     event.Message,
+		ContentHTML: markdown.Render(event.Message),
+		RoomID:      event.Room,
+		Type:        "chat",
+		Timestamp:   now,
+		CreatedAt:   now,
+	}
+
+	// Claim the attachments first, so a saved message never misses them
+	if err := h.attachmentRepository.AttachTo(attachments, models.AttachmentTargetMessage, message.ID); err != nil {
+		log.Printf("❌ Failed to attach files to message %s: %v", message.ID, err)
+		return fmt.Errorf("failed to attach files: %v", err)
 	}
 
 	if err := h.messageRepository.SaveMessage(message); err != nil {
 		log.Printf("❌ Failed to save message to database: %v", err)
+		if releaseErr := h.attachmentRepository.Release(attachments); releaseErr != nil {
+			log.Printf("❌ Failed to release attachments of message %s: %v", message.ID, releaseErr)
+		}
 		return fmt.Errorf("failed to save message: %v", err)
 	}
 
 	log.Printf("💾 Message saved to database with ID: %s", message.ID)
 
 	// STEP 2: Only broadcast after successful DB save
+	event.MessageID = message.ID
+	event.ContentHTML = message.ContentHTML
+	event.Attachments = attachments
 	client.GetHub().BroadcastToChatRoom(event.Room, &event)
 
 	log.Printf("📡 Message broadcasted to room %s", event.Room)
+
+	// STEP 3: The sender has read their own message; everyone else gets a fresh unread count
+	if _, _, err := h.readReceiptRepository.MarkRead(client.GetUsername(), event.Room, message.ID); err != nil {
+		log.Printf("❌ Failed to update read position for %s: %v", client.GetUsername(), err)
+	}
+	receipts.NotifyNewMessage(client.GetHub(), h.readReceiptRepository, event.Room, client.GetUsername())
+
+	// STEP 4: Notify @mentioned users wherever they are
+	mentions.Record(client.GetHub(), h.mentionRepository, models.Mention{
+		SourceType: models.MentionSourceMessage,
+		SourceID:   message.ID,
+		RoomID:     event.Room,
+		Author:     client.GetUsername(),
+		CreatedAt:  now,
+	}, event.Message, client.GetUsername())
+
 	return nil
 }
 
+// holdMessage queues a quarantined message for review and tells only the sender
+func (h *Handler) holdMessage(client shared.ClientInterface, event *ChatMessageEvent, verdict moderation.Verdict) error {
+	item := &models.ModerationItem{
+		TargetType:    models.ModerationTargetMessage,
+		RoomID:        event.Room,
+		Author:        client.GetUsername(),
+		AttachmentIDs: event.AttachmentIDs,
+	}
+	if err := h.moderator.Hold(item, verdict); err != nil {
+		log.Printf("❌ Failed to queue message for review: %v", err)
+		return fmt.Errorf("failed to queue message for review: %v", err)
+	}
+
+	return client.GetHub().SendToClient(client, &shared.ContentHeldEvent{
+		Type:       "CONTENT_HELD",
+		QueueID:    item.ID,
+		TargetType: item.TargetType,
+		Room:       item.RoomID,
+		Reason:     item.Reason,
+	})
+}
+
 // generateMessageID creates a unique message ID
 func generateMessageID() string {
 	return fmt.Sprintf("msg_%d", time.Now().UnixNano())
@@ -15,8 +15,8 @@ func (v *Validator) ValidateChatMessage(event *ChatMessageEvent) error {
 	if event.Room == "" {
 		return fmt.Errorf("room name is required for chat message")
 	}
-	if event.Message == "" {
-		return fmt.Errorf("message content is required")
+	if event.Message == "" && len(event.AttachmentIDs) == 0 {
+		return fmt.Errorf("message content or attachment is required")
 	}
 	if len(event.Message) > 1000 {
 		return fmt.Errorf("message too long (max 1000 characters)")
@@ -24,5 +24,8 @@ func (v *Validator) ValidateChatMessage(event *ChatMessageEvent) error {
 	if len(event.Room) > 50 {
 		return fmt.Errorf("room name too long (max 50 characters)")
 	}
+	if len(event.AttachmentIDs) > 10 {
+		return fmt.Errorf("too many attachments (max 10)")
+	}
 	return nil
 }
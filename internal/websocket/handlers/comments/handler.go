@@ -2,42 +2,114 @@ package comments
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
 	"websocket/internal/models"
+	"websocket/internal/moderation"
 	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/mentions"
 	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/markdown"
+	"websocket/pkg/protocol"
 )
 
 // Handler handles comment-related WebSocket events
 type Handler struct {
-	validator         *Validator
-	commentRepository *repository.CommentRepository
+	validator            *Validator
+	commentRepository    repository.CommentStore
+	unitOfWork           repository.UnitOfWork
+	mentionRepository    *repository.MentionRepository
+	attachmentRepository *repository.AttachmentRepository
+	attachmentService    *attachments.Service
+	moderator            *moderation.Moderator
+	bus                  *domain.Bus
 }
 
 // NewHandler creates a new comments handler
-func NewHandler(commentRepo *repository.CommentRepository) *Handler {
+func NewHandler(
+	commentRepo repository.CommentStore,
+	unitOfWork repository.UnitOfWork,
+	mentionRepo *repository.MentionRepository,
+	attachmentRepo *repository.AttachmentRepository,
+	attachmentService *attachments.Service,
+	moderator *moderation.Moderator,
+	bus *domain.Bus,
+) *Handler {
 	return &Handler{
-		validator:         NewValidator(),
-		commentRepository: commentRepo,
+		validator:            NewValidator(),
+		commentRepository:    commentRepo,
+		unitOfWork:           unitOfWork,
+		mentionRepository:    mentionRepo,
+		attachmentRepository: attachmentRepo,
+		attachmentService:    attachmentService,
+		moderator:            moderator,
+		bus:                  bus,
 	}
 }
 
-// PostCommentEvent represents a post comment event
-type PostCommentEvent struct {
-	Type    string `json:"type"`    // "POST_COMMENT"
-	PostID  string `json:"post_id"` // Target post ID
-	User    string `json:"user"`    // Commenter username
-	Comment string `json:"comment"` // Comment content
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	PostCommentEvent    = protocol.PostCommentEvent
+	EditCommentEvent    = protocol.EditCommentEvent
+	DeleteCommentEvent  = protocol.DeleteCommentEvent
+	CommentUpdatedEvent = protocol.CommentUpdatedEvent
+	CommentDeletedEvent = protocol.CommentDeletedEvent
+)
+
+// NewPostCommentEvent builds the broadcast for a newly stored comment
+func NewPostCommentEvent(comment *models.Comment) *PostCommentEvent {
+	var attachmentIDs []string
+	for _, attachment := range comment.Attachments {
+		attachmentIDs = append(attachmentIDs, attachment.ID)
+	}
+
+	return &PostCommentEvent{
+		Type:          "POST_COMMENT",
+		PostID:        comment.PostID,
+		User:          comment.AuthorName,
+		Comment:       comment.Content,
+		ContentHTML:   comment.ContentHTML,
+		ReplyTo:       comment.ParentID,
+		AttachmentIDs: attachmentIDs,
+		CommentID:     comment.ID,
+		Attachments:   comment.Attachments,
+	}
 }
 
-// GetType returns the event type
-func (e *PostCommentEvent) GetType() string { return e.Type }
+// NewCommentUpdatedEvent builds the broadcast for an edited comment
+func NewCommentUpdatedEvent(comment *models.Comment) *CommentUpdatedEvent {
+	return &CommentUpdatedEvent{
+		Type:        "COMMENT_UPDATED",
+		PostID:      comment.PostID,
+		CommentID:   comment.ID,
+		ParentID:    comment.ParentID,
+		User:        comment.AuthorName,
+		Comment:     comment.Content,
+		ContentHTML: comment.ContentHTML,
+		UpdatedAt:   comment.UpdatedAt,
+	}
+}
 
-// GetUser returns the user
-func (e *PostCommentEvent) GetUser() string { return e.User }
+// NewCommentDeletedEvent builds the broadcast for a deleted comment
+func NewCommentDeletedEvent(comment *models.Comment, deletedBy string) *CommentDeletedEvent {
+	return &CommentDeletedEvent{
+		Type:      "COMMENT_DELETED",
+		PostID:    comment.PostID,
+		CommentID: comment.ID,
+		ParentID:  comment.ParentID,
+		User:      deletedBy,
+	}
+}
+
+// IsCommentAuthor reports whether user is allowed to modify the comment
+func IsCommentAuthor(comment *models.Comment, user string) bool {
+	return user != "" && comment.AuthorID == user
+}
 
 // HandlePostComment processes post comment events with database persistence
 func (h *Handler) HandlePostComment(client shared.ClientInterface, messageBytes []byte) error {
@@ -52,37 +124,220 @@ func (h *Handler) HandlePostComment(client shared.ClientInterface, messageBytes
 		return err
 	}
 
-	// Set user from client if not provided
-	if event.User == "" {
-		event.User = client.GetUsername()
-	}
+	// Comments always come from the connected user, who alone may edit or delete them
+	event.User = client.GetUsername()
 
 	log.Printf("📝 Processing comment from %s on post %s: %s", event.User, event.PostID, event.Comment)
 
-	// STEP 1: Save comment to database first
+	// Replies must point at a comment on the same post
+	if event.ReplyTo != "" {
+		parent, err := h.commentRepository.GetCommentByID(event.ReplyTo)
+		if err != nil {
+			return fmt.Errorf("parent comment %s not found", event.ReplyTo)
+		}
+		if parent.PostID != event.PostID {
+			return fmt.Errorf("parent comment %s belongs to a different post", event.ReplyTo)
+		}
+	}
+
+	// Attachments must be the commenter's own, unused uploads
+	var attachments []*models.Attachment
+	if len(event.AttachmentIDs) > 0 {
+		var err error
+		if attachments, err = h.attachmentRepository.GetPendingAttachments(event.AttachmentIDs, client.GetUsername()); err != nil {
+			return err
+		}
+	}
+
+	// Moderate before anything is stored or broadcast; masking rewrites the text
+	verdict := h.moderator.CheckComment(event.Comment)
+	switch verdict.Action {
+	case moderation.Reject:
+		log.Printf("🛡️ Rejected comment from %s on post %s (%s)", event.User, event.PostID, verdict.Filter)
+		return shared.NewEventError(shared.ErrCodeContentRejected, verdict.Reason)
+	case moderation.Quarantine:
+		return h.holdComment(client, &event, verdict)
+	}
+	event.Comment = verdict.Content
+
+	// STEP 1: Save comment to database first, counting it on the post in the same transaction
 	comment := &models.Comment{
-		ID:         generateCommentID(),
-		PostID:     event.PostID,
-		AuthorName: event.User,
-		Content:    event.Comment,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ID:          generateCommentID(),
+		PostID:      event.PostID,
+		ParentID:    event.ReplyTo,
+		AuthorID:    client.GetUsername(),
+		AuthorName:  event.User,
+		Content:     event.Comment,
+		ContentHTML: markdown.Render(event.Comment),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
-	if err := h.commentRepository.CreateComment(comment); err != nil {
+	// Claim the attachments first, so a saved comment never misses them
+	if err := h.attachmentRepository.AttachTo(attachments, models.AttachmentTargetComment, comment.ID); err != nil {
+		log.Printf("❌ Failed to attach files to comment %s: %v", comment.ID, err)
+		return fmt.Errorf("failed to attach files: %v", err)
+	}
+
+	if err := repository.CreatePostComment(h.unitOfWork, comment); err != nil {
+		if releaseErr := h.attachmentRepository.Release(attachments); releaseErr != nil {
+			log.Printf("❌ Failed to release attachments of comment %s: %v", comment.ID, releaseErr)
+		}
+		if errors.Is(err, repository.ErrPostNotFound) {
+			return fmt.Errorf("post %s not found", event.PostID)
+		}
 		log.Printf("❌ Failed to save comment to database: %v", err)
 		return fmt.Errorf("failed to save comment: %v", err)
 	}
 
 	log.Printf("💾 Comment saved to database with ID: %s", comment.ID)
+	comment.Attachments = attachments
 
 	// STEP 2: Subscribe to this post if not already subscribed
 	client.GetHub().SubscribeToPost(client, event.PostID)
 
-	// STEP 3: Only broadcast after successful DB save
-	client.GetHub().BroadcastToPostSubscribers(event.PostID, &event)
+	// STEP 3: Only publish after successful DB save; the hub broadcasts it to
+	// post subscribers and the posts feed
+	h.bus.Publish(domain.Event{
+		Type:       domain.CommentCreated,
+		PostID:     event.PostID,
+		Comment:    comment,
+		Actor:      client.GetUsername(),
+		Source:     domain.SourceWebSocket,
+		OccurredAt: comment.CreatedAt,
+	})
+
+	// STEP 4: Notify @mentioned users, subscribed to the post or not
+	mentions.Record(client.GetHub(), h.mentionRepository, models.Mention{
+		SourceType: models.MentionSourceComment,
+		SourceID:   comment.ID,
+		PostID:     event.PostID,
+		Author:     client.GetUsername(),
+		CreatedAt:  comment.CreatedAt,
+	}, event.Comment, client.GetUsername())
+
+	return nil
+}
+
+// HandleEditComment processes comment edits from the comment author
+func (h *Handler) HandleEditComment(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event EditCommentEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid EDIT_COMMENT event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateEditComment(&event); err != nil {
+		return err
+	}
+
+	comment, err := h.commentRepository.GetCommentByID(event.CommentID)
+	if err != nil {
+		return fmt.Errorf("comment %s not found", event.CommentID)
+	}
+
+	// Only the author may edit; authorship follows the connection identity
+	if !IsCommentAuthor(comment, client.GetUsername()) {
+		return fmt.Errorf("only the comment author can edit this comment")
+	}
+
+	log.Printf("✏️ Editing comment %s on post %s by %s", comment.ID, comment.PostID, client.GetUsername())
+
+	// Edits can't wait in the review queue, so anything short of allow or mask is refused
+	verdict := h.moderator.CheckComment(event.Comment)
+	if err := EditRejection(verdict); err != nil {
+		log.Printf("🛡️ Rejected edit of comment %s by %s (%s)", comment.ID, client.GetUsername(), verdict.Filter)
+		return err
+	}
+
+	// STEP 1: Persist the edit
+	comment.Content = verdict.Content
+	comment.ContentHTML = markdown.Render(verdict.Content)
+	if err := h.commentRepository.UpdateComment(comment); err != nil {
+		log.Printf("❌ Failed to update comment in database: %v", err)
+		return fmt.Errorf("failed to update comment: %v", err)
+	}
 
-	log.Printf("📡 Comment broadcasted to post %s subscribers", event.PostID)
+	// STEP 2: Broadcast to everyone watching the post
+	client.GetHub().BroadcastToPostSubscribers(comment.PostID, NewCommentUpdatedEvent(comment))
+
+	log.Printf("📡 Comment update broadcasted to post %s subscribers", comment.PostID)
+	return nil
+}
+
+// HandleDeleteComment processes comment deletions from the comment author
+func (h *Handler) HandleDeleteComment(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event DeleteCommentEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid DELETE_COMMENT event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateDeleteComment(&event); err != nil {
+		return err
+	}
+
+	comment, err := h.commentRepository.GetCommentByID(event.CommentID)
+	if err != nil {
+		return fmt.Errorf("comment %s not found", event.CommentID)
+	}
+
+	// Only the author may delete
+	if !IsCommentAuthor(comment, client.GetUsername()) {
+		return fmt.Errorf("only the comment author can delete this comment")
+	}
+
+	log.Printf("🗑️ Deleting comment %s on post %s by %s", comment.ID, comment.PostID, client.GetUsername())
+
+	// STEP 1: Remove the thread from database and from the post's comment count
+	removed, err := repository.DeletePostComment(h.unitOfWork, comment)
+	if err != nil {
+		log.Printf("❌ Failed to delete comment from database: %v", err)
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+	h.attachmentService.RemoveBlobs(removed)
+
+	// STEP 2: Broadcast to everyone watching the post
+	client.GetHub().BroadcastToPostSubscribers(comment.PostID, NewCommentDeletedEvent(comment, client.GetUsername()))
+
+	log.Printf("📡 Comment deletion broadcasted to post %s subscribers", comment.PostID)
+	return nil
+}
+
+// holdComment queues a quarantined comment for review and tells only the commenter
+func (h *Handler) holdComment(client shared.ClientInterface, event *PostCommentEvent, verdict moderation.Verdict) error {
+	item := &models.ModerationItem{
+		TargetType:    models.ModerationTargetComment,
+		PostID:        event.PostID,
+		ParentID:      event.ReplyTo,
+		Author:        client.GetUsername(),
+		AttachmentIDs: event.AttachmentIDs,
+	}
+	if err := h.moderator.Hold(item, verdict); err != nil {
+		log.Printf("❌ Failed to queue comment for review: %v", err)
+		return fmt.Errorf("failed to queue comment for review: %v", err)
+	}
+
+	return client.GetHub().SendToClient(client, &shared.ContentHeldEvent{
+		Type:       "CONTENT_HELD",
+		QueueID:    item.ID,
+		TargetType: item.TargetType,
+		PostID:     item.PostID,
+		Reason:     item.Reason,
+	})
+}
+
+// EditRejection returns the CONTENT_REJECTED error for an edit the verdict doesn't allow.
+// Quarantined edits are refused too, since a published comment can't be held back.
+func EditRejection(verdict moderation.Verdict) *shared.EventError {
+	switch verdict.Action {
+	case moderation.Reject:
+		return shared.NewEventError(shared.ErrCodeContentRejected, verdict.Reason)
+	case moderation.Quarantine:
+		return shared.NewEventError(shared.ErrCodeContentRejected, "edit needs moderator review: "+verdict.Reason)
+	}
 	return nil
 }
 
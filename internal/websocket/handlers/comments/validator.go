@@ -22,8 +22,8 @@ func (v *Validator) ValidatePostComment(event *PostCommentEvent) error {
 	if event.PostID == "" {
 		return fmt.Errorf("post_id is required for comment")
 	}
-	if event.Comment == "" {
-		return fmt.Errorf("comment content is required")
+	if event.Comment == "" && len(event.AttachmentIDs) == 0 {
+		return fmt.Errorf("comment content or attachment is required")
 	}
 	if len(event.Comment) > 2000 {
 		return fmt.Errorf("comment too long (max 2000 characters)")
@@ -34,5 +34,36 @@ func (v *Validator) ValidatePostComment(event *PostCommentEvent) error {
 	if !v.postIDRegex.MatchString(event.PostID) {
 		return fmt.Errorf("invalid post_id format (only alphanumeric, dash, underscore allowed)")
 	}
+	if len(event.ReplyTo) > 100 {
+		return fmt.Errorf("reply_to too long (max 100 characters)")
+	}
+	if event.ReplyTo != "" && !v.postIDRegex.MatchString(event.ReplyTo) {
+		return fmt.Errorf("invalid reply_to format (only alphanumeric, dash, underscore allowed)")
+	}
+	if len(event.AttachmentIDs) > 10 {
+		return fmt.Errorf("too many attachments (max 10)")
+	}
+	return nil
+}
+
+// ValidateEditComment validates a comment edit event
+func (v *Validator) ValidateEditComment(event *EditCommentEvent) error {
+	if event.CommentID == "" {
+		return fmt.Errorf("comment_id is required")
+	}
+	if event.Comment == "" {
+		return fmt.Errorf("comment content is required")
+	}
+	if len(event.Comment) > 2000 {
+		return fmt.Errorf("comment too long (max 2000 characters)")
+	}
+	return nil
+}
+
+// ValidateDeleteComment validates a comment delete event
+func (v *Validator) ValidateDeleteComment(event *DeleteCommentEvent) error {
+	if event.CommentID == "" {
+		return fmt.Errorf("comment_id is required")
+	}
 	return nil
 }
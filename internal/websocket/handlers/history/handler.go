@@ -0,0 +1,136 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Handler answers history requests over the socket
+type Handler struct {
+	validator                *Validator
+	messageRepository        repository.MessageStore
+	commentRepository        repository.CommentStore
+	roomModerationRepository *repository.RoomModerationRepository
+}
+
+// NewHandler creates a new history handler
+func NewHandler(messageRepo repository.MessageStore, commentRepo repository.CommentStore, roomModerationRepo *repository.RoomModerationRepository) *Handler {
+	return &Handler{
+		validator:                NewValidator(),
+		messageRepository:        messageRepo,
+		commentRepository:        commentRepo,
+		roomModerationRepository: roomModerationRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	FetchHistoryEvent = protocol.FetchHistoryEvent
+	HistoryPageEvent  = protocol.HistoryPageEvent
+)
+
+// HandleFetchHistory processes FETCH_HISTORY events
+func (h *Handler) HandleFetchHistory(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event FetchHistoryEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid FETCH_HISTORY event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateFetchHistory(&event); err != nil {
+		return err
+	}
+
+	// Banned users may not read the room they were banned from
+	if event.Room != "" {
+		if err := sanctions.CheckBanned(h.roomModerationRepository, event.Room, client.GetUsername()); err != nil {
+			return err
+		}
+	}
+
+	var before, after *repository.Cursor
+	var err error
+	if event.Before != "" {
+		if before, err = repository.DecodeCursor(event.Before); err != nil {
+			return fmt.Errorf("invalid before cursor")
+		}
+	}
+	if event.After != "" {
+		if after, err = repository.DecodeCursor(event.After); err != nil {
+			return fmt.Errorf("invalid after cursor")
+		}
+	}
+
+	page := &HistoryPageEvent{
+		Type:      "HISTORY_PAGE",
+		Room:      event.Room,
+		PostID:    event.PostID,
+		RequestID: event.RequestID,
+	}
+
+	if event.Room != "" {
+		err = h.fetchMessages(page, event.Room, before, after, event.Limit)
+	} else {
+		err = h.fetchComments(page, event.PostID, before, after, event.Limit)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to fetch history: %v", err)
+		return fmt.Errorf("failed to fetch history")
+	}
+
+	return client.GetHub().SendToClient(client, page)
+}
+
+func (h *Handler) fetchMessages(page *HistoryPageEvent, roomID string, before, after *repository.Cursor, limit int) error {
+	var messages []*models.Message
+	var err error
+	if after != nil {
+		messages, page.HasMore, err = h.messageRepository.GetMessagesAfter(roomID, after, limit)
+	} else {
+		messages, page.HasMore, err = h.messageRepository.GetMessagesBefore(roomID, before, limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	page.Messages = messages
+	if len(messages) > 0 {
+		oldest, newest := messages[0], messages[len(messages)-1]
+		if after == nil && page.HasMore {
+			page.NextCursor = repository.NewCursor(oldest.Timestamp, oldest.ID).Encode()
+		}
+		page.PrevCursor = repository.NewCursor(newest.Timestamp, newest.ID).Encode()
+	}
+	return nil
+}
+
+func (h *Handler) fetchComments(page *HistoryPageEvent, postID string, before, after *repository.Cursor, limit int) error {
+	var comments []*models.Comment
+	var err error
+	if after != nil {
+		comments, page.HasMore, err = h.commentRepository.GetCommentsAfter(postID, after, limit)
+	} else {
+		comments, page.HasMore, err = h.commentRepository.GetCommentsBefore(postID, before, limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	page.Comments = comments
+	if len(comments) > 0 {
+		oldest, newest := comments[0], comments[len(comments)-1]
+		if after == nil && page.HasMore {
+			page.NextCursor = repository.NewCursor(oldest.CreatedAt, oldest.ID).Encode()
+		}
+		page.PrevCursor = repository.NewCursor(newest.CreatedAt, newest.ID).Encode()
+	}
+	return nil
+}
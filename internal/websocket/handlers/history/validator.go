@@ -0,0 +1,43 @@
+package history
+
+import "fmt"
+
+// Page size bounds for history requests
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+// Validator handles validation for history events
+type Validator struct{}
+
+// NewValidator creates a new history validator
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateFetchHistory validates a history request and applies the default page size
+func (v *Validator) ValidateFetchHistory(event *FetchHistoryEvent) error {
+	if (event.Room == "") == (event.PostID == "") {
+		return fmt.Errorf("exactly one of room or post_id is required")
+	}
+	if len(event.Room) > 50 {
+		return fmt.Errorf("room name too long (max 50 characters)")
+	}
+	if len(event.PostID) > 100 {
+		return fmt.Errorf("post_id too long (max 100 characters)")
+	}
+	if event.Before != "" && event.After != "" {
+		return fmt.Errorf("before and after cannot be combined")
+	}
+	if event.Limit < 0 {
+		return fmt.Errorf("limit must be positive")
+	}
+	if event.Limit == 0 {
+		event.Limit = defaultPageSize
+	}
+	if event.Limit > maxPageSize {
+		event.Limit = maxPageSize
+	}
+	return nil
+}
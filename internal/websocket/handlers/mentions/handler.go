@@ -0,0 +1,91 @@
+package mentions
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Mention limits
+const (
+	maxMentionsPerSource = 20  // Extra names in one message are ignored
+	maxExcerptLength     = 200 // Characters of the source text kept with a mention
+)
+
+// mentionPattern matches @username where the @ does not follow a word character,
+// so email addresses are not treated as mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([\p{L}\p{N}_][\p{L}\p{N}_.-]{0,49})`)
+
+// MentionEvent is defined in pkg/protocol so Go clients share it
+type MentionEvent = protocol.MentionEvent
+
+// ParseMentions returns the distinct usernames mentioned in content, in order of appearance
+func ParseMentions(content string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		// Trailing punctuation ends a sentence rather than a username
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerSource {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// Record stores the mentions found in content and notifies each newly mentioned user,
+// wherever they are connected. source carries everything except the mentioned username;
+// authors mentioning themselves are skipped.
+func Record(hub shared.HubInterface, mentionRepo *repository.MentionRepository, source models.Mention, content string, authorUsername string) {
+	usernames := ParseMentions(content)
+	if len(usernames) == 0 {
+		return
+	}
+
+	source.Excerpt = excerpt(content)
+
+	var mentions []*models.Mention
+	for _, username := range usernames {
+		if username == authorUsername || username == source.Author {
+			continue
+		}
+		mention := source
+		mention.Username = username
+		mentions = append(mentions, &mention)
+	}
+
+	created, err := mentionRepo.CreateMentions(mentions)
+	if err != nil {
+		log.Printf("❌ Failed to store mentions for %s %s: %v", source.SourceType, source.SourceID, err)
+	}
+
+	for _, mention := range created {
+		hub.SendToUser(mention.Username, &MentionEvent{
+			Type:    "MENTION",
+			Mention: *mention,
+		})
+		log.Printf("📣 %s mentioned %s in %s %s", mention.Author, mention.Username, mention.SourceType, mention.SourceID)
+	}
+}
+
+// excerpt shortens content to maxExcerptLength characters
+func excerpt(content string) string {
+	if utf8.RuneCountInString(content) <= maxExcerptLength {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:maxExcerptLength]) + "…"
+}
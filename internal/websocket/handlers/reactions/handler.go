@@ -0,0 +1,147 @@
+package reactions
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Reactions are rate-limited independently of chat messages
+const (
+	reactionRateLimit  = 20
+	reactionRateWindow = 10 * time.Second
+)
+
+// Handler handles reaction-related WebSocket events
+type Handler struct {
+	validator          *Validator
+	limiter            *shared.RateLimiter
+	reactionRepository *repository.ReactionRepository
+	messageRepository  repository.MessageStore
+	commentRepository  repository.CommentStore
+	roomModerationRepo *repository.RoomModerationRepository
+}
+
+// NewHandler creates a new reactions handler
+func NewHandler(reactionRepo *repository.ReactionRepository, messageRepo repository.MessageStore, commentRepo repository.CommentStore, roomModerationRepo *repository.RoomModerationRepository) *Handler {
+	return &Handler{
+		validator:          NewValidator(),
+		limiter:            shared.NewRateLimiter(reactionRateLimit, reactionRateWindow),
+		reactionRepository: reactionRepo,
+		messageRepository:  messageRepo,
+		commentRepository:  commentRepo,
+		roomModerationRepo: roomModerationRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	ReactionEvent        = protocol.ReactionEvent
+	ReactionUpdatedEvent = protocol.ReactionUpdatedEvent
+)
+
+// HandleAddReaction processes REACTION_ADD events
+func (h *Handler) HandleAddReaction(client shared.ClientInterface, messageBytes []byte) error {
+	return h.handleReaction(client, messageBytes, "add")
+}
+
+// HandleRemoveReaction processes REACTION_REMOVE events
+func (h *Handler) HandleRemoveReaction(client shared.ClientInterface, messageBytes []byte) error {
+	return h.handleReaction(client, messageBytes, "remove")
+}
+
+func (h *Handler) handleReaction(client shared.ClientInterface, messageBytes []byte, action string) error {
+	// Parse event
+	var event ReactionEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid reaction event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateReaction(&event); err != nil {
+		return err
+	}
+
+	if !h.limiter.Allow(client.GetUsername()) {
+		return shared.NewEventError(shared.ErrCodeRateLimited, "too many reactions, slow down")
+	}
+
+	// Reactions always belong to the connected user
+	event.User = client.GetUsername()
+
+	// Resolve where the target lives so we know who to notify
+	broadcast := &ReactionUpdatedEvent{
+		Type:       "REACTION_UPDATED",
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		User:       event.User,
+		Emoji:      event.Emoji,
+		Action:     action,
+	}
+	switch event.TargetType {
+	case models.ReactionTargetMessage:
+		message, err := h.messageRepository.GetMessageByID(event.TargetID)
+		if err != nil {
+			return fmt.Errorf("message %s not found", event.TargetID)
+		}
+		broadcast.Room = message.RoomID
+
+		// Banned and muted users can't react in the room either
+		if err := sanctions.CheckBanned(h.roomModerationRepo, message.RoomID, event.User); err != nil {
+			return err
+		}
+		if err := sanctions.CheckMuted(h.roomModerationRepo, message.RoomID, event.User); err != nil {
+			return err
+		}
+	case models.ReactionTargetComment:
+		comment, err := h.commentRepository.GetCommentByID(event.TargetID)
+		if err != nil {
+			return fmt.Errorf("comment %s not found", event.TargetID)
+		}
+		broadcast.PostID = comment.PostID
+	}
+
+	// STEP 1: Persist; repeated adds/removes are no-ops
+	var changed bool
+	var err error
+	if action == "add" {
+		changed, err = h.reactionRepository.AddReaction(&models.Reaction{
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			Username:   event.User,
+			Emoji:      event.Emoji,
+		})
+	} else {
+		changed, err = h.reactionRepository.RemoveReaction(event.TargetType, event.TargetID, event.User, event.Emoji)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to %s reaction: %v", action, err)
+		return fmt.Errorf("failed to %s reaction: %v", action, err)
+	}
+	if !changed {
+		return nil
+	}
+
+	// STEP 2: Broadcast the new totals
+	broadcast.Reactions, err = h.reactionRepository.GetReactionSummary(event.TargetType, event.TargetID)
+	if err != nil {
+		log.Printf("❌ Failed to load reaction summary: %v", err)
+		return fmt.Errorf("failed to load reactions: %v", err)
+	}
+
+	if broadcast.Room != "" {
+		client.GetHub().BroadcastToChatRoom(broadcast.Room, broadcast)
+	} else {
+		client.GetHub().BroadcastToPostSubscribers(broadcast.PostID, broadcast)
+	}
+
+	log.Printf("😀 Reaction %s %s on %s %s by %s", action, event.Emoji, event.TargetType, event.TargetID, event.User)
+	return nil
+}
@@ -0,0 +1,62 @@
+package reactions
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"websocket/internal/models"
+)
+
+// Validator handles validation for reaction events
+type Validator struct{}
+
+// NewValidator creates a new reactions validator
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateReaction validates a reaction add/remove event
+func (v *Validator) ValidateReaction(event *ReactionEvent) error {
+	if event.TargetType != models.ReactionTargetMessage && event.TargetType != models.ReactionTargetComment {
+		return fmt.Errorf("target_type must be 'message' or 'comment'")
+	}
+	if event.TargetID == "" {
+		return fmt.Errorf("target_id is required")
+	}
+	if len(event.TargetID) > 100 {
+		return fmt.Errorf("target_id too long (max 100 characters)")
+	}
+	if event.Emoji == "" {
+		return fmt.Errorf("emoji is required")
+	}
+	if len(event.Emoji) > 32 || utf8.RuneCountInString(event.Emoji) > 8 {
+		return fmt.Errorf("emoji too long")
+	}
+	if !isEmoji(event.Emoji) {
+		return fmt.Errorf("invalid emoji")
+	}
+	return nil
+}
+
+// isEmoji accepts symbol sequences (including ZWJ and skin-tone modifiers)
+// and rejects plain text, whitespace and control characters
+func isEmoji(s string) bool {
+	hasSymbol := false
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r), unicode.IsControl(r):
+			return false
+		case r < 0x80:
+			// ASCII is only allowed inside keycap sequences like 1️⃣
+			if !unicode.IsDigit(r) && r != '#' && r != '*' {
+				return false
+			}
+		case unicode.IsLetter(r):
+			return false
+		default:
+			hasSymbol = true
+		}
+	}
+	return hasSymbol
+}
@@ -0,0 +1,169 @@
+package receipts
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Handler handles read receipt WebSocket events
+type Handler struct {
+	validator             *Validator
+	readReceiptRepository *repository.ReadReceiptRepository
+	roomModerationRepo    *repository.RoomModerationRepository
+}
+
+// NewHandler creates a new receipts handler
+func NewHandler(readReceiptRepo *repository.ReadReceiptRepository, roomModerationRepo *repository.RoomModerationRepository) *Handler {
+	return &Handler{
+		validator:             NewValidator(),
+		readReceiptRepository: readReceiptRepo,
+		roomModerationRepo:    roomModerationRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	MarkReadEvent     = protocol.MarkReadEvent
+	ReadReceiptEvent  = protocol.ReadReceiptEvent
+	UnreadUpdateEvent = protocol.UnreadUpdateEvent
+)
+
+// HandleMarkRead processes MARK_READ events
+func (h *Handler) HandleMarkRead(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event MarkReadEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid MARK_READ event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateMarkRead(&event); err != nil {
+		return err
+	}
+
+	// Receipts are always recorded for the connected user
+	event.User = client.GetUsername()
+
+	// Receipts are broadcast to the room, so banned and muted users can't send them
+	if err := sanctions.CheckBanned(h.roomModerationRepo, event.Room, event.User); err != nil {
+		return err
+	}
+	if err := sanctions.CheckMuted(h.roomModerationRepo, event.Room, event.User); err != nil {
+		return err
+	}
+
+	// STEP 1: Move the read position forward
+	state, changed, err := h.readReceiptRepository.MarkRead(event.User, event.Room, event.MessageID)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	log.Printf("👀 %s read room %s up to %s", event.User, event.Room, state.LastReadMessageID)
+
+	// STEP 2: Let the room know, and sync the reader's other connections
+	client.GetHub().BroadcastToChatRoom(event.Room, &ReadReceiptEvent{
+		Type:      "READ_RECEIPT",
+		Room:      event.Room,
+		User:      event.User,
+		MessageID: state.LastReadMessageID,
+		ReadAt:    state.UpdatedAt,
+	})
+
+	SendUnreadUpdate(client.GetHub(), h.readReceiptRepository, event.User, event.Room)
+	return nil
+}
+
+// SendUnreadUpdate pushes a user's current unread count for a room to all their connections
+func SendUnreadUpdate(hub shared.HubInterface, readReceiptRepo *repository.ReadReceiptRepository, username, roomID string) {
+	unread, err := readReceiptRepo.GetUnreadCount(username, roomID)
+	if err != nil {
+		log.Printf("❌ Failed to count unread messages for %s in %s: %v", username, roomID, err)
+		return
+	}
+
+	hub.SendToUser(username, &UnreadUpdateEvent{
+		Type:   "UNREAD_UPDATE",
+		Room:   roomID,
+		Unread: unread,
+	})
+}
+
+// roomNotifications coalesces unread refreshes per room: while one is being
+// computed, new messages in the room only mark it to run once more
+var roomNotifications = struct {
+	sync.Mutex
+	pending map[string]*pendingNotification
+}{pending: make(map[string]*pendingNotification)}
+
+type pendingNotification struct {
+	rerun   bool
+	senders map[string]bool
+}
+
+// NotifyNewMessage refreshes unread counts for every online room member except
+// the sender. The counts are computed in the background, so the sender's read
+// loop never waits on them.
+func NotifyNewMessage(hub shared.HubInterface, readReceiptRepo *repository.ReadReceiptRepository, roomID, sender string) {
+	roomNotifications.Lock()
+	defer roomNotifications.Unlock()
+
+	if pending, running := roomNotifications.pending[roomID]; running {
+		pending.rerun = true
+		pending.senders[sender] = true
+		return
+	}
+	roomNotifications.pending[roomID] = &pendingNotification{senders: map[string]bool{sender: true}}
+	go notifyRoom(hub, readReceiptRepo, roomID)
+}
+
+// notifyRoom sends unread updates until no new message arrived during the last round
+func notifyRoom(hub shared.HubInterface, readReceiptRepo *repository.ReadReceiptRepository, roomID string) {
+	for {
+		roomNotifications.Lock()
+		pending := roomNotifications.pending[roomID]
+		senders := pending.senders
+		pending.rerun, pending.senders = false, make(map[string]bool)
+		roomNotifications.Unlock()
+
+		sendRoomUnreadUpdates(hub, readReceiptRepo, roomID, senders)
+
+		roomNotifications.Lock()
+		if !pending.rerun {
+			delete(roomNotifications.pending, roomID)
+			roomNotifications.Unlock()
+			return
+		}
+		roomNotifications.Unlock()
+	}
+}
+
+// sendRoomUnreadUpdates pushes fresh counts to online members. A member's own
+// messages never count as unread, so one who was the only sender is skipped.
+func sendRoomUnreadUpdates(hub shared.HubInterface, readReceiptRepo *repository.ReadReceiptRepository, roomID string, senders map[string]bool) {
+	counts, err := readReceiptRepo.GetRoomUnreadCounts(roomID)
+	if err != nil {
+		log.Printf("❌ Failed to count unread messages in room %s: %v", roomID, err)
+		return
+	}
+
+	for member, unread := range counts {
+		if (len(senders) == 1 && senders[member]) || !hub.IsUserOnline(member) {
+			continue
+		}
+		hub.SendToUser(member, &UnreadUpdateEvent{
+			Type:   "UNREAD_UPDATE",
+			Room:   roomID,
+			Unread: unread,
+		})
+	}
+}
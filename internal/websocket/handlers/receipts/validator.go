@@ -0,0 +1,25 @@
+package receipts
+
+import "fmt"
+
+// Validator handles validation for read receipt events
+type Validator struct{}
+
+// NewValidator creates a new receipts validator
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateMarkRead validates a mark read event
+func (v *Validator) ValidateMarkRead(event *MarkReadEvent) error {
+	if event.Room == "" {
+		return fmt.Errorf("room name is required")
+	}
+	if len(event.Room) > 50 {
+		return fmt.Errorf("room name too long (max 50 characters)")
+	}
+	if len(event.MessageID) > 100 {
+		return fmt.Errorf("message_id too long (max 100 characters)")
+	}
+	return nil
+}
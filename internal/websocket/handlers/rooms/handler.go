@@ -5,46 +5,33 @@ import (
 	"fmt"
 	"log"
 
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/sanctions"
 	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
 )
 
 // Handler handles room-related WebSocket events
 type Handler struct {
-	validator *Validator
+	validator             *Validator
+	readReceiptRepository *repository.ReadReceiptRepository
+	roomModerationRepo    *repository.RoomModerationRepository
 }
 
 // NewHandler creates a new rooms handler
-func NewHandler() *Handler {
+func NewHandler(readReceiptRepo *repository.ReadReceiptRepository, roomModerationRepo *repository.RoomModerationRepository) *Handler {
 	return &Handler{
-		validator: NewValidator(),
+		validator:             NewValidator(),
+		readReceiptRepository: readReceiptRepo,
+		roomModerationRepo:    roomModerationRepo,
 	}
 }
 
-// JoinRoomEvent represents a room join event
-type JoinRoomEvent struct {
-	Type string `json:"type"` // "JOIN_ROOM"
-	Room string `json:"room"` // Room name to join
-	User string `json:"user"` // Username
-}
-
-// RoomJoinedEvent represents a room joined confirmation
-type RoomJoinedEvent struct {
-	Type string `json:"type"` // "ROOM_JOINED"
-	Room string `json:"room"` // Room that was joined
-	User string `json:"user"` // Username who joined
-}
-
-// GetType returns the event type
-func (e *JoinRoomEvent) GetType() string { return e.Type }
-
-// GetUser returns the user
-func (e *JoinRoomEvent) GetUser() string { return e.User }
-
-// GetType returns the event type
-func (e *RoomJoinedEvent) GetType() string { return e.Type }
-
-// GetUser returns the user
-func (e *RoomJoinedEvent) GetUser() string { return e.User }
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	JoinRoomEvent   = protocol.JoinRoomEvent
+	RoomJoinedEvent = protocol.RoomJoinedEvent
+)
 
 // HandleJoinRoom processes room join requests
 func (h *Handler) HandleJoinRoom(client shared.ClientInterface, messageBytes []byte) error {
@@ -64,11 +51,22 @@ func (h *Handler) HandleJoinRoom(client shared.ClientInterface, messageBytes []b
 		event.User = client.GetUsername()
 	}
 
+	// Banned users can't rejoin until the ban is lifted or expires
+	if err := sanctions.CheckBanned(h.roomModerationRepo, event.Room, client.GetUsername()); err != nil {
+		log.Printf("🚫 Refused banned user %s joining room: %s", client.GetUsername(), event.Room)
+		return err
+	}
+
 	log.Printf("🏠 Client %s joining room: %s", event.User, event.Room)
 
 	// Join the chat room
 	client.GetHub().JoinChatRoom(client, event.Room)
 
+	// Remember membership so unread counts follow the user across sessions
+	if err := h.readReceiptRepository.EnsureMember(client.GetUsername(), event.Room); err != nil {
+		log.Printf("❌ Failed to record membership of %s in %s: %v", client.GetUsername(), event.Room, err)
+	}
+
 	// Send confirmation back to client
 	response := &RoomJoinedEvent{
 		Type: "ROOM_JOINED",
@@ -0,0 +1,240 @@
+package sanctions
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Handler handles room moderation WebSocket events: bans and timed mutes
+type Handler struct {
+	validator          *Validator
+	roomModerationRepo *repository.RoomModerationRepository
+}
+
+// NewHandler creates a new sanctions handler
+func NewHandler(roomModerationRepo *repository.RoomModerationRepository) *Handler {
+	return &Handler{
+		validator:          NewValidator(),
+		roomModerationRepo: roomModerationRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	SanctionEvent       = protocol.SanctionEvent
+	SanctionNoticeEvent = protocol.SanctionNoticeEvent
+)
+
+// HandleBanUser bans a user from a room, optionally for a limited time, and kicks their connections
+func (h *Handler) HandleBanUser(client shared.ClientInterface, messageBytes []byte) error {
+	event, err := h.parse(messageBytes, "BAN_USER")
+	if err != nil {
+		return err
+	}
+	if err := h.validator.ValidateBan(event, client.GetUsername()); err != nil {
+		return err
+	}
+
+	// STEP 1: Only moderators can ban, and only users they outrank
+	if err := h.authorize(client.GetUsername(), event.Room, event.Target); err != nil {
+		return err
+	}
+
+	// STEP 2: Persist the ban
+	sanction := h.newSanction(client, event, models.SanctionBan)
+	if err := h.roomModerationRepo.Sanction(sanction); err != nil {
+		log.Printf("❌ Failed to ban %s from %s: %v", event.Target, event.Room, err)
+		return fmt.Errorf("failed to ban user: %v", err)
+	}
+
+	log.Printf("🔨 %s banned %s from room %s", client.GetUsername(), event.Target, event.Room)
+
+	// STEP 3: Kick the banned user's connections, then tell them and the room
+	client.GetHub().RemoveUserFromRoom(event.Target, event.Room)
+
+	notice := newNotice("USER_BANNED", sanction)
+	client.GetHub().SendToUser(event.Target, notice)
+	client.GetHub().BroadcastToChatRoom(event.Room, notice)
+	return nil
+}
+
+// HandleUnbanUser lifts a user's ban from a room
+func (h *Handler) HandleUnbanUser(client shared.ClientInterface, messageBytes []byte) error {
+	event, err := h.parse(messageBytes, "UNBAN_USER")
+	if err != nil {
+		return err
+	}
+	if err := h.validator.ValidateSanction(event, client.GetUsername()); err != nil {
+		return err
+	}
+
+	if err := h.authorize(client.GetUsername(), event.Room, event.Target); err != nil {
+		return err
+	}
+
+	lifted, err := h.roomModerationRepo.LiftSanction(event.Room, event.Target, models.SanctionBan)
+	if err != nil {
+		log.Printf("❌ Failed to unban %s from %s: %v", event.Target, event.Room, err)
+		return fmt.Errorf("failed to unban user: %v", err)
+	}
+	if !lifted {
+		return fmt.Errorf("%s is not banned from room %s", event.Target, event.Room)
+	}
+
+	log.Printf("🔓 %s unbanned %s from room %s", client.GetUsername(), event.Target, event.Room)
+
+	notice := &SanctionNoticeEvent{Type: "USER_UNBANNED", Room: event.Room, User: event.Target, By: client.GetUsername()}
+	client.GetHub().SendToUser(event.Target, notice)
+	client.GetHub().BroadcastToChatRoom(event.Room, notice)
+	return nil
+}
+
+// HandleMuteUser stops a user from sending messages to a room for a while
+func (h *Handler) HandleMuteUser(client shared.ClientInterface, messageBytes []byte) error {
+	event, err := h.parse(messageBytes, "MUTE_USER")
+	if err != nil {
+		return err
+	}
+	if err := h.validator.ValidateMute(event, client.GetUsername()); err != nil {
+		return err
+	}
+
+	if err := h.authorize(client.GetUsername(), event.Room, event.Target); err != nil {
+		return err
+	}
+
+	sanction := h.newSanction(client, event, models.SanctionMute)
+	if err := h.roomModerationRepo.Sanction(sanction); err != nil {
+		log.Printf("❌ Failed to mute %s in %s: %v", event.Target, event.Room, err)
+		return fmt.Errorf("failed to mute user: %v", err)
+	}
+
+	log.Printf("🔇 %s muted %s in room %s for %ds", client.GetUsername(), event.Target, event.Room, event.DurationSeconds)
+
+	client.GetHub().BroadcastToChatRoom(event.Room, newNotice("USER_MUTED", sanction))
+	return nil
+}
+
+// HandleUnmuteUser lifts a mute before it expires
+func (h *Handler) HandleUnmuteUser(client shared.ClientInterface, messageBytes []byte) error {
+	event, err := h.parse(messageBytes, "UNMUTE_USER")
+	if err != nil {
+		return err
+	}
+	if err := h.validator.ValidateSanction(event, client.GetUsername()); err != nil {
+		return err
+	}
+
+	if err := h.authorize(client.GetUsername(), event.Room, event.Target); err != nil {
+		return err
+	}
+
+	lifted, err := h.roomModerationRepo.LiftSanction(event.Room, event.Target, models.SanctionMute)
+	if err != nil {
+		log.Printf("❌ Failed to unmute %s in %s: %v", event.Target, event.Room, err)
+		return fmt.Errorf("failed to unmute user: %v", err)
+	}
+	if !lifted {
+		return fmt.Errorf("%s is not muted in room %s", event.Target, event.Room)
+	}
+
+	log.Printf("🔊 %s unmuted %s in room %s", client.GetUsername(), event.Target, event.Room)
+
+	client.GetHub().BroadcastToChatRoom(event.Room, &SanctionNoticeEvent{
+		Type: "USER_UNMUTED",
+		Room: event.Room,
+		User: event.Target,
+		By:   client.GetUsername(),
+	})
+	return nil
+}
+
+func (h *Handler) parse(messageBytes []byte, eventType string) (*SanctionEvent, error) {
+	var event SanctionEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return nil, fmt.Errorf("invalid %s event: %v", eventType, err)
+	}
+	return &event, nil
+}
+
+// authorize checks that actor moderates room and outranks target there
+func (h *Handler) authorize(actor, room, target string) error {
+	actorRole, err := h.roomModerationRepo.GetRole(room, actor)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %v", err)
+	}
+	if models.RoleRank(actorRole) == 0 {
+		return shared.NewEventError(shared.ErrCodeForbidden, "only room moderators and owners can do that")
+	}
+
+	targetRole, err := h.roomModerationRepo.GetRole(room, target)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %v", err)
+	}
+	if models.RoleRank(targetRole) >= models.RoleRank(actorRole) {
+		return shared.NewEventError(shared.ErrCodeForbidden, fmt.Sprintf("you cannot moderate a room %s", targetRole))
+	}
+	return nil
+}
+
+func (h *Handler) newSanction(client shared.ClientInterface, event *SanctionEvent, kind string) *models.RoomSanction {
+	now := time.Now()
+	sanction := &models.RoomSanction{
+		RoomID:    event.Room,
+		Username:  event.Target,
+		Kind:      kind,
+		Reason:    event.Reason,
+		IssuedBy:  client.GetUsername(),
+		CreatedAt: now,
+	}
+	if event.DurationSeconds > 0 {
+		expiresAt := now.Add(time.Duration(event.DurationSeconds) * time.Second)
+		sanction.ExpiresAt = &expiresAt
+	}
+	return sanction
+}
+
+func newNotice(eventType string, sanction *models.RoomSanction) *SanctionNoticeEvent {
+	return &SanctionNoticeEvent{
+		Type:      eventType,
+		Room:      sanction.RoomID,
+		User:      sanction.Username,
+		By:        sanction.IssuedBy,
+		Reason:    sanction.Reason,
+		ExpiresAt: sanction.ExpiresAt,
+	}
+}
+
+// CheckBanned returns a BANNED error if username is currently banned from room
+func CheckBanned(repo *repository.RoomModerationRepository, room, username string) error {
+	ban, err := repo.GetActiveSanction(room, username, models.SanctionBan)
+	if err != nil {
+		return fmt.Errorf("failed to check room ban: %v", err)
+	}
+	if ban == nil {
+		return nil
+	}
+	if ban.ExpiresAt == nil {
+		return shared.NewEventError(shared.ErrCodeBanned, fmt.Sprintf("you are banned from room %s", room))
+	}
+	return shared.NewEventError(shared.ErrCodeBanned, fmt.Sprintf("you are banned from room %s until %s", room, ban.ExpiresAt.Format(time.RFC3339)))
+}
+
+// CheckMuted returns a MUTED error if username is currently muted in room
+func CheckMuted(repo *repository.RoomModerationRepository, room, username string) error {
+	mute, err := repo.GetActiveSanction(room, username, models.SanctionMute)
+	if err != nil {
+		return fmt.Errorf("failed to check room mute: %v", err)
+	}
+	if mute == nil {
+		return nil
+	}
+	return shared.NewEventError(shared.ErrCodeMuted, fmt.Sprintf("you are muted in room %s until %s", room, mute.ExpiresAt.Format(time.RFC3339)))
+}
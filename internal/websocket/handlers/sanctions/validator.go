@@ -0,0 +1,72 @@
+package sanctions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sanction duration limits in seconds
+const (
+	maxMuteSeconds = 30 * 24 * 60 * 60  // 30 days
+	maxBanSeconds  = 365 * 24 * 60 * 60 // 1 year; 0 bans permanently
+	maxReasonChars = 200
+)
+
+// Validator handles validation for room moderation events
+type Validator struct{}
+
+// NewValidator creates a new sanctions validator
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateSanction validates the fields shared by every moderation event
+func (v *Validator) ValidateSanction(event *SanctionEvent, actor string) error {
+	if event.Room == "" {
+		return fmt.Errorf("room name is required")
+	}
+	if len(event.Room) > 50 {
+		return fmt.Errorf("room name too long (max 50 characters)")
+	}
+	if strings.TrimSpace(event.Target) == "" {
+		return fmt.Errorf("target is required")
+	}
+	if len(event.Target) > 50 {
+		return fmt.Errorf("target too long (max 50 characters)")
+	}
+	if event.Target == actor {
+		return fmt.Errorf("you cannot moderate yourself")
+	}
+	if len(event.Reason) > maxReasonChars {
+		return fmt.Errorf("reason too long (max %d characters)", maxReasonChars)
+	}
+	return nil
+}
+
+// ValidateMute validates a MUTE_USER event; mutes always expire
+func (v *Validator) ValidateMute(event *SanctionEvent, actor string) error {
+	if err := v.ValidateSanction(event, actor); err != nil {
+		return err
+	}
+	if event.DurationSeconds <= 0 {
+		return fmt.Errorf("duration_seconds is required for mutes")
+	}
+	if event.DurationSeconds > maxMuteSeconds {
+		return fmt.Errorf("duration_seconds too long (max %d)", maxMuteSeconds)
+	}
+	return nil
+}
+
+// ValidateBan validates a BAN_USER event; a zero duration bans permanently
+func (v *Validator) ValidateBan(event *SanctionEvent, actor string) error {
+	if err := v.ValidateSanction(event, actor); err != nil {
+		return err
+	}
+	if event.DurationSeconds < 0 {
+		return fmt.Errorf("duration_seconds cannot be negative")
+	}
+	if event.DurationSeconds > maxBanSeconds {
+		return fmt.Errorf("duration_seconds too long (max %d)", maxBanSeconds)
+	}
+	return nil
+}
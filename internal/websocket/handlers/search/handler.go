@@ -0,0 +1,88 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/sanctions"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Searches hit every FTS index, so they are limited more tightly than chat
+const (
+	searchRateLimit  = 10
+	searchRateWindow = 10 * time.Second
+)
+
+// Handler answers in-chat search requests
+type Handler struct {
+	validator                *Validator
+	limiter                  *shared.RateLimiter
+	searchRepository         *repository.SearchRepository
+	roomModerationRepository *repository.RoomModerationRepository
+}
+
+// NewHandler creates a new search handler
+func NewHandler(searchRepo *repository.SearchRepository, roomModerationRepo *repository.RoomModerationRepository) *Handler {
+	return &Handler{
+		validator:                NewValidator(),
+		limiter:                  shared.NewRateLimiter(searchRateLimit, searchRateWindow),
+		searchRepository:         searchRepo,
+		roomModerationRepository: roomModerationRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	SearchEvent        = protocol.SearchEvent
+	SearchResultsEvent = protocol.SearchResultsEvent
+)
+
+// HandleSearch processes SEARCH events
+func (h *Handler) HandleSearch(client shared.ClientInterface, messageBytes []byte) error {
+	if !h.limiter.Allow(client.GetUsername()) {
+		return shared.NewEventError(shared.ErrCodeRateLimited, "too many searches, slow down")
+	}
+
+	// Parse event
+	var event SearchEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid SEARCH event: %v", err)
+	}
+
+	// Validate event
+	query, err := h.validator.ValidateSearch(&event)
+	if err != nil {
+		return err
+	}
+
+	// Banned users may not search the room they were banned from, and unscoped
+	// searches leave out its messages
+	query.Viewer = client.GetUsername()
+	if query.RoomID != "" {
+		if err := sanctions.CheckBanned(h.roomModerationRepository, query.RoomID, client.GetUsername()); err != nil {
+			return err
+		}
+	}
+
+	results, err := h.searchRepository.Search(query)
+	if err != nil {
+		log.Printf("❌ Search failed for %s: %v", client.GetUsername(), err)
+		return fmt.Errorf("search failed")
+	}
+
+	log.Printf("🔎 %s searched %q in %s (%d results)", client.GetUsername(), query.Text, query.Scope, len(results))
+
+	return client.GetHub().SendToClient(client, &SearchResultsEvent{
+		Type:      "SEARCH_RESULTS",
+		Query:     query.Text,
+		Scope:     query.Scope,
+		Results:   results,
+		Offset:    query.Offset,
+		RequestID: event.RequestID,
+	})
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"fmt"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+)
+
+// Validator handles validation for search events
+type Validator struct{}
+
+// NewValidator creates a new search validator
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// ValidateSearch validates a search event and converts it into a repository query
+func (v *Validator) ValidateSearch(event *SearchEvent) (models.SearchQuery, error) {
+	query := models.SearchQuery{
+		Text:   event.Query,
+		Scope:  event.Scope,
+		RoomID: event.Room,
+		Author: event.Author,
+		Limit:  event.Limit,
+		Offset: event.Offset,
+	}
+
+	if len(event.Room) > 50 {
+		return query, fmt.Errorf("room name too long (max 50 characters)")
+	}
+	if len(event.Author) > 50 {
+		return query, fmt.Errorf("author too long (max 50 characters)")
+	}
+
+	var err error
+	if event.From != "" {
+		if query.From, err = repository.ParseSearchDate(event.From, false); err != nil {
+			return query, fmt.Errorf("invalid from: %v", err)
+		}
+	}
+	if event.To != "" {
+		if query.To, err = repository.ParseSearchDate(event.To, true); err != nil {
+			return query, fmt.Errorf("invalid to: %v", err)
+		}
+	}
+
+	if err := repository.NormalizeSearchQuery(&query); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
@@ -1,6 +1,11 @@
 package shared
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+
+	"websocket/pkg/protocol"
+)
 
 // Common error types for WebSocket handlers
 var (
@@ -11,15 +16,9 @@ var (
 	ErrSendBufferFull     = fmt.Errorf("send buffer full")
 )
 
-// ValidationError represents a validation error with details
-type ValidationError struct {
-	Field   string
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
-}
+// ValidationError is a client event that doesn't match its schema, defined in
+// pkg/protocol where the schemas are generated
+type ValidationError = protocol.FieldError
 
 // NewValidationError creates a new validation error
 func NewValidationError(field, message string) *ValidationError {
@@ -29,18 +28,36 @@ func NewValidationError(field, message string) *ValidationError {
 	}
 }
 
-// ErrorEvent represents an error response to client
-type ErrorEvent struct {
-	Type    string `json:"type"`           // "ERROR"
-	Message string `json:"message"`        // Error message
-	Code    string `json:"code,omitempty"` // Optional error code
+// Error codes sent to clients alongside ERROR events, defined in pkg/protocol
+const (
+	ErrCodeRateLimited     = protocol.ErrCodeRateLimited
+	ErrCodeContentRejected = protocol.ErrCodeContentRejected
+	ErrCodeForbidden       = protocol.ErrCodeForbidden
+	ErrCodeBanned          = protocol.ErrCodeBanned
+	ErrCodeMuted           = protocol.ErrCodeMuted
+	ErrCodeInvalidEvent    = protocol.ErrCodeInvalidEvent
+)
+
+// EventError is a handler error that carries a machine-readable code for the client
+type EventError struct {
+	Code    string
+	Message string
 }
 
-// GetType returns the event type
-func (e *ErrorEvent) GetType() string { return e.Type }
+func (e *EventError) Error() string {
+	return e.Message
+}
 
-// GetUser returns empty string for error events
-func (e *ErrorEvent) GetUser() string { return "" }
+// NewEventError creates a new coded handler error
+func NewEventError(code, message string) *EventError {
+	return &EventError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// ErrorEvent is defined in pkg/protocol so Go clients share it
+type ErrorEvent = protocol.ErrorEvent
 
 // NewErrorEvent creates a new error event
 func NewErrorEvent(message string) *ErrorEvent {
@@ -58,3 +75,15 @@ func NewErrorEventWithCode(message, code string) *ErrorEvent {
 		Code:    code,
 	}
 }
+
+// RequestID returns the request_id of a raw client event, if it has one. Any
+// client event may carry one; it is echoed on the ERROR the event fails with.
+func RequestID(messageBytes []byte) string {
+	var event struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return ""
+	}
+	return event.RequestID
+}
@@ -0,0 +1,6 @@
+package shared
+
+import "websocket/pkg/protocol"
+
+// ContentHeldEvent is defined in pkg/protocol so Go clients share it
+type ContentHeldEvent = protocol.ContentHeldEvent
@@ -0,0 +1,61 @@
+package shared
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a sliding-window limiter. Handlers key it by username, so
+// every connection and poll session of a user draws on the same budget.
+type RateLimiter struct {
+	limit     int
+	window    time.Duration
+	hits      map[string][]time.Time
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+// NewRateLimiter allows at most limit actions per key within window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		window:    window,
+		hits:      make(map[string][]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow records an action for key and reports whether it is within the limit
+func (l *RateLimiter) Allow(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+
+	// Forget keys of clients that have gone quiet so the map doesn't grow forever
+	if now.Sub(l.lastSweep) > l.window {
+		for k, hits := range l.hits {
+			if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+				delete(l.hits, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	// Drop hits that fell out of the window
+	recent := l.hits[key][:0]
+	for _, hit := range l.hits[key] {
+		if hit.After(cutoff) {
+			recent = append(recent, hit)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false
+	}
+
+	l.hits[key] = append(recent, now)
+	return true
+}
@@ -11,10 +11,17 @@ type ClientInterface interface {
 // HubInterface defines what handlers need from the hub
 type HubInterface interface {
 	JoinChatRoom(client ClientInterface, roomName string)
+	RemoveUserFromRoom(username, roomName string) int
 	SubscribeToPost(client ClientInterface, postID string)
+	UnsubscribeFromPost(client ClientInterface, postID string)
+	PostSubscriberCount(postID string) int
+	SubscribeToFeed(client ClientInterface)
+	UnsubscribeFromFeed(client ClientInterface)
 	BroadcastToChatRoom(roomName string, event interface{})
 	BroadcastToPostSubscribers(postID string, event interface{})
 	SendToClient(client ClientInterface, event interface{}) error
+	SendToUser(username string, event interface{})
+	IsUserOnline(username string) bool
 }
 
 // Event interface - all events must implement this
@@ -0,0 +1,126 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"websocket/internal/models"
+	"websocket/internal/repository"
+	"websocket/internal/websocket/handlers/shared"
+	"websocket/pkg/protocol"
+)
+
+// Handler handles explicit post and posts feed subscriptions
+type Handler struct {
+	validator         *Validator
+	postRepository    repository.PostStore
+	commentRepository repository.CommentStore
+}
+
+// NewHandler creates a new subscriptions handler
+func NewHandler(postRepo repository.PostStore, commentRepo repository.CommentStore) *Handler {
+	return &Handler{
+		validator:         NewValidator(),
+		postRepository:    postRepo,
+		commentRepository: commentRepo,
+	}
+}
+
+// Wire formats live in pkg/protocol so Go clients share them
+type (
+	SubscribePostEvent    = protocol.SubscribePostEvent
+	UnsubscribePostEvent  = protocol.UnsubscribePostEvent
+	FeedEvent             = protocol.FeedEvent
+	CommentSnapshot       = protocol.CommentSnapshot
+	PostSubscribedEvent   = protocol.PostSubscribedEvent
+	PostUnsubscribedEvent = protocol.PostUnsubscribedEvent
+	FeedSubscriptionEvent = protocol.FeedSubscriptionEvent
+)
+
+// HandleSubscribePost processes SUBSCRIBE_POST events
+func (h *Handler) HandleSubscribePost(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event SubscribePostEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid SUBSCRIBE_POST event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateSubscribePost(&event); err != nil {
+		return err
+	}
+
+	if _, err := h.postRepository.GetPostByID(event.PostID); err != nil {
+		return fmt.Errorf("post %s not found", event.PostID)
+	}
+
+	// STEP 1: Subscribe before reading the snapshot, so a comment made in between
+	// shows up twice (clients dedupe by comment ID) rather than not at all
+	hub := client.GetHub()
+	hub.SubscribeToPost(client, event.PostID)
+
+	response := &PostSubscribedEvent{
+		Type:        "POST_SUBSCRIBED",
+		PostID:      event.PostID,
+		Subscribers: hub.PostSubscriberCount(event.PostID),
+	}
+
+	// STEP 2: Load the most recent comments if asked to
+	if event.Snapshot {
+		comments, hasMore, err := h.commentRepository.GetCommentsBefore(event.PostID, nil, event.SnapshotLimit)
+		if err != nil {
+			log.Printf("❌ Failed to load comment snapshot for post %s: %v", event.PostID, err)
+			return fmt.Errorf("failed to load comments")
+		}
+
+		snapshot := &CommentSnapshot{
+			Comments: comments,
+			HasMore:  hasMore,
+		}
+		if snapshot.Comments == nil {
+			snapshot.Comments = []*models.Comment{}
+		}
+		if hasMore {
+			oldest := comments[0]
+			snapshot.NextCursor = repository.NewCursor(oldest.CreatedAt, oldest.ID).Encode()
+		}
+		response.Snapshot = snapshot
+	}
+
+	// STEP 3: Confirm to the subscriber only
+	return hub.SendToClient(client, response)
+}
+
+// HandleUnsubscribePost processes UNSUBSCRIBE_POST events
+func (h *Handler) HandleUnsubscribePost(client shared.ClientInterface, messageBytes []byte) error {
+	// Parse event
+	var event UnsubscribePostEvent
+	if err := json.Unmarshal(messageBytes, &event); err != nil {
+		return fmt.Errorf("invalid UNSUBSCRIBE_POST event: %v", err)
+	}
+
+	// Validate event
+	if err := h.validator.ValidateUnsubscribePost(&event); err != nil {
+		return err
+	}
+
+	client.GetHub().UnsubscribeFromPost(client, event.PostID)
+
+	return client.GetHub().SendToClient(client, &PostUnsubscribedEvent{
+		Type:   "POST_UNSUBSCRIBED",
+		PostID: event.PostID,
+	})
+}
+
+// HandleSubscribeFeed processes SUBSCRIBE_FEED events
+func (h *Handler) HandleSubscribeFeed(client shared.ClientInterface, messageBytes []byte) error {
+	client.GetHub().SubscribeToFeed(client)
+	return client.GetHub().SendToClient(client, &FeedSubscriptionEvent{Type: "FEED_SUBSCRIBED"})
+}
+
+// HandleUnsubscribeFeed processes UNSUBSCRIBE_FEED events
+func (h *Handler) HandleUnsubscribeFeed(client shared.ClientInterface, messageBytes []byte) error {
+	client.GetHub().UnsubscribeFromFeed(client)
+	return client.GetHub().SendToClient(client, &FeedSubscriptionEvent{Type: "FEED_UNSUBSCRIBED"})
+}
@@ -0,0 +1,59 @@
+package subscriptions
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Snapshot size bounds for SUBSCRIBE_POST
+const (
+	defaultSnapshotLimit = 20
+	maxSnapshotLimit     = 100
+)
+
+// Validator handles validation for subscription events
+type Validator struct {
+	postIDRegex *regexp.Regexp
+}
+
+// NewValidator creates a new subscriptions validator
+func NewValidator() *Validator {
+	return &Validator{
+		postIDRegex: regexp.MustCompile(`^[a-zA-Z0-9_-]+$`),
+	}
+}
+
+// ValidateSubscribePost validates a post subscription and applies the default snapshot size
+func (v *Validator) ValidateSubscribePost(event *SubscribePostEvent) error {
+	if err := v.validatePostID(event.PostID); err != nil {
+		return err
+	}
+	if event.SnapshotLimit < 0 {
+		return fmt.Errorf("snapshot_limit must be positive")
+	}
+	if event.SnapshotLimit == 0 {
+		event.SnapshotLimit = defaultSnapshotLimit
+	}
+	if event.SnapshotLimit > maxSnapshotLimit {
+		event.SnapshotLimit = maxSnapshotLimit
+	}
+	return nil
+}
+
+// ValidateUnsubscribePost validates a post unsubscription
+func (v *Validator) ValidateUnsubscribePost(event *UnsubscribePostEvent) error {
+	return v.validatePostID(event.PostID)
+}
+
+func (v *Validator) validatePostID(postID string) error {
+	if postID == "" {
+		return fmt.Errorf("post_id is required")
+	}
+	if len(postID) > 100 {
+		return fmt.Errorf("post_id too long (max 100 characters)")
+	}
+	if !v.postIDRegex.MatchString(postID) {
+		return fmt.Errorf("invalid post_id format (only alphanumeric, dash, underscore allowed)")
+	}
+	return nil
+}
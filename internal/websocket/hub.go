@@ -8,21 +8,33 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-// Hub manages WebSocket connections with simple event handling
+// Hub manages client connections with simple event handling. Connections are
+// held as Conns, so WebSocket and SSE clients share rooms and subscriptions.
 type Hub struct {
 	// Connection management
-	clients    map[*Client]bool
-	register   chan *Client
-	unregister chan *Client
+	clients    map[Conn]bool
+	register   chan Conn
+	unregister chan Conn
 
 	// Simple room management: room_name -> clients
-	chatRooms  map[string]map[*Client]bool
+	chatRooms  map[string]map[Conn]bool
 	roomsMutex sync.RWMutex
 
 	// Post subscribers: post_id -> clients
-	postSubscribers map[string]map[*Client]bool
+	postSubscribers map[string]map[Conn]bool
 	postMutex       sync.RWMutex
 
+	// Posts feed: clients that receive every post and comment domain event
+	feedSubscribers map[Conn]bool
+	feedMutex       sync.RWMutex
+
+	// User index: username -> all of that user's connections
+	userClients map[string]map[Conn]bool
+	usersMutex  sync.RWMutex
+
+	// Recent broadcasts, for SSE clients resuming with Last-Event-ID
+	events *EventLog
+
 	// WebSocket upgrader
 	upgrader websocket.Upgrader
 }
@@ -30,11 +42,14 @@ type Hub struct {
 // NewHub creates a new Hub instance
 func NewHub() *Hub {
 	return &Hub{
-		clients:         make(map[*Client]bool),
-		register:        make(chan *Client),
-		unregister:      make(chan *Client),
-		chatRooms:       make(map[string]map[*Client]bool),
-		postSubscribers: make(map[string]map[*Client]bool),
+		clients:         make(map[Conn]bool),
+		register:        make(chan Conn),
+		unregister:      make(chan Conn),
+		chatRooms:       make(map[string]map[Conn]bool),
+		postSubscribers: make(map[string]map[Conn]bool),
+		feedSubscribers: make(map[Conn]bool),
+		userClients:     make(map[string]map[Conn]bool),
+		events:          NewEventLog(eventLogSize),
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -59,17 +74,25 @@ func (h *Hub) Run() {
 }
 
 // handleClientRegister adds a new client
-func (h *Hub) handleClientRegister(client *Client) {
+func (h *Hub) handleClientRegister(client Conn) {
 	h.clients[client] = true
-	log.Printf("✅ Client %s connected", client.id)
+
+	h.usersMutex.Lock()
+	if h.userClients[client.GetUsername()] == nil {
+		h.userClients[client.GetUsername()] = make(map[Conn]bool)
+	}
+	h.userClients[client.GetUsername()][client] = true
+	h.usersMutex.Unlock()
+
+	log.Printf("✅ Client %s connected (%s)", client.GetID(), client.Transport())
 }
 
 // handleClientUnregister removes a client from all rooms and subscriptions
-func (h *Hub) handleClientUnregister(client *Client) {
+func (h *Hub) handleClientUnregister(client Conn) {
 	if _, ok := h.clients[client]; ok {
 		// Remove from clients
 		delete(h.clients, client)
-		close(client.send)
+		client.Close()
 
 		// Remove from all chat rooms
 		h.roomsMutex.Lock()
@@ -83,11 +106,16 @@ func (h *Hub) handleClientUnregister(client *Client) {
 		}
 		h.roomsMutex.Unlock()
 
-		// Remove from all post subscriptions
+		// Remove from all post subscriptions, remembering posts that lost a subscribed user
+		subscriberCounts := make(map[string]int)
 		h.postMutex.Lock()
 		for postID, postClients := range h.postSubscribers {
 			if _, exists := postClients[client]; exists {
+				before := countSubscribers(postClients)
 				delete(postClients, client)
+				if after := countSubscribers(postClients); after != before {
+					subscriberCounts[postID] = after
+				}
 				if len(postClients) == 0 {
 					delete(h.postSubscribers, postID)
 				}
@@ -95,6 +123,25 @@ func (h *Hub) handleClientUnregister(client *Client) {
 		}
 		h.postMutex.Unlock()
 
-		log.Printf("❌ Client %s disconnected", client.id)
+		// Remove from the posts feed
+		h.feedMutex.Lock()
+		delete(h.feedSubscribers, client)
+		h.feedMutex.Unlock()
+
+		// Remove from user index
+		h.usersMutex.Lock()
+		if userClients, exists := h.userClients[client.GetUsername()]; exists {
+			delete(userClients, client)
+			if len(userClients) == 0 {
+				delete(h.userClients, client.GetUsername())
+			}
+		}
+		h.usersMutex.Unlock()
+
+		for postID, count := range subscriberCounts {
+			h.broadcastSubscriberCount(postID, count)
+		}
+
+		log.Printf("❌ Client %s disconnected (%s)", client.GetID(), client.Transport())
 	}
 }
@@ -10,104 +10,221 @@ import (
 
 // Make Hub implement HubInterface
 func (h *Hub) JoinChatRoom(client shared.ClientInterface, roomName string) {
-	// Convert interface back to concrete type for internal operations
-	concreteClient, ok := client.(*Client)
+	// Convert interface back to a hub connection for internal operations
+	conn, ok := client.(Conn)
 	if !ok {
 		log.Printf("❌ Invalid client type in JoinChatRoom")
 		return
 	}
+	h.joinChatRoom(conn, roomName)
+}
 
+func (h *Hub) joinChatRoom(conn Conn, roomName string) {
 	h.roomsMutex.Lock()
 	defer h.roomsMutex.Unlock()
 
 	if h.chatRooms[roomName] == nil {
-		h.chatRooms[roomName] = make(map[*Client]bool)
+		h.chatRooms[roomName] = make(map[Conn]bool)
 	}
-	h.chatRooms[roomName][concreteClient] = true
+	h.chatRooms[roomName][conn] = true
 
-	log.Printf("👥 Client %s joined chat room: %s", client.GetUsername(), roomName)
+	log.Printf("👥 Client %s joined chat room: %s", conn.GetUsername(), roomName)
 }
 
+// RemoveUserFromRoom drops every connection of a user from a chat room, e.g. after a ban.
+// It returns how many connections were removed.
+func (h *Hub) RemoveUserFromRoom(username, roomName string) int {
+	h.roomsMutex.Lock()
+	defer h.roomsMutex.Unlock()
+
+	removed := 0
+	for client := range h.chatRooms[roomName] {
+		if client.GetUsername() == username {
+			delete(h.chatRooms[roomName], client)
+			removed++
+		}
+	}
+	if len(h.chatRooms[roomName]) == 0 {
+		delete(h.chatRooms, roomName)
+	}
+
+	if removed > 0 {
+		log.Printf("🚪 Removed %d connection(s) of %s from chat room: %s", removed, username, roomName)
+	}
+	return removed
+}
+
+// SubscribeToPost adds a client to a post's subscribers. When that brings a new
+// user to the post, the new subscriber count is broadcast.
 func (h *Hub) SubscribeToPost(client shared.ClientInterface, postID string) {
-	// Convert interface back to concrete type for internal operations
-	concreteClient, ok := client.(*Client)
+	// Convert interface back to a hub connection for internal operations
+	conn, ok := client.(Conn)
 	if !ok {
 		log.Printf("❌ Invalid client type in SubscribeToPost")
 		return
 	}
+	h.subscribeToPost(conn, postID)
+}
 
+func (h *Hub) subscribeToPost(conn Conn, postID string) {
 	h.postMutex.Lock()
-	defer h.postMutex.Unlock()
-
 	if h.postSubscribers[postID] == nil {
-		h.postSubscribers[postID] = make(map[*Client]bool)
+		h.postSubscribers[postID] = make(map[Conn]bool)
 	}
-	h.postSubscribers[postID][concreteClient] = true
+	before := countSubscribers(h.postSubscribers[postID])
+	h.postSubscribers[postID][conn] = true
+	after := countSubscribers(h.postSubscribers[postID])
+	h.postMutex.Unlock()
 
-	log.Printf("📝 Client %s subscribed to post: %s", client.GetUsername(), postID)
+	log.Printf("📝 Client %s subscribed to post: %s", conn.GetUsername(), postID)
+
+	if after != before {
+		h.broadcastSubscriberCount(postID, after)
+	}
 }
 
-func (h *Hub) BroadcastToChatRoom(roomName string, event interface{}) {
-	h.roomsMutex.RLock()
-	roomClients := h.chatRooms[roomName]
-	h.roomsMutex.RUnlock()
+// UnsubscribeFromPost removes a client from a post's subscribers, broadcasting
+// the new subscriber count if that was the last of the user's connections reading it
+func (h *Hub) UnsubscribeFromPost(client shared.ClientInterface, postID string) {
+	conn, ok := client.(Conn)
+	if !ok {
+		log.Printf("❌ Invalid client type in UnsubscribeFromPost")
+		return
+	}
 
-	if roomClients == nil {
-		log.Printf("⚠️ No clients in room %s to broadcast to", roomName)
+	h.postMutex.Lock()
+	postClients := h.postSubscribers[postID]
+	if !postClients[conn] {
+		h.postMutex.Unlock()
 		return
 	}
+	before := countSubscribers(postClients)
+	delete(postClients, conn)
+	after := countSubscribers(postClients)
+	if len(postClients) == 0 {
+		delete(h.postSubscribers, postID)
+	}
+	h.postMutex.Unlock()
 
+	log.Printf("📝 Client %s unsubscribed from post: %s", client.GetUsername(), postID)
+
+	if after != before {
+		h.broadcastSubscriberCount(postID, after)
+	}
+}
+
+// PostSubscriberCount returns how many distinct users are subscribed to a post
+func (h *Hub) PostSubscriberCount(postID string) int {
+	h.postMutex.RLock()
+	defer h.postMutex.RUnlock()
+	return countSubscribers(h.postSubscribers[postID])
+}
+
+// BroadcastToChatRoom sends an event to everyone in a room. It is logged even when
+// the room is empty, so SSE clients reconnecting to it can catch up.
+func (h *Hub) BroadcastToChatRoom(roomName string, event interface{}) {
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
 		log.Printf("❌ Error marshaling chat event: %v", err)
 		return
 	}
+	message := Outbound{ID: h.events.Append(eventBytes, roomChannel(roomName)), Data: eventBytes}
 
-	for client := range roomClients {
-		select {
-		case client.send <- eventBytes:
-		default:
-			// Client's send buffer is full, remove it
-			delete(roomClients, client)
-			close(client.send)
+	h.roomsMutex.RLock()
+	roomClients := make([]Conn, 0, len(h.chatRooms[roomName]))
+	for client := range h.chatRooms[roomName] {
+		roomClients = append(roomClients, client)
+	}
+	h.roomsMutex.RUnlock()
+
+	if len(roomClients) == 0 {
+		log.Printf("⚠️ No clients in room %s to broadcast to", roomName)
+		return
+	}
+
+	for _, client := range roomClients {
+		if !client.Deliver(message) {
+			h.drop(client)
 		}
 	}
 
 	log.Printf("💬 Broadcasted chat message to room %s (%d clients)", roomName, len(roomClients))
 }
 
+// BroadcastToPostSubscribers sends an event to everyone following a post, logging it like BroadcastToChatRoom
 func (h *Hub) BroadcastToPostSubscribers(postID string, event interface{}) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("❌ Error marshaling comment event: %v", err)
+		return
+	}
+	message := Outbound{ID: h.events.Append(eventBytes, postChannel(postID)), Data: eventBytes}
+
 	h.postMutex.RLock()
-	postClients := h.postSubscribers[postID]
+	postClients := make([]Conn, 0, len(h.postSubscribers[postID]))
+	for client := range h.postSubscribers[postID] {
+		postClients = append(postClients, client)
+	}
 	h.postMutex.RUnlock()
 
-	if postClients == nil {
+	if len(postClients) == 0 {
 		log.Printf("⚠️ No subscribers for post %s", postID)
 		return
 	}
 
+	for _, client := range postClients {
+		if !client.Deliver(message) {
+			h.drop(client)
+		}
+	}
+
+	log.Printf("📝 Broadcasted comment to post %s (%d clients)", postID, len(postClients))
+}
+
+// drop disconnects a client whose send buffer is full. It is unregistered from a
+// goroutine, as the hub loop itself broadcasts while unregistering.
+func (h *Hub) drop(client Conn) {
+	log.Printf("⚠️ Dropping %s: send buffer full", client.GetUsername())
+	client.Close()
+	go func() { h.unregister <- client }()
+}
+
+// SendToUser delivers an event to every connection of a user, wherever they are
+func (h *Hub) SendToUser(username string, event interface{}) {
+	h.usersMutex.RLock()
+	userClients := make([]Conn, 0, len(h.userClients[username]))
+	for client := range h.userClients[username] {
+		userClients = append(userClients, client)
+	}
+	h.usersMutex.RUnlock()
+
+	if len(userClients) == 0 {
+		return
+	}
+
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
-		log.Printf("❌ Error marshaling comment event: %v", err)
+		log.Printf("❌ Error marshaling user event: %v", err)
 		return
 	}
 
-	for client := range postClients {
-		select {
-		case client.send <- eventBytes:
-		default:
-			// Client's send buffer is full, remove it
-			delete(postClients, client)
-			close(client.send)
+	for _, client := range userClients {
+		if !client.Deliver(Outbound{Data: eventBytes}) {
+			log.Printf("⚠️ Dropped event for %s: send buffer full", username)
 		}
 	}
+}
 
-	log.Printf("📝 Broadcasted comment to post %s (%d clients)", postID, len(postClients))
+// IsUserOnline reports whether a user has at least one open connection
+func (h *Hub) IsUserOnline(username string) bool {
+	h.usersMutex.RLock()
+	defer h.usersMutex.RUnlock()
+	return len(h.userClients[username]) > 0
 }
 
 func (h *Hub) SendToClient(client shared.ClientInterface, event interface{}) error {
-	// Convert interface back to concrete type
-	concreteClient, ok := client.(*Client)
+	// Convert interface back to a hub connection
+	conn, ok := client.(Conn)
 	if !ok {
 		return fmt.Errorf("invalid client type")
 	}
@@ -117,10 +234,8 @@ func (h *Hub) SendToClient(client shared.ClientInterface, event interface{}) err
 		return fmt.Errorf("error marshaling event: %v", err)
 	}
 
-	select {
-	case concreteClient.send <- eventBytes:
-		return nil
-	default:
+	if !conn.Deliver(Outbound{Data: eventBytes}) {
 		return fmt.Errorf("client send buffer is full")
 	}
+	return nil
 }
@@ -0,0 +1,309 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"websocket/internal/websocket/handlers/shared"
+)
+
+// Long-polling constants
+const (
+	PollSessionIdle   = 60 * time.Second // Sessions not polled or sent to for this long expire
+	pollSweepInterval = 10 * time.Second
+	pollMaxPending    = 256 // Unacknowledged events a session may hold before it is dropped
+	pollMaxPerUser    = 5   // Open sessions one username may hold
+	pollMaxPerAddr    = 20  // Open sessions one client IP may hold
+	DefaultPollWait   = 25 * time.Second
+	MaxPollWait       = 30 * time.Second
+)
+
+var (
+	// ErrPollSessionNotFound is returned for unknown, closed or expired sessions
+	ErrPollSessionNotFound = errors.New("poll session not found")
+	// ErrTooManyPollSessions is returned when the username or client IP already
+	// holds as many open sessions as it may
+	ErrTooManyPollSessions = errors.New("too many poll sessions")
+)
+
+// PolledEvent is one queued event as returned by a poll
+type PolledEvent struct {
+	Seq   uint64          `json:"seq"`   // Per-session sequence; pass the highest seen as after
+	Event json.RawMessage `json:"event"` // The event, exactly as WebSocket clients receive it
+}
+
+// pollClient is a session of the long-polling transport. Events wait in pending
+// until a poll acknowledges them, so a response lost in transit is resent.
+type pollClient struct {
+	hub      *Hub
+	id       string
+	username string
+	addr     string // Client IP the session was created from
+
+	mutex    sync.Mutex
+	pending  []PolledEvent
+	seq      uint64
+	returned uint64 // Highest seq handed out by a poll; acknowledgements can't go past it
+	lastSeen time.Time
+	isClosed bool
+	notify   chan struct{} // Signalled when events arrive
+	done     chan struct{} // Closed with the session
+}
+
+// Make pollClient implement Conn
+func (c *pollClient) GetID() string       { return c.id }
+func (c *pollClient) GetUsername() string { return c.username }
+func (c *pollClient) Transport() string   { return TransportLongPoll }
+
+func (c *pollClient) Deliver(message Outbound) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isClosed || len(c.pending) >= pollMaxPending {
+		return false
+	}
+	c.seq++
+	c.pending = append(c.pending, PolledEvent{Seq: c.seq, Event: message.Data})
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (c *pollClient) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isClosed {
+		c.isClosed = true
+		close(c.done)
+	}
+}
+
+// Make pollClient implement ClientInterface, so sent events go through the event router
+func (c *pollClient) GetHub() shared.HubInterface {
+	return c.hub
+}
+
+func (c *pollClient) SendError(message string) {
+	c.hub.SendToClient(c, shared.NewErrorEvent(message))
+}
+
+// touch records activity and reports whether the session is still open
+func (c *pollClient) touch() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lastSeen = time.Now()
+	return !c.isClosed
+}
+
+// take drops events up to after and returns the rest. Only events a poll has
+// returned can be acknowledged, so an after from the future loses nothing.
+func (c *pollClient) take(after uint64) []PolledEvent {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if after > c.returned {
+		after = c.returned
+	}
+	i := 0
+	for i < len(c.pending) && c.pending[i].Seq <= after {
+		i++
+	}
+	c.pending = c.pending[i:]
+
+	events := make([]PolledEvent, len(c.pending))
+	copy(events, c.pending)
+	if len(events) > 0 {
+		c.returned = events[len(events)-1].Seq
+	}
+	return events
+}
+
+// isOpen reports whether the session is open without recording activity
+func (c *pollClient) isOpen() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return !c.isClosed
+}
+
+func (c *pollClient) idleSince() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastSeen
+}
+
+// LongPoller manages long-polling sessions for clients that can use neither
+// WebSocket nor SSE. Sessions are registered in the hub like any other Conn.
+type LongPoller struct {
+	hub      *Hub
+	sessions map[string]*pollClient
+	mutex    sync.RWMutex
+}
+
+// NewLongPoller creates a session manager for the hub; start Run to expire idle sessions
+func NewLongPoller(hub *Hub) *LongPoller {
+	return &LongPoller{
+		hub:      hub,
+		sessions: make(map[string]*pollClient),
+	}
+}
+
+// Run expires idle sessions until the process exits
+func (p *LongPoller) Run() {
+	ticker := time.NewTicker(pollSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.expireIdle(time.Now().Add(-PollSessionIdle))
+	}
+}
+
+// Create opens a session for username, created from the client IP addr, and
+// returns its ID. Sessions cost nothing to open, so each username and each IP
+// may only hold a few at once.
+func (p *LongPoller) Create(username, addr string) (string, error) {
+	id, err := newPollSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	client := &pollClient{
+		hub:      p.hub,
+		id:       id,
+		username: username,
+		addr:     addr,
+		lastSeen: time.Now(),
+		notify:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	p.mutex.Lock()
+	byUser, byAddr := 0, 0
+	for _, session := range p.sessions {
+		if !session.isOpen() {
+			continue
+		}
+		if session.username == username {
+			byUser++
+		}
+		if session.addr == addr {
+			byAddr++
+		}
+	}
+	if byUser >= pollMaxPerUser || byAddr >= pollMaxPerAddr {
+		p.mutex.Unlock()
+		return "", ErrTooManyPollSessions
+	}
+	p.sessions[id] = client
+	p.mutex.Unlock()
+
+	p.hub.register <- client
+	return id, nil
+}
+
+// Poll acknowledges events up to after, then returns the session's pending events,
+// waiting up to wait for some to arrive. An empty result means the wait timed out.
+func (p *LongPoller) Poll(ctx context.Context, id string, after uint64, wait time.Duration) ([]PolledEvent, error) {
+	client, err := p.session(id)
+	if err != nil {
+		return nil, err
+	}
+	defer client.touch()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		if events := client.take(after); len(events) > 0 {
+			return events, nil
+		}
+
+		select {
+		case <-client.notify:
+			// A stale signal can wake us with nothing new; take again and keep waiting
+		case <-timer.C:
+			return []PolledEvent{}, nil
+		case <-ctx.Done():
+			return []PolledEvent{}, nil
+		case <-client.done:
+			return nil, ErrPollSessionNotFound
+		}
+	}
+}
+
+// Send routes a client event through the event router as if it came over WebSocket
+func (p *LongPoller) Send(id string, messageBytes []byte) error {
+	client, err := p.session(id)
+	if err != nil {
+		return err
+	}
+	if eventRouter == nil {
+		return fmt.Errorf("event router not initialized")
+	}
+	return eventRouter.routeEvent(client, messageBytes)
+}
+
+// Close ends a session and removes it from the hub
+func (p *LongPoller) Close(id string) error {
+	p.mutex.Lock()
+	client, ok := p.sessions[id]
+	delete(p.sessions, id)
+	p.mutex.Unlock()
+
+	if !ok {
+		return ErrPollSessionNotFound
+	}
+	p.hub.unregister <- client
+	return nil
+}
+
+// session looks up an open session and marks it active
+func (p *LongPoller) session(id string) (*pollClient, error) {
+	p.mutex.RLock()
+	client, ok := p.sessions[id]
+	p.mutex.RUnlock()
+
+	if !ok || !client.touch() {
+		return nil, ErrPollSessionNotFound
+	}
+	return client, nil
+}
+
+// expireIdle closes sessions with no activity since cutoff, and sessions the hub
+// already dropped (e.g. for falling too far behind)
+func (p *LongPoller) expireIdle(cutoff time.Time) {
+	var expired []*pollClient
+
+	p.mutex.Lock()
+	for id, client := range p.sessions {
+		if client.idleSince().Before(cutoff) || !client.isOpen() {
+			expired = append(expired, client)
+			delete(p.sessions, id)
+		}
+	}
+	p.mutex.Unlock()
+
+	for _, client := range expired {
+		log.Printf("⌛ Poll session of %s expired", client.username)
+		p.hub.unregister <- client
+	}
+}
+
+// newPollSessionID returns an unguessable session ID; holding one is holding the session
+func newPollSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %v", err)
+	}
+	return "poll_" + hex.EncodeToString(b), nil
+}
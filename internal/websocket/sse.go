@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSE stream constants
+const (
+	sseHeartbeat  = 25 * time.Second
+	sseRetry      = 3 * time.Second
+	sseBufferSize = 256
+)
+
+// StreamOpenedEvent is the first event on every SSE stream
+type StreamOpenedEvent struct {
+	Type    string   `json:"type"`    // "STREAM_OPENED"
+	Rooms   []string `json:"rooms"`   // Rooms being streamed
+	Posts   []string `json:"posts"`   // Posts being streamed
+	Resumed bool     `json:"resumed"` // Missed events since Last-Event-ID were replayed
+}
+
+// StreamResetEvent tells an SSE client that the events it missed can't be
+// replayed, so it should reload state over REST before trusting the stream
+type StreamResetEvent struct {
+	Type   string `json:"type"`   // "STREAM_RESET"
+	Reason string `json:"reason"` // Why resumption failed
+}
+
+// GetType returns the event type
+func (e *StreamOpenedEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for stream notices
+func (e *StreamOpenedEvent) GetUser() string { return "" }
+
+// GetType returns the event type
+func (e *StreamResetEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for stream notices
+func (e *StreamResetEvent) GetUser() string { return "" }
+
+// sseClient is a receive-only connection fed over Server-Sent Events
+type sseClient struct {
+	id       string
+	username string
+	send     chan Outbound
+	isClosed bool
+	mutex    sync.Mutex
+}
+
+func (c *sseClient) GetID() string       { return c.id }
+func (c *sseClient) GetUsername() string { return c.username }
+func (c *sseClient) Transport() string   { return TransportSSE }
+
+func (c *sseClient) Deliver(message Outbound) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.isClosed {
+		return false
+	}
+	select {
+	case c.send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *sseClient) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.isClosed {
+		c.isClosed = true
+		close(c.send)
+	}
+}
+
+// ServeSSE streams the broadcasts of the given rooms and posts, plus events sent
+// to the user, until the request ends. With lastEventID set it first replays
+// the logged broadcasts the client missed. Callers check access beforehand.
+func (h *Hub) ServeSSE(c *gin.Context, username string, rooms, posts []string, lastEventID string) {
+	client := &sseClient{
+		id:       generateClientID(),
+		username: username,
+		send:     make(chan Outbound, sseBufferSize),
+	}
+
+	// STEP 1: Register and subscribe before reading the log, so nothing published
+	// in between is missed; anything seen twice is dropped by ID below
+	position := h.events.Current()
+	h.register <- client
+	defer func() { h.unregister <- client }()
+
+	channels := make(map[string]bool)
+	for _, room := range rooms {
+		h.joinChatRoom(client, room)
+		channels[roomChannel(room)] = true
+	}
+	for _, postID := range posts {
+		h.subscribeToPost(client, postID)
+		channels[postChannel(postID)] = true
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+	// STEP 2: Replay what the client missed, or tell it that we can't
+	opened := &StreamOpenedEvent{Type: "STREAM_OPENED", Rooms: rooms, Posts: posts}
+	var replay []Outbound
+	if lastEventID != "" {
+		var ok bool
+		if replay, ok = h.events.Since(lastEventID, channels); ok {
+			opened.Resumed = true
+		} else {
+			writeSSE(w, Outbound{}, &StreamResetEvent{
+				Type:   "STREAM_RESET",
+				Reason: "events since Last-Event-ID are no longer available",
+			})
+		}
+	}
+
+	// A fresh (or reset) stream's opening event carries the position it started
+	// from, so a client that sees nothing else before reconnecting still resumes
+	// from there. Resumed streams keep the client's own position instead.
+	start := Outbound{ID: position}
+	if opened.Resumed {
+		start.ID = ""
+	}
+	writeSSE(w, start, opened)
+	var lastSeq uint64
+	for _, message := range replay {
+		writeSSE(w, message, nil)
+		lastSeq = h.events.Seq(message.ID)
+	}
+	w.Flush()
+
+	log.Printf("📡 SSE stream opened for %s (rooms: %v, posts: %v, replayed: %d)", username, rooms, posts, len(replay))
+
+	// STEP 3: Stream live events until the client goes away or the hub drops us
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case message, ok := <-client.send:
+			if !ok {
+				return
+			}
+			if message.ID != "" && h.events.Seq(message.ID) <= lastSeq {
+				continue // Already replayed
+			}
+			writeSSE(w, message, nil)
+			w.Flush()
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		}
+	}
+}
+
+// writeSSE writes one SSE event: the message's data, or event encoded as JSON when set
+func writeSSE(w gin.ResponseWriter, message Outbound, event interface{}) {
+	data := message.Data
+	if event != nil {
+		var err error
+		if data, err = json.Marshal(event); err != nil {
+			log.Printf("❌ Error marshaling SSE event: %v", err)
+			return
+		}
+	}
+
+	if message.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", message.ID)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
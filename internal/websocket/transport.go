@@ -0,0 +1,32 @@
+package websocket
+
+// Transport names reported by Conn.Transport
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+	TransportLongPoll  = "longpoll"
+)
+
+// Conn is a client connection as the hub sees it, whatever transport carries it.
+// Rooms, post subscriptions, the posts feed and the user index all hold Conns,
+// so every broadcast reaches WebSocket, SSE and long-polling clients alike.
+type Conn interface {
+	GetID() string
+	GetUsername() string
+	Transport() string
+
+	// Deliver queues an encoded event without blocking and reports whether it fit
+	Deliver(message Outbound) bool
+
+	// Close releases the connection's queue. The hub calls it when it unregisters
+	// the connection; it must be safe to call more than once.
+	Close()
+}
+
+// Outbound is an encoded event on its way to a connection. Broadcasts carry the
+// ID they were logged under (see EventLog) so resumable transports can tell
+// clients where they are; direct sends have no ID.
+type Outbound struct {
+	ID   string
+	Data []byte
+}
@@ -17,9 +17,26 @@ func (h *Hub) GetStats() map[string]interface{} {
 	defer h.roomsMutex.RUnlock()
 	defer h.postMutex.RUnlock()
 
+	h.usersMutex.RLock()
+	defer h.usersMutex.RUnlock()
+
+	h.feedMutex.RLock()
+	defer h.feedMutex.RUnlock()
+
+	// Counted from the user index, which unlike clients is safe to read here
+	transports := make(map[string]int)
+	for _, userClients := range h.userClients {
+		for client := range userClients {
+			transports[client.Transport()]++
+		}
+	}
+
 	return map[string]interface{}{
 		"total_clients":    len(h.clients),
 		"chat_rooms":       len(h.chatRooms),
 		"post_subscribers": len(h.postSubscribers),
+		"online_users":     len(h.userClients),
+		"feed_subscribers": len(h.feedSubscribers),
+		"transports":       transports,
 	}
 }
@@ -0,0 +1,55 @@
+package blobstore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MemoryStore keeps blobs in process memory, for demo mode and tests
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Put(key string, r io.Reader, size int64, contentType string) error {
+	if key == "" {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return fmt.Errorf("failed to write blob: wrote %d of %d bytes", len(data), size)
+	}
+
+	s.mu.Lock()
+	s.blobs[key] = data
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	data, ok := s.blobs[key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.blobs, key)
+	s.mu.Unlock()
+	return nil
+}
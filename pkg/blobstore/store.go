@@ -0,0 +1,48 @@
+// Package blobstore stores uploaded files behind a small interface so the server can
+// keep them on local disk or in any S3-compatible bucket.
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound is returned by Get when no blob exists under the key
+var ErrNotFound = errors.New("blob not found")
+
+// Store is a flat key/value store for file contents. Keys use forward slashes.
+type Store interface {
+	// Put writes size bytes from r under key, replacing any existing blob
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the blob under key; deleting a missing blob is not an error
+	Delete(key string) error
+}
+
+// NewFromEnv builds the store selected by BLOB_STORE: "local" (the default) keeps files
+// under BLOB_DIR (default ./uploads); "s3" uses S3_ENDPOINT, S3_BUCKET, S3_REGION,
+// S3_ACCESS_KEY, S3_SECRET_KEY and S3_PATH_STYLE (default true).
+func NewFromEnv() (Store, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") != "false",
+		})
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q (use local or s3)", kind)
+	}
+}
@@ -0,0 +1,363 @@
+// Package client is the Go SDK for the chat server's WebSocket API. It dials
+// /ws, reconnects with backoff when the connection drops, rejoins the rooms
+// and resubscribes to the posts and feed it was following, and decodes events
+// into the shared structs in pkg/protocol.
+//
+//	c, err := client.Connect(ctx, "ws://localhost:8080/ws", client.Options{Username: "bot"})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	c.OnChatMessage(func(e *protocol.ChatMessageEvent) {
+//		log.Printf("%s: %s", e.User, e.Message)
+//	})
+//	if _, err := c.JoinRoom(ctx, "general"); err != nil {
+//		return err
+//	}
+//	_, err = c.SendMessage(ctx, "general", "hello")
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"websocket/pkg/protocol"
+)
+
+// Defaults for zero Options fields
+const (
+	DefaultMinBackoff     = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+// Connection timings, matched to the server's ping period
+const (
+	writeWait = 10 * time.Second
+	pongWait  = 70 * time.Second // The server pings every 54s
+)
+
+var (
+	// ErrClosed is returned by calls made after Close
+	ErrClosed = errors.New("client closed")
+
+	// ErrDisconnected fails requests in flight when the connection drops; the
+	// client reconnects on its own, so they can be retried
+	ErrDisconnected = errors.New("connection lost")
+)
+
+// Options configures a Client. Zero values use the defaults above.
+type Options struct {
+	Username string            // Sent as ?username= unless the URL already has one
+	Header   http.Header       // Extra handshake headers
+	Dialer   *websocket.Dialer // Defaults to websocket.DefaultDialer
+
+	DisableReconnect bool          // Stop for good when the connection drops
+	MinBackoff       time.Duration // First reconnect delay, doubled per failed attempt
+	MaxBackoff       time.Duration // Reconnect delay cap
+	RequestTimeout   time.Duration // Reply deadline for requests whose ctx has none
+}
+
+// Client is a connection to the server that survives reconnects. Its methods
+// are safe for concurrent use.
+type Client struct {
+	url      string
+	username string // Who the server attributes our events to
+	opts     Options
+
+	writeMutex sync.Mutex // gorilla connections allow one writer at a time
+
+	mutex        sync.Mutex
+	conn         *websocket.Conn // nil while reconnecting
+	rooms        map[string]bool // Joined rooms, rejoined after a reconnect
+	posts        map[string]bool // Subscribed posts, resubscribed after a reconnect
+	feed         bool
+	pending      []*request
+	nextID       uint64
+	handlers     map[string][]func(json.RawMessage)
+	onDisconnect []func(error)
+	onReconnect  []func()
+	isClosed     bool
+
+	closing chan struct{} // Closed by Close
+	done    chan struct{} // Closed when the connection loop exits
+	err     error         // Why the loop exited, once done is closed
+}
+
+// Connect dials the server and returns a running client. ctx bounds only the
+// initial dial; the client then runs until Close, or until the connection drops
+// with reconnects disabled.
+func Connect(ctx context.Context, rawURL string, opts Options) (*Client, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %v", err)
+	}
+	if opts.Username != "" && target.Query().Get("username") == "" {
+		query := target.Query()
+		query.Set("username", opts.Username)
+		target.RawQuery = query.Encode()
+	}
+	// The server takes the username from the URL, as we send it
+	username := target.Query().Get("username")
+	if username == "" {
+		username = "anonymous"
+	}
+
+	if opts.Dialer == nil {
+		opts.Dialer = websocket.DefaultDialer
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+
+	c := &Client{
+		url:      target.String(),
+		username: username,
+		opts:     opts,
+		rooms:    make(map[string]bool),
+		posts:    make(map[string]bool),
+		handlers: make(map[string][]func(json.RawMessage)),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+
+	go c.run(conn)
+	return c, nil
+}
+
+// Close disconnects and stops reconnecting. Requests in flight fail with ErrClosed.
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	if c.isClosed {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.isClosed = true
+	conn := c.conn
+	c.mutex.Unlock()
+
+	close(c.closing)
+	if conn != nil {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(writeWait))
+		conn.Close()
+	}
+
+	<-c.done
+	return nil
+}
+
+// Done is closed once the client has stopped for good
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err reports why the client stopped: nil after Close, the connection error when
+// reconnects are disabled. Only meaningful once Done is closed.
+func (c *Client) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Connected reports whether the client currently has a live connection
+func (c *Client) Connected() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.conn != nil
+}
+
+// Send writes any client event as JSON without waiting for a reply. Use it for
+// events without a request helper, e.g. REACTION_ADD or MARK_READ.
+func (c *Client) Send(event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+	return c.write(data)
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, resp, err := c.opts.Dialer.DialContext(ctx, c.url, c.opts.Header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %v (HTTP %d)", c.url, err, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %v", c.url, err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeWait))
+	})
+	return conn, nil
+}
+
+// run reads from the connection and reconnects when it drops, until Close
+func (c *Client) run(conn *websocket.Conn) {
+	defer close(c.done)
+
+	for {
+		err := c.read(conn)
+
+		c.mutex.Lock()
+		c.conn = nil
+		closed := c.isClosed
+		handlers := append([]func(error){}, c.onDisconnect...)
+		c.mutex.Unlock()
+
+		if closed {
+			c.failPending(ErrClosed)
+			return
+		}
+		c.failPending(ErrDisconnected)
+		for _, handler := range handlers {
+			handler(err)
+		}
+
+		if c.opts.DisableReconnect {
+			c.stop(err)
+			return
+		}
+		if conn = c.reconnect(); conn == nil {
+			return
+		}
+	}
+}
+
+// read dispatches events until the connection fails
+func (c *Client) read(conn *websocket.Conn) error {
+	for {
+		_, frame, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		// The server batches queued events into one frame, one JSON value each
+		decoder := json.NewDecoder(bytes.NewReader(frame))
+		for decoder.More() {
+			var data json.RawMessage
+			if err := decoder.Decode(&data); err != nil {
+				break
+			}
+			c.dispatch(data)
+		}
+	}
+}
+
+// reconnect dials with exponential backoff and jitter until it succeeds or the
+// client is closed, then restores rooms and subscriptions
+func (c *Client) reconnect() *websocket.Conn {
+	backoff := c.opts.MinBackoff
+	for {
+		// Full jitter keeps a restarted server from being hit by every client at once
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-time.After(delay):
+		case <-c.closing:
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		conn, err := c.dial(ctx)
+		cancel()
+		if err == nil {
+			c.mutex.Lock()
+			if c.isClosed {
+				c.mutex.Unlock()
+				conn.Close()
+				return nil
+			}
+			c.conn = conn
+			handlers := append([]func(){}, c.onReconnect...)
+			c.mutex.Unlock()
+
+			c.resubscribe()
+			for _, handler := range handlers {
+				handler()
+			}
+			return conn
+		}
+
+		if backoff *= 2; backoff > c.opts.MaxBackoff {
+			backoff = c.opts.MaxBackoff
+		}
+	}
+}
+
+// resubscribe replays the joins and subscriptions of the previous connection.
+// Their replies reach handlers as usual; failures come back as ERROR events.
+func (c *Client) resubscribe() {
+	c.mutex.Lock()
+	var events []interface{}
+	for room := range c.rooms {
+		events = append(events, &protocol.JoinRoomEvent{Type: protocol.EventJoinRoom, Room: room})
+	}
+	for postID := range c.posts {
+		events = append(events, &protocol.SubscribePostEvent{Type: protocol.EventSubscribePost, PostID: postID})
+	}
+	if c.feed {
+		events = append(events, &protocol.FeedEvent{Type: protocol.EventSubscribeFeed})
+	}
+	c.mutex.Unlock()
+
+	for _, event := range events {
+		if err := c.Send(event); err != nil {
+			return // Dropped again; the next reconnect retries
+		}
+	}
+}
+
+func (c *Client) write(data []byte) error {
+	c.mutex.Lock()
+	conn, closed := c.conn, c.isClosed
+	c.mutex.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+	if conn == nil {
+		return ErrDisconnected
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		conn.Close() // Let the read loop notice and reconnect
+		return ErrDisconnected
+	}
+	return nil
+}
+
+func (c *Client) stop(err error) {
+	c.mutex.Lock()
+	c.isClosed = true
+	c.err = err
+	c.mutex.Unlock()
+}
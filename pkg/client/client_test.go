@@ -0,0 +1,370 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"websocket/internal/attachments"
+	"websocket/internal/domain"
+	"websocket/internal/handlers"
+	"websocket/internal/models"
+	"websocket/internal/moderation"
+	"websocket/internal/repository"
+	"websocket/internal/retention"
+	"websocket/internal/websocket"
+	"websocket/pkg/blobstore"
+	"websocket/pkg/client"
+	"websocket/pkg/database"
+	"websocket/pkg/protocol"
+)
+
+// The tests share one server, wired like cmd/server in demo mode, because the
+// event router it installs is process-wide
+var (
+	wsURL string
+	conns = &hijackedConns{conns: make(map[net.Conn]bool)}
+)
+
+const testPostID = "client-test-post"
+
+func TestMain(m *testing.M) {
+	// SetupEnhancedRoutes loads its templates relative to the repository root
+	if err := os.Chdir("../.."); err != nil {
+		log.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	log.SetOutput(io.Discard)
+
+	server, err := startServer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "starting test server:", err)
+		os.Exit(1)
+	}
+	wsURL = "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	code := m.Run()
+	conns.closeAll()
+	server.Close()
+	os.Exit(code)
+}
+
+func startServer() (*httptest.Server, error) {
+	db, err := database.NewMemoryDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	messageRepository := repository.NewMessageRepository(db)
+	postRepo := repository.NewPostRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+	readReceiptRepo := repository.NewReadReceiptRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	moderationRepo := repository.NewModerationRepository(db)
+	roomModerationRepo := repository.NewRoomModerationRepository(db)
+	attachmentService := attachments.NewService(blobstore.NewMemoryStore(), attachmentRepo)
+	moderator, err := moderation.NewModeratorFromEnv(moderationRepo)
+	if err != nil {
+		return nil, err
+	}
+	purger := retention.NewPurger(nil, db, messageRepository, attachmentService)
+
+	post := &models.Post{ID: testPostID, Title: "Client tests", Content: "Comments land here", AuthorID: "tests", AuthorName: "tests"}
+	if err := postRepo.CreatePost(post); err != nil {
+		return nil, err
+	}
+
+	bus := domain.NewBus()
+	websocket.InitializeEventRouter(messageRepository, postRepo, commentRepo, unitOfWork, repository.NewReactionRepository(db),
+		readReceiptRepo, searchRepo, mentionRepo, attachmentRepo, attachmentService, moderator, roomModerationRepo, bus)
+	hub := websocket.NewHub()
+	bus.Subscribe(hub.HandleDomainEvent)
+	go hub.Run()
+
+	router := handlers.SetupEnhancedRoutes(hub, messageRepository, postRepo, commentRepo, unitOfWork, readReceiptRepo, searchRepo,
+		mentionRepo, attachmentRepo, attachmentService, moderationRepo, moderator, roomModerationRepo, purger, bus, websocket.NewLongPoller(hub))
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateHijacked {
+			conns.add(conn)
+		}
+	}
+	server.Start()
+	return server, nil
+}
+
+// hijackedConns remembers upgraded connections, which httptest stops tracking,
+// so a test can drop them from the server side
+type hijackedConns struct {
+	mutex sync.Mutex
+	conns map[net.Conn]bool
+}
+
+func (h *hijackedConns) add(conn net.Conn) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.conns[conn] = true
+}
+
+func (h *hijackedConns) closeAll() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for conn := range h.conns {
+		conn.Close()
+		delete(h.conns, conn)
+	}
+}
+
+func connect(t *testing.T, rawURL string, opts client.Options) *client.Client {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	c, err := client.Connect(ctx, rawURL, opts)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// testTimeout bounds each wait. The in-memory database makes readers wait out
+// writers, which the race detector slows enough that two busy clients need
+// seconds, not milliseconds.
+const testTimeout = 20 * time.Second
+
+var runs atomic.Int64
+
+// unique suffixes a username, so repeated runs (-count) stay under the
+// per-username rate limits of the shared server
+func unique(name string) string {
+	return fmt.Sprintf("%s-%d", name, runs.Add(1))
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// await waits for a value on ch
+func await[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for %s", what)
+		var zero T
+		return zero
+	}
+}
+
+func TestConnect(t *testing.T) {
+	c := connect(t, wsURL, client.Options{Username: unique("connect-alice")})
+	if !c.Connected() {
+		t.Fatal("Connected() = false after Connect")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-c.Done():
+	default:
+		t.Error("Done not closed after Close")
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("Err after Close = %v, want nil", err)
+	}
+	if err := c.Send(&protocol.JoinRoomEvent{Type: protocol.EventJoinRoom, Room: "general"}); !errors.Is(err, client.ErrClosed) {
+		t.Errorf("Send after Close = %v, want ErrClosed", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.Connect(ctx, strings.Replace(wsURL, "/ws", "/missing", 1), client.Options{}); err == nil {
+		t.Error("Connect to a path without a WebSocket endpoint succeeded")
+	}
+}
+
+func TestURLUsernameWins(t *testing.T) {
+	carol := unique("url-carol")
+	c := connect(t, wsURL+"?username="+carol, client.Options{Username: "opts-dave"})
+	ctx := testContext(t)
+
+	if _, err := c.JoinRoom(ctx, "url-room"); err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+	// The broadcast is attributed to the URL's username; matching on the option would time out
+	reply, err := c.SendMessage(ctx, "url-room", "who am I")
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if reply.User != carol {
+		t.Errorf("message sent as %q, want %s", reply.User, carol)
+	}
+}
+
+func TestJoinRoomAndSendMessage(t *testing.T) {
+	aliceName, bobName := unique("match-alice"), unique("match-bob")
+	alice := connect(t, wsURL, client.Options{Username: aliceName})
+	bob := connect(t, wsURL, client.Options{Username: bobName})
+	ctx := testContext(t)
+
+	joined, err := alice.JoinRoom(ctx, "match-room")
+	if err != nil {
+		t.Fatalf("alice JoinRoom: %v", err)
+	}
+	if joined.Room != "match-room" {
+		t.Errorf("ROOM_JOINED for %q, want match-room", joined.Room)
+	}
+	if _, err := bob.JoinRoom(ctx, "match-room"); err != nil {
+		t.Fatalf("bob JoinRoom: %v", err)
+	}
+
+	// Both see each other's broadcasts, so each must pick out its own, in order
+	var wg sync.WaitGroup
+	for _, c := range []struct {
+		client *client.Client
+		name   string
+	}{{alice, aliceName}, {bob, bobName}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				text := fmt.Sprintf("%s says %d", c.name, i)
+				reply, err := c.client.SendMessage(ctx, "match-room", text)
+				if err != nil {
+					t.Errorf("%s SendMessage: %v", c.name, err)
+					return
+				}
+				if reply.User != c.name || reply.Message != text || reply.MessageID == "" {
+					t.Errorf("%s sent %q, reply was %+v", c.name, text, reply)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestErrorByRequestID(t *testing.T) {
+	c := connect(t, wsURL, client.Options{Username: unique("error-erin")})
+	ctx := testContext(t)
+
+	_, err := c.JoinRoom(ctx, "no spaces allowed")
+	var serverErr *client.Error
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("JoinRoom with an invalid room: got %v, want *client.Error", err)
+	}
+	if serverErr.Code != protocol.ErrCodeInvalidEvent || serverErr.Field != "room" {
+		t.Errorf("got %+v, want %s on room", serverErr, protocol.ErrCodeInvalidEvent)
+	}
+
+	_, err = c.Search(ctx, protocol.SearchEvent{})
+	if !errors.As(err, &serverErr) || serverErr.Code != protocol.ErrCodeInvalidEvent {
+		t.Errorf("Search without a query: got %v, want %s", err, protocol.ErrCodeInvalidEvent)
+	}
+
+	// A failed request leaves later ones matching normally
+	if _, err := c.JoinRoom(ctx, "error-room"); err != nil {
+		t.Errorf("JoinRoom after errors: %v", err)
+	}
+}
+
+func TestReconnectResubscribes(t *testing.T) {
+	c := connect(t, wsURL, client.Options{
+		Username:   unique("reconnect-rita"),
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	})
+	ctx := testContext(t)
+
+	if _, err := c.JoinRoom(ctx, "reconnect-room"); err != nil {
+		t.Fatalf("JoinRoom: %v", err)
+	}
+	if _, err := c.SubscribePost(ctx, testPostID, 0); err != nil {
+		t.Fatalf("SubscribePost: %v", err)
+	}
+
+	disconnected := make(chan error, 1)
+	reconnected := make(chan struct{}, 1)
+	rejoined := make(chan string, 1)
+	resubscribed := make(chan string, 1)
+	messages := make(chan *protocol.ChatMessageEvent, 10)
+	comments := make(chan *protocol.PostCommentEvent, 10)
+	c.OnDisconnect(func(err error) { disconnected <- err })
+	c.OnReconnect(func() { reconnected <- struct{}{} })
+	c.OnRoomJoined(func(e *protocol.RoomJoinedEvent) { rejoined <- e.Room })
+	c.OnEvent(protocol.EventPostSubscribed, func(data json.RawMessage) {
+		var e protocol.PostSubscribedEvent
+		json.Unmarshal(data, &e)
+		resubscribed <- e.PostID
+	})
+	c.OnChatMessage(func(e *protocol.ChatMessageEvent) { messages <- e })
+	c.OnPostComment(func(e *protocol.PostCommentEvent) { comments <- e })
+
+	conns.closeAll()
+
+	await(t, disconnected, "the disconnect")
+	await(t, reconnected, "the reconnect")
+	if room := await(t, rejoined, "the room to be rejoined"); room != "reconnect-room" {
+		t.Errorf("rejoined %q, want reconnect-room", room)
+	}
+	if postID := await(t, resubscribed, "the post to be resubscribed"); postID != testPostID {
+		t.Errorf("resubscribed to %q, want %s", postID, testPostID)
+	}
+	if !c.Connected() {
+		t.Error("Connected() = false after reconnecting")
+	}
+
+	// Events sent after the reconnect reach the restored room and post subscriptions
+	samName := unique("reconnect-sam")
+	sender := connect(t, wsURL, client.Options{Username: samName})
+	if _, err := sender.JoinRoom(ctx, "reconnect-room"); err != nil {
+		t.Fatalf("sender JoinRoom: %v", err)
+	}
+	if _, err := sender.SendMessage(ctx, "reconnect-room", "welcome back"); err != nil {
+		t.Fatalf("sender SendMessage: %v", err)
+	}
+	if _, err := sender.PostComment(ctx, testPostID, "still subscribed?", ""); err != nil {
+		t.Fatalf("sender PostComment: %v", err)
+	}
+
+	for {
+		message := await(t, messages, "the chat message")
+		if message.User == samName {
+			if message.Message != "welcome back" {
+				t.Errorf("got message %q, want %q", message.Message, "welcome back")
+			}
+			break
+		}
+	}
+	for {
+		comment := await(t, comments, "the comment")
+		if comment.User == samName {
+			if comment.Comment != "still subscribed?" {
+				t.Errorf("got comment %q, want %q", comment.Comment, "still subscribed?")
+			}
+			break
+		}
+	}
+}
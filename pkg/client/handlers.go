@@ -0,0 +1,150 @@
+package client
+
+import (
+	"encoding/json"
+	"log"
+
+	"websocket/pkg/protocol"
+)
+
+// Handlers run on the client's read goroutine, in the order events arrive.
+// A slow handler delays every event behind it, and one that makes a request
+// and waits for the reply would wait forever; hand long work to a goroutine.
+
+// OnEvent registers fn for every event of eventType, undecoded. Use it for event
+// types without a typed On method, or "*" for every event.
+func (c *Client) OnEvent(eventType string, fn func(data json.RawMessage)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], fn)
+}
+
+// OnDisconnect registers fn to run when the connection drops, before reconnecting
+func (c *Client) OnDisconnect(fn func(err error)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.onDisconnect = append(c.onDisconnect, fn)
+}
+
+// OnReconnect registers fn to run after a reconnect, once rooms and subscriptions
+// have been requested again. Events sent while disconnected are not replayed;
+// use FetchHistory to catch up.
+func (c *Client) OnReconnect(fn func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.onReconnect = append(c.onReconnect, fn)
+}
+
+// on registers a handler that decodes events of eventType into T
+func on[T any](c *Client, eventType string, fn func(*T)) {
+	c.OnEvent(eventType, func(data json.RawMessage) {
+		event := new(T)
+		if err := json.Unmarshal(data, event); err != nil {
+			log.Printf("client: failed to decode %s event: %v", eventType, err)
+			return
+		}
+		fn(event)
+	})
+}
+
+// OnChatMessage registers fn for CHAT_MESSAGE broadcasts in joined rooms
+func (c *Client) OnChatMessage(fn func(*protocol.ChatMessageEvent)) {
+	on(c, protocol.EventChatMessage, fn)
+}
+
+// OnRoomJoined registers fn for ROOM_JOINED confirmations
+func (c *Client) OnRoomJoined(fn func(*protocol.RoomJoinedEvent)) {
+	on(c, protocol.EventRoomJoined, fn)
+}
+
+// OnPostComment registers fn for new comments on subscribed posts
+func (c *Client) OnPostComment(fn func(*protocol.PostCommentEvent)) {
+	on(c, protocol.EventPostComment, fn)
+}
+
+// OnCommentUpdated registers fn for edited comments on subscribed posts
+func (c *Client) OnCommentUpdated(fn func(*protocol.CommentUpdatedEvent)) {
+	on(c, protocol.EventCommentUpdated, fn)
+}
+
+// OnCommentDeleted registers fn for deleted comments on subscribed posts
+func (c *Client) OnCommentDeleted(fn func(*protocol.CommentDeletedEvent)) {
+	on(c, protocol.EventCommentDeleted, fn)
+}
+
+// OnPostEvent registers fn for POST_CREATED, POST_UPDATED and POST_DELETED.
+// Creations reach feed subscribers only.
+func (c *Client) OnPostEvent(fn func(*protocol.PostEvent)) {
+	on(c, protocol.EventPostCreated, fn)
+	on(c, protocol.EventPostUpdated, fn)
+	on(c, protocol.EventPostDeleted, fn)
+}
+
+// OnCommentCreated registers fn for the posts feed's COMMENT_CREATED events
+func (c *Client) OnCommentCreated(fn func(*protocol.CommentCreatedEvent)) {
+	on(c, protocol.EventCommentCreated, fn)
+}
+
+// OnPostSubscribers registers fn for live reader counts of subscribed posts
+func (c *Client) OnPostSubscribers(fn func(*protocol.PostSubscribersEvent)) {
+	on(c, protocol.EventPostSubscribers, fn)
+}
+
+// OnReactionUpdated registers fn for reaction count changes
+func (c *Client) OnReactionUpdated(fn func(*protocol.ReactionUpdatedEvent)) {
+	on(c, protocol.EventReactionUpdated, fn)
+}
+
+// OnReadReceipt registers fn for read receipts in joined rooms
+func (c *Client) OnReadReceipt(fn func(*protocol.ReadReceiptEvent)) {
+	on(c, protocol.EventReadReceipt, fn)
+}
+
+// OnUnreadUpdate registers fn for the user's unread count changes
+func (c *Client) OnUnreadUpdate(fn func(*protocol.UnreadUpdateEvent)) {
+	on(c, protocol.EventUnreadUpdate, fn)
+}
+
+// OnMention registers fn for mentions of the user
+func (c *Client) OnMention(fn func(*protocol.MentionEvent)) {
+	on(c, protocol.EventMention, fn)
+}
+
+// OnSanction registers fn for USER_MUTED, USER_UNMUTED, USER_BANNED and USER_UNBANNED
+func (c *Client) OnSanction(fn func(*protocol.SanctionNoticeEvent)) {
+	on(c, protocol.EventUserMuted, fn)
+	on(c, protocol.EventUserUnmuted, fn)
+	on(c, protocol.EventUserBanned, fn)
+	on(c, protocol.EventUserUnbanned, fn)
+}
+
+// OnContentHeld registers fn for the user's messages and comments held for review
+func (c *Client) OnContentHeld(fn func(*protocol.ContentHeldEvent)) {
+	on(c, protocol.EventContentHeld, fn)
+}
+
+// OnError registers fn for ERROR events, including those that also fail a request
+func (c *Client) OnError(fn func(*protocol.ErrorEvent)) {
+	on(c, protocol.EventError, fn)
+}
+
+// dispatch hands an event to the request waiting for it, then to handlers
+func (c *Client) dispatch(data json.RawMessage) {
+	var base struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		log.Printf("client: dropping malformed event: %v", err)
+		return
+	}
+
+	c.resolve(base.Type, data)
+
+	c.mutex.Lock()
+	handlers := append(append([]func(json.RawMessage){}, c.handlers[base.Type]...), c.handlers["*"]...)
+	c.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(data)
+	}
+}
@@ -0,0 +1,352 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"websocket/pkg/protocol"
+)
+
+// ErrContentHeld is returned when moderation held a message or comment for
+// review instead of broadcasting it
+var ErrContentHeld = errors.New("content held for review")
+
+// Error is an ERROR event the server answered a request with
+type Error struct {
+	Code    string // e.g. protocol.ErrCodeRateLimited; empty for plain failures
+	Message string
+	Field   string // Offending field when Code is protocol.ErrCodeInvalidEvent
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+// request is a sent event waiting for its reply
+type request struct {
+	id        string
+	replyType string                                            // Reply echoes id as request_id
+	match     func(eventType string, data json.RawMessage) bool // Or is recognized by its content
+	done      chan result                                       // Buffered, so resolving never blocks the read loop
+}
+
+type result struct {
+	data json.RawMessage
+	err  error
+}
+
+// JoinRoom joins a room and waits for ROOM_JOINED. The room is rejoined after reconnects.
+func (c *Client) JoinRoom(ctx context.Context, room string) (*protocol.RoomJoinedEvent, error) {
+	reply := &protocol.RoomJoinedEvent{}
+	event := &protocol.JoinRoomEvent{Type: protocol.EventJoinRoom, Room: room}
+	err := c.request(ctx, event, reply, func(eventType string, data json.RawMessage) bool {
+		return eventType == protocol.EventRoomJoined && field(data, "room") == room
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.rooms[room] = true
+	c.mutex.Unlock()
+	return reply, nil
+}
+
+// SendMessage sends a chat message to a joined room and waits for its broadcast,
+// which carries the stored message_id and rendered HTML. Returns ErrContentHeld
+// when the message went to the review queue instead.
+func (c *Client) SendMessage(ctx context.Context, room, message string, attachmentIDs ...string) (*protocol.ChatMessageEvent, error) {
+	reply := &protocol.ChatMessageEvent{}
+	event := &protocol.ChatMessageEvent{
+		Type:          protocol.EventChatMessage,
+		Room:          room,
+		Message:       message,
+		AttachmentIDs: attachmentIDs,
+	}
+	err := c.request(ctx, event, reply, func(eventType string, data json.RawMessage) bool {
+		switch eventType {
+		case protocol.EventChatMessage:
+			return field(data, "room") == room && field(data, "user") == c.username
+		case protocol.EventContentHeld:
+			return field(data, "target_type") == "message" && field(data, "room") == room
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// PostComment comments on a post, or replies to a comment when replyTo is set,
+// and waits for the comment's broadcast. Commenting subscribes to the post, so
+// the post is resubscribed after reconnects too. Returns ErrContentHeld when
+// the comment went to the review queue instead.
+func (c *Client) PostComment(ctx context.Context, postID, comment, replyTo string) (*protocol.PostCommentEvent, error) {
+	reply := &protocol.PostCommentEvent{}
+	event := &protocol.PostCommentEvent{
+		Type:    protocol.EventPostComment,
+		PostID:  postID,
+		Comment: comment,
+		ReplyTo: replyTo,
+	}
+	err := c.request(ctx, event, reply, func(eventType string, data json.RawMessage) bool {
+		switch eventType {
+		case protocol.EventPostComment:
+			return field(data, "post_id") == postID && field(data, "user") == c.username
+		case protocol.EventContentHeld:
+			return field(data, "target_type") == "comment" && field(data, "post_id") == postID
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.posts[postID] = true
+	c.mutex.Unlock()
+	return reply, nil
+}
+
+// SubscribePost follows a post's comments and reader count. With snapshotLimit
+// above zero the reply also carries that many of the newest comments. The post
+// is resubscribed, without a snapshot, after reconnects.
+func (c *Client) SubscribePost(ctx context.Context, postID string, snapshotLimit int) (*protocol.PostSubscribedEvent, error) {
+	reply := &protocol.PostSubscribedEvent{}
+	event := &protocol.SubscribePostEvent{
+		Type:          protocol.EventSubscribePost,
+		PostID:        postID,
+		Snapshot:      snapshotLimit > 0,
+		SnapshotLimit: snapshotLimit,
+	}
+	err := c.request(ctx, event, reply, func(eventType string, data json.RawMessage) bool {
+		return eventType == protocol.EventPostSubscribed && field(data, "post_id") == postID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.posts[postID] = true
+	c.mutex.Unlock()
+	return reply, nil
+}
+
+// UnsubscribePost stops following a post
+func (c *Client) UnsubscribePost(ctx context.Context, postID string) error {
+	c.mutex.Lock()
+	delete(c.posts, postID)
+	c.mutex.Unlock()
+
+	event := &protocol.UnsubscribePostEvent{Type: protocol.EventUnsubscribePost, PostID: postID}
+	return c.request(ctx, event, nil, func(eventType string, data json.RawMessage) bool {
+		return eventType == protocol.EventPostUnsubscribed && field(data, "post_id") == postID
+	})
+}
+
+// SubscribeFeed follows the posts feed: every post created, updated or deleted,
+// and every new comment. The feed is resubscribed after reconnects.
+func (c *Client) SubscribeFeed(ctx context.Context) error {
+	event := &protocol.FeedEvent{Type: protocol.EventSubscribeFeed}
+	err := c.request(ctx, event, nil, func(eventType string, data json.RawMessage) bool {
+		return eventType == protocol.EventFeedSubscribed
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.feed = true
+	c.mutex.Unlock()
+	return nil
+}
+
+// UnsubscribeFeed stops following the posts feed
+func (c *Client) UnsubscribeFeed(ctx context.Context) error {
+	c.mutex.Lock()
+	c.feed = false
+	c.mutex.Unlock()
+
+	event := &protocol.FeedEvent{Type: protocol.EventUnsubscribeFeed}
+	return c.request(ctx, event, nil, func(eventType string, data json.RawMessage) bool {
+		return eventType == protocol.EventFeedUnsubscribed
+	})
+}
+
+// FetchHistory requests a page of room messages or post comments; set Room or
+// PostID and optionally Before, After and Limit. Type and RequestID are filled in.
+func (c *Client) FetchHistory(ctx context.Context, query protocol.FetchHistoryEvent) (*protocol.HistoryPageEvent, error) {
+	query.Type = protocol.EventFetchHistory
+	reply := &protocol.HistoryPageEvent{}
+	if err := c.requestByID(ctx, &query, reply, protocol.EventHistoryPage); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// Search runs a full-text search; set Query and optionally the filters. Type
+// and RequestID are filled in.
+func (c *Client) Search(ctx context.Context, query protocol.SearchEvent) (*protocol.SearchResultsEvent, error) {
+	query.Type = protocol.EventSearch
+	reply := &protocol.SearchResultsEvent{}
+	if err := c.requestByID(ctx, &query, reply, protocol.EventSearchResults); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// request sends event and waits for the first event match accepts, decoding it
+// into reply when reply is non-nil. Replies are matched in the order requests
+// were sent, which is the order the server answers them in.
+func (c *Client) request(ctx context.Context, event interface{}, reply interface{}, match func(eventType string, data json.RawMessage) bool) error {
+	return c.do(ctx, event, reply, &request{match: match})
+}
+
+// requestByID sends event and waits for the replyType event that echoes its request_id
+func (c *Client) requestByID(ctx context.Context, event interface{}, reply interface{}, replyType string) error {
+	return c.do(ctx, event, reply, &request{replyType: replyType})
+}
+
+// do tags event with a fresh request_id and sends it for req. An ERROR carrying
+// the request_id fails the request with *Error, and a CONTENT_HELD reply with
+// ErrContentHeld.
+func (c *Client) do(ctx context.Context, event interface{}, reply interface{}, req *request) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.RequestTimeout)
+		defer cancel()
+	}
+
+	c.mutex.Lock()
+	c.nextID++
+	req.id = "req-" + strconv.FormatUint(c.nextID, 10)
+	req.done = make(chan result, 1)
+	c.mutex.Unlock()
+
+	data, err := withRequestID(event, req.id)
+	if err != nil {
+		return err
+	}
+
+	// Register before writing, as the reply can beat the write's return
+	c.mutex.Lock()
+	c.pending = append(c.pending, req)
+	c.mutex.Unlock()
+
+	if err := c.write(data); err != nil {
+		c.removePending(req)
+		return err
+	}
+
+	select {
+	case res := <-req.done:
+		if res.err != nil {
+			return res.err
+		}
+		if field(res.data, "type") == protocol.EventContentHeld {
+			return fmt.Errorf("%w: %s", ErrContentHeld, field(res.data, "reason"))
+		}
+		if reply != nil {
+			if err := json.Unmarshal(res.data, reply); err != nil {
+				return fmt.Errorf("failed to decode reply: %v", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		c.removePending(req)
+		return ctx.Err()
+	}
+}
+
+// resolve completes the oldest request waiting for this event, if any
+func (c *Client) resolve(eventType string, data json.RawMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if eventType == protocol.EventError {
+		var errorEvent protocol.ErrorEvent
+		if json.Unmarshal(data, &errorEvent) != nil || errorEvent.RequestID == "" {
+			return
+		}
+		for i, req := range c.pending {
+			if req.id == errorEvent.RequestID {
+				c.pending = append(c.pending[:i], c.pending[i+1:]...)
+				req.done <- result{err: &Error{Code: errorEvent.Code, Message: errorEvent.Message, Field: errorEvent.Field}}
+				return
+			}
+		}
+		return
+	}
+
+	for i, req := range c.pending {
+		if req.matches(eventType, data) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			req.done <- result{data: data}
+			return
+		}
+	}
+}
+
+func (c *Client) removePending(req *request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, pending := range c.pending {
+		if pending == req {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// failPending fails every request in flight, e.g. when the connection drops
+func (c *Client) failPending(err error) {
+	c.mutex.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mutex.Unlock()
+
+	for _, req := range pending {
+		req.done <- result{err: err}
+	}
+}
+
+// withRequestID encodes event with a request_id field added
+func withRequestID(event interface{}, id string) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode event: %v", err)
+	}
+	fields["request_id"], _ = json.Marshal(id)
+	return json.Marshal(fields)
+}
+
+func (r *request) matches(eventType string, data json.RawMessage) bool {
+	if r.replyType != "" {
+		return eventType == r.replyType && field(data, "request_id") == r.id
+	}
+	return r.match(eventType, data)
+}
+
+// field reads a top-level string field of a raw event
+func field(data json.RawMessage, name string) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+	var value string
+	json.Unmarshal(fields[name], &value)
+	return value
+}
@@ -1,116 +1,117 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
 
 	_ "modernc.org/sqlite"
 )
 
 type DB struct {
 	*sql.DB
+	pin *sql.Conn // Keeps an in-memory database alive between queries
 }
 
-func NewDatabase() (*DB, error) {
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./chat.db"
-	}
+// memoryDatabases numbers in-memory databases so each one is private
+var memoryDatabases atomic.Int64
 
-	db, err := sql.Open("sqlite", dbPath)
+// NewDatabase opens the database named by DB_PATH, applies pending migrations
+// and inserts the demo data
+func NewDatabase() (*DB, error) {
+	database, err := Open(Path())
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
 
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	database := &DB{DB: db}
-	
-	if err := database.migrate(); err != nil {
+	if _, err := database.MigrateUp(0); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	log.Println("Database migration completed successfully")
 
 	// Insert sample data for demo
 	if err := database.InsertSampleData(); err != nil {
 		log.Printf("Warning: Failed to insert sample data: %v", err)
 	}
 
+	// Older rows and the sample data have no rendered HTML yet
+	if err := database.backfillContentHTML(); err != nil {
+		return nil, fmt.Errorf("failed to render stored content: %w", err)
+	}
+
 	log.Println("Database connected successfully")
 	return database, nil
 }
 
-func (db *DB) migrate() error {
-	// Messages table
-	createMessagesTable := `
-	CREATE TABLE IF NOT EXISTS messages (
-		id TEXT PRIMARY KEY,
-		username TEXT NOT NULL,
-		content TEXT NOT NULL,
-		room_id TEXT NOT NULL,
-		type TEXT NOT NULL DEFAULT 'message',
-		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	// Posts table
-	createPostsTable := `
-	CREATE TABLE IF NOT EXISTS posts (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		content TEXT NOT NULL,
-		author_id TEXT NOT NULL,
-		author_name TEXT NOT NULL,
-		comment_count INTEGER DEFAULT 0,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	// Comments table
-	createCommentsTable := `
-	CREATE TABLE IF NOT EXISTS comments (
-		id TEXT PRIMARY KEY,
-		post_id TEXT NOT NULL,
-		content TEXT NOT NULL,
-		author_id TEXT NOT NULL,
-		author_name TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
-	);`
-
-	// Create indexes
-	createIndexes := `
-	CREATE INDEX IF NOT EXISTS idx_messages_room_id ON messages(room_id);
-	CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
-	CREATE INDEX IF NOT EXISTS idx_messages_room_timestamp ON messages(room_id, timestamp);
-	
-	CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at);
-	CREATE INDEX IF NOT EXISTS idx_posts_author_id ON posts(author_id);
-	
-	CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments(post_id);
-	CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments(created_at);
-	CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments(author_id);`
-
-	// Execute migrations
-	tables := []string{createMessagesTable, createPostsTable, createCommentsTable}
-	for _, table := range tables {
-		if _, err := db.Exec(table); err != nil {
-			return fmt.Errorf("failed to create table: %w", err)
-		}
+// NewMemoryDatabase creates a migrated database that lives only in process
+// memory, for demo mode and tests. It starts empty and is gone once closed.
+func NewMemoryDatabase() (*DB, error) {
+	// The memdb VFS shares one database between the pool's connections and,
+	// unlike shared-cache :memory:, honours busy_timeout
+	name := fmt.Sprintf("file:/memory-%d?vfs=memdb", memoryDatabases.Add(1))
+	database, err := Open(name)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, err := db.Exec(createIndexes); err != nil {
-		return fmt.Errorf("failed to create indexes: %w", err)
+	// The database is freed when its last connection closes, so hold one open
+	database.pin, err = database.Conn(context.Background())
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to pin in-memory database: %w", err)
 	}
 
-	log.Println("Database migration completed successfully")
-	return nil
+	if _, err := database.MigrateUp(0); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return database, nil
+}
+
+// Path returns DB_PATH, or ./chat.db when it is unset
+func Path() string {
+	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
+		return dbPath
+	}
+	return "./chat.db"
+}
+
+// Open connects to a database without migrating it
+func Open(dbPath string) (*DB, error) {
+	// Wait for locks instead of failing with SQLITE_BUSY while another
+	// connection (e.g. the retention purger) is writing. Transactions take the
+	// write lock when they begin: a deferred one that reads and then writes
+	// can fail immediately rather than wait for a concurrent writer.
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite", dbPath+separator+"_pragma=busy_timeout(5000)&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	// New databases can hand freed pages back to the file system with
+	// incremental_vacuum; this has no effect once tables exist
+	if _, err := db.Exec("PRAGMA auto_vacuum = INCREMENTAL"); err != nil {
+		return nil, fmt.Errorf("failed to set auto_vacuum: %w", err)
+	}
+
+	return &DB{DB: db}, nil
 }
 
 func (db *DB) Close() error {
+	if db.pin != nil {
+		db.pin.Close()
+	}
 	return db.DB.Close()
-}
\ No newline at end of file
+}
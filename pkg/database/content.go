@@ -0,0 +1,70 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"websocket/pkg/markdown"
+)
+
+// backfillContentHTML renders content_html for messages and comments stored
+// before the column existed. Rows that already have HTML are left untouched.
+func (db *DB) backfillContentHTML() error {
+	for _, table := range []string{"messages", "comments"} {
+		count, err := db.backfillTable(table)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("Rendered content_html for %d %s", count, table)
+		}
+	}
+	return nil
+}
+
+func (db *DB) backfillTable(table string) (int, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT id, content FROM %s WHERE content_html IS NULL", table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s without content_html: %w", table, err)
+	}
+
+	rendered := make(map[string]string)
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		rendered[id] = markdown.Render(content)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate %s: %w", table, err)
+	}
+	if len(rendered) == 0 {
+		return 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("UPDATE %s SET content_html = ? WHERE id = ?", table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare %s update: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for id, html := range rendered {
+		if _, err := stmt.Exec(html, id); err != nil {
+			return 0, fmt.Errorf("failed to store content_html for %s %s: %w", table, id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit content_html: %w", err)
+	}
+	return len(rendered), nil
+}
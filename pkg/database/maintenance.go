@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// auto_vacuum values reported by PRAGMA auto_vacuum
+const (
+	autoVacuumNone        = 0
+	autoVacuumIncremental = 2
+)
+
+// SpaceStats describes how much of the database file is in use
+type SpaceStats struct {
+	PageSize   int64  `json:"page_size"`
+	PageCount  int64  `json:"page_count"`
+	FreePages  int64  `json:"free_pages"`
+	SizeBytes  int64  `json:"size_bytes"`
+	FreeBytes  int64  `json:"free_bytes"`
+	AutoVacuum string `json:"auto_vacuum"` // "none", "full" or "incremental"
+}
+
+// SpaceStats reads the page counts SQLite keeps in the database header
+func (db *DB) SpaceStats() (*SpaceStats, error) {
+	stats := &SpaceStats{}
+	var autoVacuum int
+
+	for _, pragma := range []struct {
+		name string
+		dest interface{}
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.PageCount},
+		{"freelist_count", &stats.FreePages},
+		{"auto_vacuum", &autoVacuum},
+	} {
+		if err := db.QueryRow("PRAGMA " + pragma.name).Scan(pragma.dest); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", pragma.name, err)
+		}
+	}
+
+	stats.SizeBytes = stats.PageSize * stats.PageCount
+	stats.FreeBytes = stats.PageSize * stats.FreePages
+	switch autoVacuum {
+	case autoVacuumNone:
+		stats.AutoVacuum = "none"
+	case autoVacuumIncremental:
+		stats.AutoVacuum = "incremental"
+	default:
+		stats.AutoVacuum = "full"
+	}
+	return stats, nil
+}
+
+// IncrementalVacuum returns free pages to the file system. It only works once the
+// database is in incremental auto_vacuum mode; see EnableIncrementalVacuum.
+func (db *DB) IncrementalVacuum() error {
+	if _, err := db.Exec("PRAGMA incremental_vacuum"); err != nil {
+		return fmt.Errorf("failed to run incremental vacuum: %w", err)
+	}
+	return nil
+}
+
+// EnableIncrementalVacuum switches an existing database to incremental
+// auto_vacuum. The switch needs one full VACUUM, so it is a no-op when the mode
+// is already set.
+func (db *DB) EnableIncrementalVacuum() error {
+	var mode int
+	if err := db.QueryRow("PRAGMA auto_vacuum").Scan(&mode); err != nil {
+		return fmt.Errorf("failed to read auto_vacuum: %w", err)
+	}
+	if mode == autoVacuumIncremental {
+		return nil
+	}
+	return db.vacuum("PRAGMA auto_vacuum = INCREMENTAL")
+}
+
+// Vacuum rebuilds the database file to reclaim all free space
+func (db *DB) Vacuum() error {
+	return db.vacuum("")
+}
+
+// vacuum runs VACUUM, preceded by an optional pragma, on one dedicated
+// connection. VACUUM can renumber rowids, so the full-text indexes are rebuilt
+// afterwards.
+func (db *DB) vacuum(pragma string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for vacuum: %w", err)
+	}
+	defer conn.Close()
+
+	if pragma != "" {
+		if _, err := conn.ExecContext(ctx, pragma); err != nil {
+			return fmt.Errorf("failed to set %q: %w", pragma, err)
+		}
+	}
+	if _, err := conn.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum: %w", err)
+	}
+
+	return db.RebuildSearchIndexes()
+}
@@ -0,0 +1,390 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations are numbered SQL files, NNNN_name.up.sql with an optional
+// NNNN_name.down.sql, compiled into the binary. Each one runs in its own
+// transaction and is recorded in schema_migrations with a checksum of its up
+// script, so an edited migration is caught instead of silently skipped.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFilename = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+const createSchemaMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	);`
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string // Empty when the migration can't be rolled back
+	Checksum string // SHA-256 of Up
+}
+
+// MigrationStatus pairs a known migration with what the database has recorded
+type MigrationStatus struct {
+	*Migration
+	Applied          bool
+	AppliedAt        *time.Time
+	ChecksumMismatch bool // Applied from a different version of the file
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// LoadMigrations returns the embedded migrations in version order
+func LoadMigrations() ([]*Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		m := migrationFilename.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file name %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+
+		data, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(data)
+			sum := sha256.Sum256(data)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies up to steps pending migrations (all of them when steps is 0)
+// and returns the ones it applied
+func (db *DB) MigrateUp(steps int) ([]*Migration, error) {
+	migrations, applied, err := db.prepareMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(done) == steps {
+			break
+		}
+		if err := db.applyMigration(migration, migration.Up, true); err != nil {
+			return done, err
+		}
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown rolls back the steps most recently applied migrations and returns
+// the ones it rolled back
+func (db *DB) MigrateDown(steps int) ([]*Migration, error) {
+	migrations, applied, err := db.prepareMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+		}
+		if err := db.applyMigration(migration, migration.Down, false); err != nil {
+			return done, err
+		}
+		log.Printf("Rolled back migration %04d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrationStatus lists every known migration and whether it has been applied
+func (db *DB) MigrationStatus() ([]*MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := &MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.ChecksumMismatch = record.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// prepareMigrations loads the migrations and checks them against the database:
+// every applied migration must still exist and be unchanged
+func (db *DB) prepareMigrations() ([]*Migration, map[int]appliedMigration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.adoptLegacySchema(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := db.Exec(createSchemaMigrationsTable); err != nil {
+		return nil, nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	known := make(map[int]*Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+	for version, record := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return nil, nil, fmt.Errorf("database has migration %d applied, which this build doesn't know; it was probably written by a newer version", version)
+		}
+		if record.checksum != migration.Checksum {
+			return nil, nil, fmt.Errorf("migration %04d_%s was changed after it was applied (checksum mismatch); add a new migration instead of editing it", version, migration.Name)
+		}
+	}
+	return migrations, applied, nil
+}
+
+// appliedMigrations reads schema_migrations; a database without the table has
+// nothing applied
+func (db *DB) appliedMigrations() (map[int]appliedMigration, error) {
+	applied := make(map[int]appliedMigration)
+
+	var exists int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if exists == 0 {
+		return applied, nil
+	}
+
+	rows, err := db.Query(`SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schema_migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// applyMigration runs one script and records (or forgets) the migration in the
+// same transaction, so a failing script leaves no trace
+func (db *DB) applyMigration(migration *Migration, script string, up bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			migration.Version, migration.Name, migration.Checksum, time.Now().Format("2006-01-02 15:04:05"))
+	} else {
+		_, err = tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// adoptLegacySchema brings databases created before versioned migrations up to
+// the baseline. Their tables already exist, so the baseline's CREATE TABLE IF
+// NOT EXISTS statements skip them; only columns that early builds added with
+// ALTER TABLE may be missing.
+func (db *DB) adoptLegacySchema() error {
+	var versioned, legacy int
+	err := db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'),
+			(SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'messages')
+	`).Scan(&versioned, &legacy)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if versioned > 0 || legacy == 0 {
+		return nil
+	}
+
+	log.Println("Adopting database created before versioned migrations")
+	for _, column := range []struct{ table, name, definition string }{
+		{"comments", "parent_id", "TEXT"},
+		{"messages", "content_html", "TEXT"},
+		{"comments", "content_html", "TEXT"},
+	} {
+		if err := db.addColumnIfMissing(column.table, column.name, column.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing adds a column to an existing table unless it is already there
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	var exists int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	if exists == 0 {
+		// The baseline creates it with the column
+		return nil
+	}
+
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	log.Printf("Added column %s.%s", table, column)
+	return nil
+}
+
+// CreateMigration writes empty up and down scripts for a new migration into dir,
+// numbered after the highest version already there
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+	next := 1
+	for _, entry := range entries {
+		if m := migrationFilename.FindStringSubmatch(entry.Name()); m != nil {
+			if version, _ := strconv.Atoi(m[1]); version >= next {
+				next = version + 1
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+	templates := map[string]string{
+		upPath:   fmt.Sprintf("-- %s\n", strings.ReplaceAll(name, "_", " ")),
+		downPath: "-- Undo the up migration\n",
+	}
+	for path, content := range templates {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return "", "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return upPath, downPath, nil
+}
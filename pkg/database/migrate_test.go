@@ -0,0 +1,59 @@
+package database
+
+import "testing"
+
+func countReactions(t *testing.T, db *DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reactions`).Scan(&n); err != nil {
+		t.Fatalf("count reactions: %v", err)
+	}
+	return n
+}
+
+func addReactedMessage(t *testing.T, db *DB, id string) {
+	t.Helper()
+	if _, err := db.Exec(`INSERT INTO messages (id, username, content, room_id) VALUES (?, 'alice', 'hi', 'general')`, id); err != nil {
+		t.Fatalf("insert message: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO reactions (target_type, target_id, username, emoji) VALUES ('message', ?, 'bob', '👍')`, id); err != nil {
+		t.Fatalf("insert reaction: %v", err)
+	}
+}
+
+// 0002_reaction_cleanup drops reactions orphaned before it ran and removes
+// reactions with their message from then on; rolling it back restores the
+// baseline behaviour
+func TestReactionCleanupMigration(t *testing.T) {
+	db, err := NewMemoryDatabase()
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.MigrateDown(1); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	addReactedMessage(t, db, "m1")
+	if _, err := db.Exec(`DELETE FROM messages WHERE id = 'm1'`); err != nil {
+		t.Fatalf("delete message: %v", err)
+	}
+	if n := countReactions(t, db); n != 1 {
+		t.Fatalf("%d reactions after deleting their message at the baseline, want the orphan kept", n)
+	}
+
+	if _, err := db.MigrateUp(0); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if n := countReactions(t, db); n != 0 {
+		t.Fatalf("%d reactions after migrating, want the orphan dropped", n)
+	}
+
+	addReactedMessage(t, db, "m2")
+	if _, err := db.Exec(`DELETE FROM messages WHERE id = 'm2'`); err != nil {
+		t.Fatalf("delete message: %v", err)
+	}
+	if n := countReactions(t, db); n != 0 {
+		t.Errorf("%d reactions after deleting their message, want 0", n)
+	}
+}
@@ -0,0 +1,16 @@
+-- Drops everything the baseline created. All data is lost.
+
+DROP TABLE IF EXISTS comments_fts;
+DROP TABLE IF EXISTS posts_fts;
+DROP TABLE IF EXISTS messages_fts;
+
+DROP TABLE IF EXISTS room_sanctions;
+DROP TABLE IF EXISTS room_roles;
+DROP TABLE IF EXISTS moderation_queue;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS mentions;
+DROP TABLE IF EXISTS room_reads;
+DROP TABLE IF EXISTS reactions;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS messages;
@@ -0,0 +1,209 @@
+-- Baseline: the schema as it was before versioned migrations. Everything is
+-- IF NOT EXISTS so databases created by earlier builds are adopted as they are.
+
+-- Messages table
+CREATE TABLE IF NOT EXISTS messages (
+	id TEXT PRIMARY KEY,
+	username TEXT NOT NULL,
+	content TEXT NOT NULL,
+	room_id TEXT NOT NULL,
+	type TEXT NOT NULL DEFAULT 'message',
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	content_html TEXT
+);
+
+-- Posts table
+CREATE TABLE IF NOT EXISTS posts (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	content TEXT NOT NULL,
+	author_id TEXT NOT NULL,
+	author_name TEXT NOT NULL,
+	comment_count INTEGER DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Comments table
+CREATE TABLE IF NOT EXISTS comments (
+	id TEXT PRIMARY KEY,
+	post_id TEXT NOT NULL,
+	content TEXT NOT NULL,
+	author_id TEXT NOT NULL,
+	author_name TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	parent_id TEXT,
+	content_html TEXT,
+	FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+-- Reactions table: one row per (target, user, emoji) keeps reactions idempotent
+CREATE TABLE IF NOT EXISTS reactions (
+	target_type TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	username TEXT NOT NULL,
+	emoji TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (target_type, target_id, username, emoji)
+);
+
+-- Read positions: (last_read_at, last_read_message_id) mark the newest message a user has seen in a room
+CREATE TABLE IF NOT EXISTS room_reads (
+	username TEXT NOT NULL,
+	room_id TEXT NOT NULL,
+	last_read_message_id TEXT,
+	last_read_at DATETIME,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (username, room_id)
+);
+
+-- Mentions: one row per mentioned user per message or comment
+CREATE TABLE IF NOT EXISTS mentions (
+	username TEXT NOT NULL,
+	source_type TEXT NOT NULL,
+	source_id TEXT NOT NULL,
+	room_id TEXT,
+	post_id TEXT,
+	author TEXT NOT NULL,
+	excerpt TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (username, source_type, source_id)
+);
+
+-- Attachments: uploaded files, linked to one message or comment once referenced
+CREATE TABLE IF NOT EXISTS attachments (
+	id TEXT PRIMARY KEY,
+	filename TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	size INTEGER NOT NULL,
+	width INTEGER NOT NULL DEFAULT 0,
+	height INTEGER NOT NULL DEFAULT 0,
+	storage_key TEXT NOT NULL,
+	thumbnail_key TEXT NOT NULL DEFAULT '',
+	uploaded_by TEXT NOT NULL,
+	target_type TEXT,
+	target_id TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Moderation queue: quarantined messages and comments waiting for review
+CREATE TABLE IF NOT EXISTS moderation_queue (
+	id TEXT PRIMARY KEY,
+	target_type TEXT NOT NULL,
+	room_id TEXT,
+	post_id TEXT,
+	parent_id TEXT,
+	author TEXT NOT NULL,
+	content TEXT NOT NULL,
+	attachment_ids TEXT NOT NULL DEFAULT '',
+	reason TEXT NOT NULL,
+	filter TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	reviewed_by TEXT,
+	published_id TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	reviewed_at DATETIME
+);
+
+-- Room roles: owners and moderators of a room
+CREATE TABLE IF NOT EXISTS room_roles (
+	room_id TEXT NOT NULL,
+	username TEXT NOT NULL,
+	role TEXT NOT NULL,
+	granted_by TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (room_id, username)
+);
+
+-- Room sanctions: at most one ban and one mute per user per room; expires_at NULL is permanent
+CREATE TABLE IF NOT EXISTS room_sanctions (
+	room_id TEXT NOT NULL,
+	username TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	issued_by TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	expires_at DATETIME,
+	PRIMARY KEY (room_id, username, kind)
+);
+
+-- Drop mentions along with the message or comment that made them
+CREATE TRIGGER IF NOT EXISTS mentions_message_delete AFTER DELETE ON messages BEGIN
+	DELETE FROM mentions WHERE source_type = 'message' AND source_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS mentions_comment_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM mentions WHERE source_type = 'comment' AND source_id = old.id;
+END;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_messages_room_id ON messages(room_id);
+CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+CREATE INDEX IF NOT EXISTS idx_messages_room_timestamp ON messages(room_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_messages_room_keyset ON messages(room_id, timestamp, id);
+
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_author_id ON posts(author_id);
+CREATE INDEX IF NOT EXISTS idx_posts_keyset ON posts(created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments(post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments(created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments(author_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_keyset ON comments(post_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_reactions_target ON reactions(target_type, target_id);
+
+CREATE INDEX IF NOT EXISTS idx_room_reads_room_id ON room_reads(room_id);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_username_created ON mentions(username, created_at, source_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_source ON mentions(source_type, source_id);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_target ON attachments(target_type, target_id);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_queue_status ON moderation_queue(status, created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_room_sanctions_expires ON room_sanctions(expires_at);
+
+-- Full-text search: FTS5 indexes over the base tables' rowids, kept in sync by triggers
+CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(content, content='messages', content_rowid='rowid', tokenize='unicode61 remove_diacritics 2');
+CREATE TRIGGER IF NOT EXISTS messages_fts_insert AFTER INSERT ON messages BEGIN
+	INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_delete AFTER DELETE ON messages BEGIN
+	INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_fts_update AFTER UPDATE OF content ON messages BEGIN
+	INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+	INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS posts_fts USING fts5(title, content, content='posts', content_rowid='rowid', tokenize='unicode61 remove_diacritics 2');
+CREATE TRIGGER IF NOT EXISTS posts_fts_insert AFTER INSERT ON posts BEGIN
+	INSERT INTO posts_fts(rowid, title, content) VALUES (new.rowid, new.title, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS posts_fts_delete AFTER DELETE ON posts BEGIN
+	INSERT INTO posts_fts(posts_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS posts_fts_update AFTER UPDATE OF title, content ON posts BEGIN
+	INSERT INTO posts_fts(posts_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+	INSERT INTO posts_fts(rowid, title, content) VALUES (new.rowid, new.title, new.content);
+END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(content, content='comments', content_rowid='rowid', tokenize='unicode61 remove_diacritics 2');
+CREATE TRIGGER IF NOT EXISTS comments_fts_insert AFTER INSERT ON comments BEGIN
+	INSERT INTO comments_fts(rowid, content) VALUES (new.rowid, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_delete AFTER DELETE ON comments BEGIN
+	INSERT INTO comments_fts(comments_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_update AFTER UPDATE OF content ON comments BEGIN
+	INSERT INTO comments_fts(comments_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+	INSERT INTO comments_fts(rowid, content) VALUES (new.rowid, new.content);
+END;
+
+-- Index rows that existed before the search tables did
+INSERT INTO messages_fts(messages_fts) VALUES ('rebuild');
+INSERT INTO posts_fts(posts_fts) VALUES ('rebuild');
+INSERT INTO comments_fts(comments_fts) VALUES ('rebuild');
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS reactions_comment_delete;
+DROP TRIGGER IF EXISTS reactions_message_delete;
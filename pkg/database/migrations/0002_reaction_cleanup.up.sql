@@ -0,0 +1,14 @@
+-- Reactions go with the message or comment they were left on, however it is
+-- deleted: by its author, by a thread cascade or by the retention purge.
+
+CREATE TRIGGER IF NOT EXISTS reactions_message_delete AFTER DELETE ON messages BEGIN
+	DELETE FROM reactions WHERE target_type = 'message' AND target_id = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS reactions_comment_delete AFTER DELETE ON comments BEGIN
+	DELETE FROM reactions WHERE target_type = 'comment' AND target_id = old.id;
+END;
+
+-- Drop the reactions earlier deletions left behind
+DELETE FROM reactions
+WHERE (target_type = 'message' AND target_id NOT IN (SELECT id FROM messages))
+   OR (target_type = 'comment' AND target_id NOT IN (SELECT id FROM comments));
@@ -0,0 +1,26 @@
+package database
+
+import "fmt"
+
+// searchIndexes are the FTS5 tables created by the baseline migration. They use
+// external content keyed by the base table's rowid, so the text is stored once
+// and triggers only have to keep the inverted index in sync.
+var searchIndexes = []string{"messages_fts", "posts_fts", "comments_fts"}
+
+// RebuildSearchIndexes regenerates every full-text index from its base table. Run it
+// after anything that can renumber rowids, such as VACUUM.
+func (db *DB) RebuildSearchIndexes() error {
+	for _, name := range searchIndexes {
+		if err := db.rebuildSearchIndex(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) rebuildSearchIndex(name string) error {
+	if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES ('rebuild')`, name, name)); err != nil {
+		return fmt.Errorf("failed to rebuild search index %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+// Package markdown renders the small Markdown subset allowed in user content
+// (bold, italics, inline and fenced code, links and lists) to HTML.
+//
+// The renderer never passes user HTML through: every piece of text is escaped
+// and the only tags in the output are the ones emitted here, so the result is
+// safe to insert into a page as-is. Links are limited to http, https and mailto.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxNesting bounds how deep emphasis and links may nest before the rest is
+// rendered as plain text
+const maxNesting = 8
+
+var (
+	bulletItem  = regexp.MustCompile(`^ {0,3}[-*+][ \t]+(.*)$`)
+	orderedItem = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)][ \t]+(.*)$`)
+	fenceOpen   = regexp.MustCompile("^ {0,3}```[ \t]*([A-Za-z0-9_+-]*)[ \t]*$")
+	fenceClose  = regexp.MustCompile("^ {0,3}```[ \t]*$")
+)
+
+// Render converts Markdown source into sanitized HTML
+func Render(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	lines := strings.Split(source, "\n")
+
+	var out strings.Builder
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		out.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				out.WriteString("<br>\n")
+			}
+			out.WriteString(renderInline(strings.TrimSpace(line), true, 0))
+		}
+		out.WriteString("</p>\n")
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		// Fenced code block; an unterminated fence runs to the end of the input
+		if m := fenceOpen.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			i++
+			var code []string
+			for i < len(lines) && !fenceClose.MatchString(lines[i]) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // Skip the closing fence
+
+			if m[1] != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(strings.ToLower(m[1])) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			out.WriteString("</code></pre>\n")
+			continue
+		}
+
+		// Consecutive items of the same kind form one list
+		if bulletItem.MatchString(line) || orderedItem.MatchString(line) {
+			flushParagraph()
+			ordered := orderedItem.MatchString(line)
+			pattern, tag := bulletItem, "ul"
+			if ordered {
+				pattern, tag = orderedItem, "ol"
+			}
+
+			out.WriteString("<" + tag)
+			if ordered {
+				if start := strings.TrimLeft(orderedItem.FindStringSubmatch(line)[1], "0"); start != "" && start != "1" {
+					out.WriteString(` start="` + start + `"`)
+				}
+			}
+			out.WriteString(">\n")
+
+			for i < len(lines) {
+				m := pattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				out.WriteString("<li>" + renderInline(strings.TrimSpace(m[len(m)-1]), true, 0) + "</li>\n")
+				i++
+			}
+			out.WriteString("</" + tag + ">\n")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+		} else {
+			paragraph = append(paragraph, line)
+		}
+		i++
+	}
+	flushParagraph()
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// renderInline renders code spans, emphasis and links within a single line
+func renderInline(s string, allowLinks bool, depth int) string {
+	if depth > maxNesting {
+		return html.EscapeString(s)
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isEscapable(s[i+1]):
+			out.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				out.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			// A delimiter run opens emphasis only when text follows it directly;
+			// otherwise the whole run is literal (e.g. "****" from a masked word)
+			run := i
+			for run < len(s) && s[run] == c {
+				run++
+			}
+			if run < len(s) && !isSpace(s[run]) {
+				if rendered, n := renderEmphasis(s, i, allowLinks, depth); n > 0 {
+					out.WriteString(rendered)
+					i += n
+					continue
+				}
+			}
+			out.WriteString(html.EscapeString(s[i:run]))
+			i = run
+			continue
+
+		case c == '[' && allowLinks:
+			if rendered, n := renderLink(s, i, depth); n > 0 {
+				out.WriteString(rendered)
+				i += n
+				continue
+			}
+
+		case (c == 'h' || c == 'H') && allowLinks:
+			if rendered, n := renderAutolink(s, i); n > 0 {
+				out.WriteString(rendered)
+				i += n
+				continue
+			}
+		}
+
+		out.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return out.String()
+}
+
+// renderEmphasis renders **strong**, __strong__, *em* or _em_ starting at s[i].
+// It returns the number of bytes consumed, or 0 if there is no match.
+func renderEmphasis(s string, i int, allowLinks bool, depth int) (string, int) {
+	marker := s[i : i+1]
+	// Underscores inside words (snake_case) are literal
+	if marker == "_" && isWordBefore(s, i) {
+		return "", 0
+	}
+
+	for _, width := range []int{2, 1} {
+		delim := strings.Repeat(marker, width)
+		if !strings.HasPrefix(s[i:], delim) {
+			continue
+		}
+
+		start := i + width
+		end := findClosing(s, start, delim)
+		if end < 0 {
+			continue
+		}
+		if marker == "_" && isWordAfter(s, end+width) {
+			continue
+		}
+		// A run of nothing but markers (e.g. a masked word) stays literal
+		if strings.Trim(s[start:end], marker) == "" {
+			continue
+		}
+
+		tag := "em"
+		if width == 2 {
+			tag = "strong"
+		}
+		inner := renderInline(s[start:end], allowLinks, depth+1)
+		return "<" + tag + ">" + inner + "</" + tag + ">", end + width - i
+	}
+	return "", 0
+}
+
+// findClosing finds the closing delimiter for emphasis opened at start.
+// The content must be non-empty and must not start or end with whitespace.
+// Marker runs are taken whole: a single delimiter never closes on half of a
+// doubled one, and a run of three or more closes with its last markers, so
+// *a **b*** ends both the strong and the emphasis.
+func findClosing(s string, start int, delim string) int {
+	if start >= len(s) || isSpace(s[start]) {
+		return -1
+	}
+	marker := delim[0]
+	for j := start + 1; j+len(delim) <= len(s); j++ {
+		if s[j] == '`' {
+			// Don't close inside a code span
+			if end := strings.IndexByte(s[j+1:], '`'); end >= 0 {
+				j += end + 1
+				continue
+			}
+		}
+		if s[j] != marker {
+			continue
+		}
+		run := j
+		for run < len(s) && s[run] == marker {
+			run++
+		}
+		n := run - j
+		if !isSpace(s[j-1]) && (n == len(delim) || n >= 3) {
+			return run - len(delim)
+		}
+		j = run - 1
+	}
+	return -1
+}
+
+// renderLink renders [text](url) starting at s[i]; unsafe URLs are left as text
+func renderLink(s string, i int, depth int) (string, int) {
+	closeText := strings.IndexByte(s[i+1:], ']')
+	if closeText < 0 {
+		return "", 0
+	}
+	textEnd := i + 1 + closeText
+	if textEnd+1 >= len(s) || s[textEnd+1] != '(' {
+		return "", 0
+	}
+	urlEnd := closingParen(s, textEnd+2)
+	if urlEnd < 0 {
+		return "", 0
+	}
+
+	href, ok := safeURL(strings.TrimSpace(s[textEnd+2 : urlEnd]))
+	text := s[i+1 : textEnd]
+	// A [ in the text means this bracket is literal and a link starts later
+	if !ok || strings.TrimSpace(text) == "" || strings.IndexByte(text, '[') >= 0 {
+		return "", 0
+	}
+
+	return anchor(href, renderInline(text, false, depth+1)), urlEnd + 1 - i
+}
+
+// closingParen finds the ) ending a link destination that starts at s[start],
+// allowing balanced parentheses inside it as in https://en.wikipedia.org/wiki/Go_(language)
+func closingParen(s string, start int) int {
+	depth := 0
+	for j := start; j < len(s); j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return j
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// renderAutolink links a bare http(s) URL starting at s[i]
+func renderAutolink(s string, i int) (string, int) {
+	rest := strings.ToLower(s[i:])
+	if !strings.HasPrefix(rest, "http://") && !strings.HasPrefix(rest, "https://") {
+		return "", 0
+	}
+	if isWordBefore(s, i) {
+		return "", 0
+	}
+
+	end := i
+	for end < len(s) && !isSpace(s[end]) && s[end] != '<' && s[end] != '>' && s[end] != '"' {
+		end++
+	}
+	// Trailing punctuation belongs to the sentence, not the URL
+	for end > i && strings.IndexByte(".,;:!?)'*_", s[end-1]) >= 0 {
+		end--
+	}
+
+	raw := s[i:end]
+	href, ok := safeURL(raw)
+	if !ok {
+		return "", 0
+	}
+	return anchor(href, html.EscapeString(raw)), end - i
+}
+
+// safeURL accepts absolute http, https and mailto URLs
+func safeURL(raw string) (string, bool) {
+	if raw == "" || strings.ContainsAny(raw, " \t\n<>\"") {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", false
+		}
+	case "mailto":
+		if u.Opaque == "" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	return raw, true
+}
+
+func anchor(href, inner string) string {
+	return `<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer" target="_blank">` + inner + `</a>`
+}
+
+func isEscapable(c byte) bool {
+	return strings.IndexByte("\\`*_[]()#+-.!<>", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWordBefore(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isWordAfter(s string, i int) bool {
+	if i >= len(s) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,187 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const linkAttrs = ` rel="nofollow noopener noreferrer" target="_blank"`
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		// Block structure
+		{"paragraph", "hello", "<p>hello</p>"},
+		{"line breaks", "a\nb", "<p>a<br>\nb</p>"},
+		{"paragraphs", "a\n\nb", "<p>a</p>\n<p>b</p>"},
+		{"bullet list", "- a\n* b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>"},
+		{"ordered list start", "3. x\n4. y", "<ol start=\"3\">\n<li>x</li>\n<li>y</li>\n</ol>"},
+		{"fenced code", "```go\nfmt.Println(\"<b>\")\n```", "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;b&gt;&#34;)</code></pre>"},
+
+		// Emphasis, including nesting
+		{"strong", "**bold**", "<p><strong>bold</strong></p>"},
+		{"em", "*em* and _em_", "<p><em>em</em> and <em>em</em></p>"},
+		{"em in strong", "**bold *em* bold**", "<p><strong>bold <em>em</em> bold</strong></p>"},
+		{"strong in em", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		{"triple run", "***x***", "<p><strong><em>x</em></strong></p>"},
+		{"em ending in strong", "*a **b***", "<p><em>a <strong>b</strong></em></p>"},
+		{"strong ending in em", "**a *b***", "<p><strong>a <em>b</em></strong></p>"},
+		{"snake_case is literal", "snake_case_name", "<p>snake_case_name</p>"},
+		{"masked word is literal", "what the **** is", "<p>what the **** is</p>"},
+		{"spaced stars are literal", "2 * 3 * 4", "<p>2 * 3 * 4</p>"},
+		{"escaped markers", `\*not em\*`, "<p>*not em*</p>"},
+		{"no emphasis in code", "`*a*` *b*", "<p><code>*a*</code> <em>b</em></p>"},
+		{"deep nesting is bounded", strings.Repeat("*a ", 12) + "x" + strings.Repeat(" a*", 12), ""},
+
+		// Links
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com"` + linkAttrs + `>site</a></p>`},
+		{"uppercase scheme", "[x](HTTPS://Example.com)", `<p><a href="HTTPS://Example.com"` + linkAttrs + `>x</a></p>`},
+		{"mailto", "[mail](mailto:a@b.c)", `<p><a href="mailto:a@b.c"` + linkAttrs + `>mail</a></p>`},
+		{"emphasis in link text", "[**bold** link](https://e.com)", `<p><a href="https://e.com"` + linkAttrs + `><strong>bold</strong> link</a></p>`},
+		{"balanced parens in url", "[Go](https://en.wikipedia.org/wiki/Go_(language))", `<p><a href="https://en.wikipedia.org/wiki/Go_(language)"` + linkAttrs + `>Go</a></p>`},
+		{"nested brackets", "[[nested](https://a.com)]", `<p>[<a href="https://a.com"` + linkAttrs + `>nested</a>]</p>`},
+		{"no links in link text", "[see https://a.com](https://b.com)", `<p><a href="https://b.com"` + linkAttrs + `>see https://a.com</a></p>`},
+		{"empty link text", "[ ](https://e.com)", `<p>[ ](<a href="https://e.com"` + linkAttrs + `>https://e.com</a>)</p>`},
+		{"autolink", "see https://e.com/x.", `<p>see <a href="https://e.com/x"` + linkAttrs + `>https://e.com/x</a>.</p>`},
+		{"autolink inside word", "xhttp://a.com", "<p>xhttp://a.com</p>"},
+
+		// Unsafe schemes stay text
+		{"javascript", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>"},
+		{"mixed-case javascript", "[x](JaVaScRiPt:alert)", "<p>[x](JaVaScRiPt:alert)</p>"},
+		{"javascript with spaces", "[x]( javascript:alert)", "<p>[x]( javascript:alert)</p>"},
+		{"data", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>[x](data:text/html;base64,PHNjcmlwdD4=)</p>"},
+		{"vbscript", "[x](vbscript:msgbox)", "<p>[x](vbscript:msgbox)</p>"},
+		{"decimal entity scheme", "[x](&#106;avascript:alert)", "<p>[x](&amp;#106;avascript:alert)</p>"},
+		{"hex entity scheme", "[x](&#x6A;avascript:alert)", "<p>[x](&amp;#x6A;avascript:alert)</p>"},
+		{"entity inside scheme", "[x](java&#115;cript:alert)", "<p>[x](java&amp;#115;cript:alert)</p>"},
+		{"percent-encoded scheme", "[x](%6Aavascript:alert)", "<p>[x](%6Aavascript:alert)</p>"},
+		{"protocol-relative", "[x](//evil.com)", "<p>[x](//evil.com)</p>"},
+
+		// User HTML is always escaped
+		{"script tag", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"event handler", "<img src=x onerror=alert(1)>", "<p>&lt;img src=x onerror=alert(1)&gt;</p>"},
+		{"html in emphasis", "**<b>x</b>**", "<p><strong>&lt;b&gt;x&lt;/b&gt;</strong></p>"},
+		{"entities are not decoded", "&lt;script&gt;", "<p>&amp;lt;script&amp;gt;</p>"},
+		{"script in code block", "```\n<script>\n```", "<pre><code>&lt;script&gt;</code></pre>"},
+		{"quote breaks out of href", `[x](https://e.com" onmouseover="alert(1))`,
+			`<p>[x](<a href="https://e.com"` + linkAttrs + `>https://e.com</a>&#34; onmouseover=&#34;alert(1))</p>`},
+		{"quote after autolink", `https://e.com/"onmouseover=x`,
+			`<p><a href="https://e.com/"` + linkAttrs + `>https://e.com/</a>&#34;onmouseover=x</p>`},
+		{"angle brackets end autolink", "https://e.com/?q=<b>",
+			`<p><a href="https://e.com/?q="` + linkAttrs + `>https://e.com/?q=</a>&lt;b&gt;</p>`},
+
+		// Unterminated markup is literal
+		{"unterminated strong", "**unterminated", "<p>**unterminated</p>"},
+		{"unterminated em", "*a", "<p>*a</p>"},
+		{"unterminated code span", "`code", "<p>`code</p>"},
+		{"unterminated link text", "[text(https://e.com)", `<p>[text(<a href="https://e.com"` + linkAttrs + `>https://e.com</a>)</p>`},
+		{"unterminated link url", "[text](javascript:x", "<p>[text](javascript:x</p>"},
+		{"unterminated fence", "```\n**x**", "<pre><code>**x**</code></pre>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.in)
+			if tt.want == "" {
+				assertSafe(t, tt.in, got)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Render(%q)\n got  %q\n want %q", tt.in, got, tt.want)
+			}
+			assertSafe(t, tt.in, got)
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		raw string
+		ok  bool
+	}{
+		{"http://e.com", true},
+		{"https://e.com/path?q=1#x", true},
+		{"HtTpS://E.com", true},
+		{"mailto:a@b.c", true},
+		{"javascript:alert(1)", false},
+		{"JAVASCRIPT:alert(1)", false},
+		{"JaVaScRiPt:alert(1)", false},
+		{"data:text/html,<script>", false},
+		{"vbscript:x", false},
+		{"&#106;avascript:x", false},
+		{"%6Aavascript:x", false},
+		{"ftp://e.com", false},
+		{"//e.com", false},
+		{"/relative", false},
+		{"http:e.com", false},
+		{"https://", false},
+		{"mailto:", false},
+		{"https://e.com/a b", false},
+		{"https://e.com\t", false},
+		{`https://e.com/"x`, false},
+		{"https://e.com/<x>", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		href, ok := safeURL(tt.raw)
+		if ok != tt.ok {
+			t.Errorf("safeURL(%q) ok = %v, want %v", tt.raw, ok, tt.ok)
+		}
+		if ok && href != tt.raw {
+			t.Errorf("safeURL(%q) = %q, want it unchanged", tt.raw, href)
+		}
+	}
+}
+
+// TestRenderHostileInput renders inputs built to smuggle markup through and
+// checks only the renderer's own tags come out
+func TestRenderHostileInput(t *testing.T) {
+	payloads := []string{
+		`<script>alert(1)</script>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`[<img src=x onerror=alert(1)>](https://e.com)`,
+		`[x](https://e.com/" onclick="alert(1))`,
+		`**[x](javascript:alert(1))**`,
+		"`<script>` **<b onmouseover=x>**",
+		`[x](https://e.com)<svg onload=alert(1)>`,
+		`*<iframe src="javascript:x">*`,
+		"- <script>\n1. [a](data:text/html,x)",
+		"```\"><script>\nx\n```",
+		"```js\" onload=\"x\nx\n```",
+		`https://e.com/'onmouseover='alert(1)`,
+		"[" + strings.Repeat("[x](https://e.com)", 20),
+		strings.Repeat("**_", 50) + "x" + strings.Repeat("_**", 50),
+	}
+
+	for _, payload := range payloads {
+		assertSafe(t, payload, Render(payload))
+	}
+}
+
+var (
+	tagPattern  = regexp.MustCompile(`<[^>]*>`)
+	allowedTags = regexp.MustCompile(`^<(/?(p|br|strong|em|code|pre|ul|li|a)|ol( start="\d+")?|/ol|code class="language-[a-z0-9_+-]+"|a href="[^"<>]*"` + linkAttrs + `)>$`)
+	hrefPattern = regexp.MustCompile(`href="([^"]*)"`)
+)
+
+// assertSafe checks that every tag in out is one the renderer emits and that
+// every link points at an allowed scheme
+func assertSafe(t *testing.T, in, out string) {
+	t.Helper()
+	for _, tag := range tagPattern.FindAllString(out, -1) {
+		if !allowedTags.MatchString(tag) {
+			t.Errorf("Render(%q) emitted unexpected tag %q", in, tag)
+		}
+	}
+	for _, m := range hrefPattern.FindAllStringSubmatch(out, -1) {
+		href := strings.ToLower(m[1])
+		if !strings.HasPrefix(href, "http://") && !strings.HasPrefix(href, "https://") && !strings.HasPrefix(href, "mailto:") {
+			t.Errorf("Render(%q) linked to %q", in, m[1])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package protocol
+
+// AsyncAPIVersion is the AsyncAPI specification version of the generated document
+const AsyncAPIVersion = "2.6.0"
+
+// Version is the version of the WebSocket protocol described here
+const Version = "1.0.0"
+
+// AsyncAPIDocument is an AsyncAPI 2.6 description of the WebSocket protocol
+type AsyncAPIDocument struct {
+	AsyncAPI           string                     `json:"asyncapi"`
+	Info               AsyncAPIInfo               `json:"info"`
+	Servers            map[string]AsyncAPIServer  `json:"servers,omitempty"`
+	DefaultContentType string                     `json:"defaultContentType"`
+	Channels           map[string]AsyncAPIChannel `json:"channels"`
+	Components         AsyncAPIComponents         `json:"components"`
+}
+
+// AsyncAPIInfo describes the API
+type AsyncAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// AsyncAPIServer is a host the API is served from
+type AsyncAPIServer struct {
+	URL         string `json:"url"`
+	Protocol    string `json:"protocol"`
+	Description string `json:"description,omitempty"`
+}
+
+// AsyncAPIChannel is the WebSocket endpoint. In AsyncAPI 2.x, publish lists
+// the messages clients send and subscribe the messages they receive.
+type AsyncAPIChannel struct {
+	Description string             `json:"description,omitempty"`
+	Publish     *AsyncAPIOperation `json:"publish,omitempty"`
+	Subscribe   *AsyncAPIOperation `json:"subscribe,omitempty"`
+}
+
+// AsyncAPIOperation lists the messages of one direction
+type AsyncAPIOperation struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary,omitempty"`
+	Message     struct {
+		OneOf []AsyncAPIRef `json:"oneOf"`
+	} `json:"message"`
+}
+
+// AsyncAPIRef refers to a component
+type AsyncAPIRef struct {
+	Ref string `json:"$ref"`
+}
+
+// AsyncAPIMessage describes one event type
+type AsyncAPIMessage struct {
+	Name    string      `json:"name"`
+	Title   string      `json:"title"`
+	Summary string      `json:"summary,omitempty"`
+	Payload AsyncAPIRef `json:"payload"`
+}
+
+// AsyncAPIComponents holds the messages and the schemas of their payloads
+type AsyncAPIComponents struct {
+	Messages map[string]AsyncAPIMessage `json:"messages"`
+	Schemas  map[string]*Schema         `json:"schemas"`
+}
+
+// AsyncAPI generates the protocol document from the event registry. host is
+// the server's host:port, for the servers section; empty leaves it out.
+func AsyncAPI(host string) *AsyncAPIDocument {
+	builder := newSchemaBuilder("#/components/schemas/")
+
+	doc := &AsyncAPIDocument{
+		AsyncAPI: AsyncAPIVersion,
+		Info: AsyncAPIInfo{
+			Title:   "Real-time Chat & Comments WebSocket API",
+			Version: Version,
+			Description: "Every frame is a JSON event whose type field selects its schema; " +
+				"a server frame may carry several events separated by newlines. " +
+				"Client events that don't match their schema are answered with an ERROR " +
+				"with code INVALID_EVENT and the offending field.",
+		},
+		DefaultContentType: "application/json",
+		Components: AsyncAPIComponents{
+			Messages: make(map[string]AsyncAPIMessage),
+			Schemas:  builder.defs,
+		},
+	}
+	if host != "" {
+		doc.Servers = map[string]AsyncAPIServer{
+			"default": {URL: host, Protocol: "ws", Description: "Connect to /ws?username=NAME"},
+		}
+	}
+
+	publish := &AsyncAPIOperation{OperationID: "sendEvent", Summary: "Events clients send"}
+	subscribe := &AsyncAPIOperation{OperationID: "receiveEvent", Summary: "Events the server sends"}
+
+	for _, spec := range Events {
+		name := SchemaName(spec)
+		schema := builder.event(spec)
+		builder.defs[name] = schema
+
+		doc.Components.Messages[name] = AsyncAPIMessage{
+			Name:    spec.Type,
+			Title:   spec.Type,
+			Summary: schema.Description,
+			Payload: AsyncAPIRef{Ref: "#/components/schemas/" + name},
+		}
+
+		ref := AsyncAPIRef{Ref: "#/components/messages/" + name}
+		if spec.Direction == ClientToServer {
+			publish.Message.OneOf = append(publish.Message.OneOf, ref)
+		} else {
+			subscribe.Message.OneOf = append(subscribe.Message.OneOf, ref)
+		}
+	}
+
+	doc.Channels = map[string]AsyncAPIChannel{
+		"/ws": {
+			Description: "The WebSocket endpoint. The long-polling transport accepts and returns the same events.",
+			Publish:     publish,
+			Subscribe:   subscribe,
+		},
+	}
+	return doc
+}
@@ -0,0 +1,19 @@
+package protocol
+
+// ChatMessageEvent represents a chat message event
+type ChatMessageEvent struct {
+	Type          string        `json:"type"`                                            // "CHAT_MESSAGE"
+	Room          string        `json:"room" schema:"required,minLength=1,maxLength=50"` // Target room
+	User          string        `json:"user"`                                            // Sender username; set by the server from the connection
+	Message       string        `json:"message" schema:"maxLength=1000"`                 // Message content (Markdown)
+	ContentHTML   string        `json:"content_html,omitempty"`                          // Set by the server on broadcast
+	AttachmentIDs []string      `json:"attachment_ids,omitempty" schema:"maxItems=10"`   // Uploaded attachments to include
+	MessageID     string        `json:"message_id,omitempty"`                            // Set by the server on broadcast
+	Attachments   []*Attachment `json:"attachments,omitempty"`                           // Set by the server on broadcast
+}
+
+// GetType returns the event type
+func (e *ChatMessageEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *ChatMessageEvent) GetUser() string { return e.User }
@@ -0,0 +1,82 @@
+package protocol
+
+import "time"
+
+// PostCommentEvent represents a post comment event
+type PostCommentEvent struct {
+	Type          string        `json:"type"`                                                                         // "POST_COMMENT"
+	PostID        string        `json:"post_id" schema:"required,minLength=1,maxLength=100,pattern=^[a-zA-Z0-9_-]+$"` // Target post ID
+	User          string        `json:"user"`                                                                         // Commenter username; set by the server from the connection
+	Comment       string        `json:"comment" schema:"maxLength=2000"`                                              // Comment content (Markdown)
+	ContentHTML   string        `json:"content_html,omitempty"`                                                       // Set by the server on broadcast
+	ReplyTo       string        `json:"reply_to,omitempty" schema:"maxLength=100,pattern=^[a-zA-Z0-9_-]+$"`           // Parent comment ID for replies
+	AttachmentIDs []string      `json:"attachment_ids,omitempty" schema:"maxItems=10"`                                // Uploaded attachments to include
+	CommentID     string        `json:"comment_id,omitempty"`                                                         // Set by the server on broadcast
+	Attachments   []*Attachment `json:"attachments,omitempty"`                                                        // Set by the server on broadcast
+}
+
+// EditCommentEvent represents a request to edit an existing comment
+type EditCommentEvent struct {
+	Type      string `json:"type"`                                                 // "EDIT_COMMENT"
+	CommentID string `json:"comment_id" schema:"required,minLength=1"`             // Comment to edit
+	User      string `json:"user"`                                                 // Editor username
+	Comment   string `json:"comment" schema:"required,minLength=1,maxLength=2000"` // New comment content
+}
+
+// DeleteCommentEvent represents a request to delete an existing comment
+type DeleteCommentEvent struct {
+	Type      string `json:"type"`                                     // "DELETE_COMMENT"
+	CommentID string `json:"comment_id" schema:"required,minLength=1"` // Comment to delete
+	User      string `json:"user"`                                     // Username requesting deletion
+}
+
+// CommentUpdatedEvent is broadcast to post subscribers after an edit
+type CommentUpdatedEvent struct {
+	Type        string    `json:"type"`                // "COMMENT_UPDATED"
+	PostID      string    `json:"post_id"`             // Post the comment belongs to
+	CommentID   string    `json:"comment_id"`          // Edited comment ID
+	ParentID    string    `json:"parent_id,omitempty"` // Parent comment for replies
+	User        string    `json:"user"`                // Comment author
+	Comment     string    `json:"comment"`             // New comment content
+	ContentHTML string    `json:"content_html"`        // Sanitized rendering of the new content
+	UpdatedAt   time.Time `json:"updated_at"`          // Edit timestamp
+}
+
+// CommentDeletedEvent is broadcast to post subscribers after a deletion
+type CommentDeletedEvent struct {
+	Type      string `json:"type"`                // "COMMENT_DELETED"
+	PostID    string `json:"post_id"`             // Post the comment belonged to
+	CommentID string `json:"comment_id"`          // Deleted comment ID; its replies are removed too
+	ParentID  string `json:"parent_id,omitempty"` // Parent comment for replies
+	User      string `json:"user"`                // Username who deleted it
+}
+
+// GetType returns the event type
+func (e *PostCommentEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *PostCommentEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *EditCommentEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *EditCommentEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *DeleteCommentEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *DeleteCommentEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *CommentUpdatedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *CommentUpdatedEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *CommentDeletedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *CommentDeletedEvent) GetUser() string { return e.User }
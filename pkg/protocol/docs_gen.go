@@ -0,0 +1,314 @@
+// Code generated by gen_docs.go; DO NOT EDIT.
+
+package protocol
+
+// typeDocs holds the doc comments of wire structs, keyed by package.Type
+var typeDocs = map[string]string{
+	"protocol.AsyncAPIChannel":       "AsyncAPIChannel is the WebSocket endpoint. In AsyncAPI 2.x, publish lists the messages clients send and subscribe the messages they receive.",
+	"protocol.AsyncAPIComponents":    "AsyncAPIComponents holds the messages and the schemas of their payloads",
+	"protocol.AsyncAPIDocument":      "AsyncAPIDocument is an AsyncAPI 2.6 description of the WebSocket protocol",
+	"protocol.AsyncAPIInfo":          "AsyncAPIInfo describes the API",
+	"protocol.AsyncAPIMessage":       "AsyncAPIMessage describes one event type",
+	"protocol.AsyncAPIOperation":     "AsyncAPIOperation lists the messages of one direction",
+	"protocol.AsyncAPIRef":           "AsyncAPIRef refers to a component",
+	"protocol.AsyncAPIServer":        "AsyncAPIServer is a host the API is served from",
+	"protocol.Attachment":            "Attachment is an uploaded file. It is created unattached and linked to a single message or comment when its uploader references it.",
+	"protocol.ChatMessageEvent":      "ChatMessageEvent represents a chat message event",
+	"protocol.CommentCreatedEvent":   "CommentCreatedEvent tells feed subscribers that a post received a comment",
+	"protocol.CommentDeletedEvent":   "CommentDeletedEvent is broadcast to post subscribers after a deletion",
+	"protocol.CommentSnapshot":       "CommentSnapshot is the newest page of a post's comments, oldest first",
+	"protocol.CommentUpdatedEvent":   "CommentUpdatedEvent is broadcast to post subscribers after an edit",
+	"protocol.ContentHeldEvent":      "ContentHeldEvent tells the sender that their message or comment was quarantined for moderator review instead of being broadcast",
+	"protocol.DeleteCommentEvent":    "DeleteCommentEvent represents a request to delete an existing comment",
+	"protocol.EditCommentEvent":      "EditCommentEvent represents a request to edit an existing comment",
+	"protocol.ErrorEvent":            "ErrorEvent represents an error response to client",
+	"protocol.EventSpec":             "EventSpec registers an event type with the struct that carries it",
+	"protocol.FeedEvent":             "FeedEvent subscribes to or unsubscribes from the posts feed",
+	"protocol.FeedSubscriptionEvent": "FeedSubscriptionEvent confirms a posts feed subscription change",
+	"protocol.FetchHistoryEvent":     "FetchHistoryEvent requests a page of room messages or post comments. Pass a page's next_cursor as before to scroll back, or prev_cursor as after to catch up.",
+	"protocol.FieldError":            "FieldError reports the first field of a client event that doesn't match the event's schema. Field is a path like \"room\" or \"attachment_ids[2]\", or empty when the event as a whole is malformed.",
+	"protocol.HistoryPageEvent":      "HistoryPageEvent is the reply to FETCH_HISTORY, sent only to the requesting connection. Items are always oldest first.",
+	"protocol.JoinRoomEvent":         "JoinRoomEvent represents a room join event",
+	"protocol.MarkReadEvent":         "MarkReadEvent marks a room as read up to a message",
+	"protocol.Mention":               "Mention records that a user was @mentioned in a chat message or comment",
+	"protocol.MentionEvent":          "MentionEvent is pushed to every connection of a mentioned user",
+	"protocol.PostCommentEvent":      "PostCommentEvent represents a post comment event",
+	"protocol.PostEvent":             "PostEvent tells clients that a post was created, updated or deleted",
+	"protocol.PostSubscribedEvent":   "PostSubscribedEvent confirms a post subscription, sent only to the subscriber",
+	"protocol.PostSubscribersEvent":  "PostSubscribersEvent tells a post's subscribers and the posts feed how many users are reading the post",
+	"protocol.PostUnsubscribedEvent": "PostUnsubscribedEvent confirms a post unsubscription",
+	"protocol.Property":              "Property is a named object property",
+	"protocol.ReactionEvent":         "ReactionEvent represents a reaction add or remove request",
+	"protocol.ReactionSummary":       "ReactionSummary aggregates reactions of one emoji on a target",
+	"protocol.ReactionUpdatedEvent":  "ReactionUpdatedEvent is broadcast with the new aggregated counts for a target",
+	"protocol.ReadReceiptEvent":      "ReadReceiptEvent is broadcast to a room when a member's read position moves",
+	"protocol.RoomJoinedEvent":       "RoomJoinedEvent represents a room joined confirmation",
+	"protocol.SanctionEvent":         "SanctionEvent is sent by a moderator to mute, ban, unmute or unban a user",
+	"protocol.SanctionNoticeEvent":   "SanctionNoticeEvent is broadcast to the room (and sent to the target) after a moderation action",
+	"protocol.Schema":                "Schema is the subset of JSON Schema the protocol is described with",
+	"protocol.SearchEvent":           "SearchEvent requests a full-text search",
+	"protocol.SearchResult":          "SearchResult is a single ranked hit",
+	"protocol.SearchResultsEvent":    "SearchResultsEvent is the reply to SEARCH, sent only to the requesting connection",
+	"protocol.SubscribePostEvent":    "SubscribePostEvent asks for live updates on a post without having to comment on it",
+	"protocol.UnreadUpdateEvent":     "UnreadUpdateEvent tells a user their unread count for a room changed",
+	"protocol.UnsubscribePostEvent":  "UnsubscribePostEvent stops live updates on a post",
+}
+
+// fieldDocs holds the comments of wire struct fields, keyed by package.Type and JSON name
+var fieldDocs = map[string]map[string]string{
+	"protocol.Attachment": {
+		"height":      "Images only",
+		"target_type": "Empty until attached",
+		"width":       "Images only",
+	},
+	"protocol.ChatMessageEvent": {
+		"attachment_ids": "Uploaded attachments to include",
+		"attachments":    "Set by the server on broadcast",
+		"content_html":   "Set by the server on broadcast",
+		"message":        "Message content (Markdown)",
+		"message_id":     "Set by the server on broadcast",
+		"room":           "Target room",
+		"type":           "\"CHAT_MESSAGE\"",
+		"user":           "Sender username; set by the server from the connection",
+	},
+	"protocol.Comment": {
+		"content_html": "Sanitized Markdown rendering of Content",
+		"parent_id":    "Empty for top-level comments",
+	},
+	"protocol.CommentCreatedEvent": {
+		"comment": "The new comment, attachments included",
+		"post_id": "Post that was commented on",
+		"type":    "\"COMMENT_CREATED\"",
+		"user":    "Commenter username",
+	},
+	"protocol.CommentDeletedEvent": {
+		"comment_id": "Deleted comment ID; its replies are removed too",
+		"parent_id":  "Parent comment for replies",
+		"post_id":    "Post the comment belonged to",
+		"type":       "\"COMMENT_DELETED\"",
+		"user":       "Username who deleted it",
+	},
+	"protocol.CommentSnapshot": {
+		"comments":    "Most recent comments",
+		"has_more":    "Older comments exist",
+		"next_cursor": "Use as FETCH_HISTORY before for older comments; set only when has_more",
+	},
+	"protocol.CommentUpdatedEvent": {
+		"comment":      "New comment content",
+		"comment_id":   "Edited comment ID",
+		"content_html": "Sanitized rendering of the new content",
+		"parent_id":    "Parent comment for replies",
+		"post_id":      "Post the comment belongs to",
+		"type":         "\"COMMENT_UPDATED\"",
+		"updated_at":   "Edit timestamp",
+		"user":         "Comment author",
+	},
+	"protocol.ContentHeldEvent": {
+		"post_id":     "Set for comments",
+		"queue_id":    "Review queue item ID",
+		"reason":      "Why the content was held",
+		"room":        "Set for messages",
+		"target_type": "\"message\" or \"comment\"",
+		"type":        "\"CONTENT_HELD\"",
+	},
+	"protocol.DeleteCommentEvent": {
+		"comment_id": "Comment to delete",
+		"type":       "\"DELETE_COMMENT\"",
+		"user":       "Username requesting deletion",
+	},
+	"protocol.EditCommentEvent": {
+		"comment":    "New comment content",
+		"comment_id": "Comment to edit",
+		"type":       "\"EDIT_COMMENT\"",
+		"user":       "Editor username",
+	},
+	"protocol.ErrorEvent": {
+		"code":       "Optional error code",
+		"field":      "Offending field of an INVALID_EVENT, e.g. \"room\"",
+		"message":    "Error message",
+		"request_id": "request_id of the failed event, if it had one",
+		"type":       "\"ERROR\"",
+	},
+	"protocol.FeedEvent": {
+		"type": "\"SUBSCRIBE_FEED\" or \"UNSUBSCRIBE_FEED\"",
+		"user": "Requesting username",
+	},
+	"protocol.FeedSubscriptionEvent": {
+		"type": "\"FEED_SUBSCRIBED\" or \"FEED_UNSUBSCRIBED\"",
+	},
+	"protocol.FetchHistoryEvent": {
+		"after":      "Return items newer than this cursor",
+		"before":     "Return items older than this cursor",
+		"limit":      "Page size (default 50, max 100)",
+		"post_id":    "Or post whose comments to page through",
+		"request_id": "Echoed back on the HISTORY_PAGE",
+		"room":       "Chat room to page through",
+		"type":       "\"FETCH_HISTORY\"",
+		"user":       "Requesting username",
+	},
+	"protocol.HistoryPageEvent": {
+		"comments":    "Post comments",
+		"has_more":    "More items in the requested direction",
+		"messages":    "Room messages",
+		"next_cursor": "Oldest item; use as before. Only set while older items remain",
+		"post_id":     "Set for comment history",
+		"prev_cursor": "Newest item; use as after to catch up",
+		"request_id":  "Copied from the request",
+		"room":        "Set for room history",
+		"type":        "\"HISTORY_PAGE\"",
+	},
+	"protocol.JoinRoomEvent": {
+		"room": "Room name to join",
+		"type": "\"JOIN_ROOM\"",
+		"user": "Username",
+	},
+	"protocol.MarkReadEvent": {
+		"message_id": "Last seen message; newest if empty",
+		"room":       "Room being read",
+		"type":       "\"MARK_READ\"",
+		"user":       "Reader username",
+	},
+	"protocol.Mention": {
+		"author":      "Who wrote the mention",
+		"excerpt":     "Start of the mentioning text",
+		"post_id":     "Set for comments",
+		"room":        "Set for messages",
+		"source_id":   "Message or comment ID",
+		"source_type": "\"message\" or \"comment\"",
+		"username":    "Mentioned user",
+	},
+	"protocol.MentionEvent": {
+		"type": "\"MENTION\"",
+	},
+	"protocol.Message": {
+		"content_html": "Sanitized Markdown rendering of Content",
+		"type":         "\"message\", \"join\", \"leave\"",
+	},
+	"protocol.PostCommentEvent": {
+		"attachment_ids": "Uploaded attachments to include",
+		"attachments":    "Set by the server on broadcast",
+		"comment":        "Comment content (Markdown)",
+		"comment_id":     "Set by the server on broadcast",
+		"content_html":   "Set by the server on broadcast",
+		"post_id":        "Target post ID",
+		"reply_to":       "Parent comment ID for replies",
+		"type":           "\"POST_COMMENT\"",
+		"user":           "Commenter username; set by the server from the connection",
+	},
+	"protocol.PostEvent": {
+		"post":      "Current post; omitted for POST_DELETED",
+		"post_id":   "Affected post",
+		"timestamp": "When the change was committed",
+		"type":      "\"POST_CREATED\", \"POST_UPDATED\" or \"POST_DELETED\"",
+		"user":      "Username that made the change, when known",
+	},
+	"protocol.PostSubscribedEvent": {
+		"post_id":     "Post now followed",
+		"snapshot":    "Set when a snapshot was requested",
+		"subscribers": "Distinct users following the post",
+		"type":        "\"POST_SUBSCRIBED\"",
+	},
+	"protocol.PostSubscribersEvent": {
+		"post_id":     "Post being read",
+		"subscribers": "Distinct users subscribed to the post",
+		"type":        "\"POST_SUBSCRIBERS\"",
+	},
+	"protocol.PostUnsubscribedEvent": {
+		"post_id": "Post no longer followed",
+		"type":    "\"POST_UNSUBSCRIBED\"",
+	},
+	"protocol.ReactionEvent": {
+		"emoji":       "Emoji to add or remove",
+		"target_id":   "Message or comment ID",
+		"target_type": "\"message\" or \"comment\"",
+		"type":        "\"REACTION_ADD\" or \"REACTION_REMOVE\"",
+		"user":        "Reacting username",
+	},
+	"protocol.ReactionUpdatedEvent": {
+		"action":      "\"add\" or \"remove\"",
+		"emoji":       "Emoji that changed",
+		"post_id":     "Set for comment targets",
+		"reactions":   "Aggregated counts",
+		"room":        "Set for message targets",
+		"target_id":   "Message or comment ID",
+		"target_type": "\"message\" or \"comment\"",
+		"type":        "\"REACTION_UPDATED\"",
+		"user":        "User whose reaction changed",
+	},
+	"protocol.ReadReceiptEvent": {
+		"message_id": "Last read message",
+		"read_at":    "When the receipt was recorded",
+		"room":       "Room that was read",
+		"type":       "\"READ_RECEIPT\"",
+		"user":       "Reader username",
+	},
+	"protocol.RoomJoinedEvent": {
+		"room": "Room that was joined",
+		"type": "\"ROOM_JOINED\"",
+		"user": "Username who joined",
+	},
+	"protocol.SanctionEvent": {
+		"duration_seconds": "Required for mutes; 0 bans permanently",
+		"reason":           "Shown to the room",
+		"room":             "Room to moderate",
+		"target":           "User to act on",
+		"type":             "\"MUTE_USER\", \"UNMUTE_USER\", \"BAN_USER\" or \"UNBAN_USER\"",
+		"user":             "Moderator username",
+	},
+	"protocol.SanctionNoticeEvent": {
+		"by":         "Moderator who acted",
+		"expires_at": "When a ban or mute ends; absent for permanent bans",
+		"reason":     "Moderator's reason",
+		"room":       "Room the action applies to",
+		"type":       "\"USER_BANNED\", \"USER_UNBANNED\", \"USER_MUTED\" or \"USER_UNMUTED\"",
+		"user":       "User who was acted on",
+	},
+	"protocol.SearchEvent": {
+		"author":     "Restrict hits to an author",
+		"from":       "YYYY-MM-DD or RFC3339 lower bound",
+		"limit":      "Page size (default 20, max 50)",
+		"offset":     "Results to skip (max 1000)",
+		"query":      "Words to search for (all must match)",
+		"request_id": "Echoed back on SEARCH_RESULTS",
+		"room":       "Restrict message hits to a room; not allowed with scope posts or comments",
+		"scope":      "\"all\" (default), \"messages\", \"posts\" or \"comments\"",
+		"to":         "YYYY-MM-DD or RFC3339 upper bound",
+		"type":       "\"SEARCH\"",
+		"user":       "Searching username",
+	},
+	"protocol.SearchResult": {
+		"author":  "Username or author name",
+		"id":      "ID of the matching row",
+		"post_id": "Set for posts and comments",
+		"rank":    "bm25 score; lower is more relevant",
+		"room_id": "Set for messages",
+		"snippet": "HTML-escaped excerpt with matches wrapped in <mark>",
+		"title":   "Set for posts",
+		"type":    "\"message\", \"post\" or \"comment\"",
+	},
+	"protocol.SearchResultsEvent": {
+		"offset":     "Offset that was applied",
+		"query":      "Normalized query text",
+		"request_id": "Copied from the request",
+		"results":    "Hits, best first",
+		"scope":      "Scope that was searched",
+		"type":       "\"SEARCH_RESULTS\"",
+	},
+	"protocol.SubscribePostEvent": {
+		"post_id":        "Post to follow",
+		"snapshot":       "Also send the most recent comments",
+		"snapshot_limit": "Snapshot size (default 20, max 100)",
+		"type":           "\"SUBSCRIBE_POST\"",
+		"user":           "Requesting username",
+	},
+	"protocol.UnreadUpdateEvent": {
+		"room":   "Room whose count changed",
+		"type":   "\"UNREAD_UPDATE\"",
+		"unread": "Current unread count",
+	},
+	"protocol.UnsubscribePostEvent": {
+		"post_id": "Post to stop following",
+		"type":    "\"UNSUBSCRIBE_POST\"",
+		"user":    "Requesting username",
+	},
+}
@@ -0,0 +1,26 @@
+package protocol
+
+// Error codes sent to clients alongside ERROR events
+const (
+	ErrCodeRateLimited     = "RATE_LIMITED"
+	ErrCodeContentRejected = "CONTENT_REJECTED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeBanned          = "BANNED"
+	ErrCodeMuted           = "MUTED"
+	ErrCodeInvalidEvent    = "INVALID_EVENT"
+)
+
+// ErrorEvent represents an error response to client
+type ErrorEvent struct {
+	Type      string `json:"type"`                 // "ERROR"
+	Message   string `json:"message"`              // Error message
+	Code      string `json:"code,omitempty"`       // Optional error code
+	RequestID string `json:"request_id,omitempty"` // request_id of the failed event, if it had one
+	Field     string `json:"field,omitempty"`      // Offending field of an INVALID_EVENT, e.g. "room"
+}
+
+// GetType returns the event type
+func (e *ErrorEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for error events
+func (e *ErrorEvent) GetUser() string { return "" }
@@ -0,0 +1,46 @@
+package protocol
+
+import "time"
+
+// PostEvent tells clients that a post was created, updated or deleted
+type PostEvent struct {
+	Type      string    `json:"type"`           // "POST_CREATED", "POST_UPDATED" or "POST_DELETED"
+	PostID    string    `json:"post_id"`        // Affected post
+	Post      *Post     `json:"post,omitempty"` // Current post; omitted for POST_DELETED
+	User      string    `json:"user,omitempty"` // Username that made the change, when known
+	Timestamp time.Time `json:"timestamp"`      // When the change was committed
+}
+
+// CommentCreatedEvent tells feed subscribers that a post received a comment
+type CommentCreatedEvent struct {
+	Type    string   `json:"type"`    // "COMMENT_CREATED"
+	PostID  string   `json:"post_id"` // Post that was commented on
+	Comment *Comment `json:"comment"` // The new comment, attachments included
+	User    string   `json:"user"`    // Commenter username
+}
+
+// PostSubscribersEvent tells a post's subscribers and the posts feed how many
+// users are reading the post
+type PostSubscribersEvent struct {
+	Type        string `json:"type"`        // "POST_SUBSCRIBERS"
+	PostID      string `json:"post_id"`     // Post being read
+	Subscribers int    `json:"subscribers"` // Distinct users subscribed to the post
+}
+
+// GetType returns the event type
+func (e *PostEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *PostEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *CommentCreatedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *CommentCreatedEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *PostSubscribersEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for subscriber counts
+func (e *PostSubscribersEvent) GetUser() string { return "" }
@@ -0,0 +1,140 @@
+//go:build ignore
+
+// gen_docs.go extracts the doc comments of the wire structs and their field
+// comments into docs_gen.go, so the generated schemas describe every field
+// without repeating the comments in struct tags. Run it with go generate after
+// changing an event struct or a record it carries.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	typeDocs := make(map[string]string)
+	fieldDocs := make(map[string]map[string]string)
+
+	// Every struct on the wire, events and the records they carry, is declared here
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || strings.HasSuffix(path, "_gen.go") || path == "gen_docs.go" {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		collect(file, typeDocs, fieldDocs)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gen_docs.go; DO NOT EDIT.\n\npackage protocol\n\n")
+	out.WriteString("// typeDocs holds the doc comments of wire structs, keyed by package.Type\n")
+	out.WriteString("var typeDocs = map[string]string{\n")
+	for _, name := range sortedKeys(typeDocs) {
+		fmt.Fprintf(&out, "%q: %q,\n", name, typeDocs[name])
+	}
+	out.WriteString("}\n\n")
+	out.WriteString("// fieldDocs holds the comments of wire struct fields, keyed by package.Type and JSON name\n")
+	out.WriteString("var fieldDocs = map[string]map[string]string{\n")
+	for _, name := range sortedKeys(fieldDocs) {
+		fmt.Fprintf(&out, "%q: {\n", name)
+		for _, field := range sortedKeys(fieldDocs[name]) {
+			fmt.Fprintf(&out, "%q: %q,\n", field, fieldDocs[name][field])
+		}
+		out.WriteString("},\n")
+	}
+	out.WriteString("}\n")
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("docs_gen.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collect records the docs of every exported struct in file
+func collect(file *ast.File, typeDocs map[string]string, fieldDocs map[string]map[string]string) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gen.Specs {
+			spec := s.(*ast.TypeSpec)
+			structType, ok := spec.Type.(*ast.StructType)
+			if !ok || !spec.Name.IsExported() {
+				continue
+			}
+			name := "protocol." + spec.Name.Name
+
+			doc := spec.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+			if text := clean(doc); text != "" {
+				typeDocs[name] = text
+			}
+
+			for _, field := range structType.Fields.List {
+				if len(field.Names) == 0 || field.Tag == nil {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				jsonName, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+				if jsonName == "" || jsonName == "-" {
+					continue
+				}
+				text := clean(field.Comment)
+				if text == "" {
+					text = clean(field.Doc)
+				}
+				if text == "" {
+					continue
+				}
+				if fieldDocs[name] == nil {
+					fieldDocs[name] = make(map[string]string)
+				}
+				fieldDocs[name][jsonName] = text
+			}
+		}
+	}
+}
+
+// clean joins a comment's lines into one
+func clean(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
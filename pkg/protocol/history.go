@@ -0,0 +1,40 @@
+package protocol
+
+// FetchHistoryEvent requests a page of room messages or post comments.
+// Pass a page's next_cursor as before to scroll back, or prev_cursor as after to catch up.
+type FetchHistoryEvent struct {
+	Type      string `json:"type"`                                     // "FETCH_HISTORY"
+	Room      string `json:"room,omitempty" schema:"maxLength=50"`     // Chat room to page through
+	PostID    string `json:"post_id,omitempty" schema:"maxLength=100"` // Or post whose comments to page through
+	Before    string `json:"before,omitempty"`                         // Return items older than this cursor
+	After     string `json:"after,omitempty"`                          // Return items newer than this cursor
+	Limit     int    `json:"limit,omitempty" schema:"minimum=0"`       // Page size (default 50, max 100)
+	RequestID string `json:"request_id,omitempty"`                     // Echoed back on the HISTORY_PAGE
+	User      string `json:"user"`                                     // Requesting username
+}
+
+// HistoryPageEvent is the reply to FETCH_HISTORY, sent only to the requesting connection.
+// Items are always oldest first.
+type HistoryPageEvent struct {
+	Type       string     `json:"type"`                  // "HISTORY_PAGE"
+	Room       string     `json:"room,omitempty"`        // Set for room history
+	PostID     string     `json:"post_id,omitempty"`     // Set for comment history
+	Messages   []*Message `json:"messages,omitempty"`    // Room messages
+	Comments   []*Comment `json:"comments,omitempty"`    // Post comments
+	NextCursor string     `json:"next_cursor,omitempty"` // Oldest item; use as before. Only set while older items remain
+	PrevCursor string     `json:"prev_cursor,omitempty"` // Newest item; use as after to catch up
+	HasMore    bool       `json:"has_more"`              // More items in the requested direction
+	RequestID  string     `json:"request_id,omitempty"`  // Copied from the request
+}
+
+// GetType returns the event type
+func (e *FetchHistoryEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *FetchHistoryEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *HistoryPageEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for history pages
+func (e *HistoryPageEvent) GetUser() string { return "" }
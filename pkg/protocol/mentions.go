@@ -0,0 +1,13 @@
+package protocol
+
+// MentionEvent is pushed to every connection of a mentioned user
+type MentionEvent struct {
+	Type string `json:"type"` // "MENTION"
+	Mention
+}
+
+// GetType returns the event type
+func (e *MentionEvent) GetType() string { return e.Type }
+
+// GetUser returns the author of the mention
+func (e *MentionEvent) GetUser() string { return e.Author }
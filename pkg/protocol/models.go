@@ -0,0 +1,108 @@
+package protocol
+
+import "time"
+
+// The records below are stored by the server and carried inside events. They
+// are defined here so clients can decode them without importing the server's
+// internal packages; internal/models aliases them.
+
+// AttachmentURLPrefix is where attachments are downloaded from
+const AttachmentURLPrefix = "/api/v1/attachments/"
+
+type Message struct {
+	ID          string            `json:"id" db:"id"`
+	Username    string            `json:"username" db:"username"`
+	Content     string            `json:"content" db:"content"`
+	ContentHTML string            `json:"content_html" db:"content_html"` // Sanitized Markdown rendering of Content
+	RoomID      string            `json:"room_id" db:"room_id"`
+	Type        string            `json:"type" db:"type"` // "message", "join", "leave"
+	Timestamp   time.Time         `json:"timestamp" db:"timestamp"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	Reactions   []ReactionSummary `json:"reactions,omitempty" db:"-"`
+	Attachments []*Attachment     `json:"attachments,omitempty" db:"-"`
+}
+
+type Post struct {
+	ID           string    `json:"id" db:"id"`
+	Title        string    `json:"title" db:"title"`
+	Content      string    `json:"content" db:"content"`
+	AuthorID     string    `json:"author_id" db:"author_id"`
+	AuthorName   string    `json:"author_name" db:"author_name"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	CommentCount int       `json:"comment_count" db:"comment_count"`
+}
+
+type Comment struct {
+	ID          string            `json:"id" db:"id"`
+	PostID      string            `json:"post_id" db:"post_id"`
+	ParentID    string            `json:"parent_id,omitempty" db:"parent_id"` // Empty for top-level comments
+	Content     string            `json:"content" db:"content"`
+	ContentHTML string            `json:"content_html" db:"content_html"` // Sanitized Markdown rendering of Content
+	AuthorID    string            `json:"author_id" db:"author_id"`
+	AuthorName  string            `json:"author_name" db:"author_name"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+	Reactions   []ReactionSummary `json:"reactions,omitempty" db:"-"`
+	Attachments []*Attachment     `json:"attachments,omitempty" db:"-"`
+}
+
+// Attachment is an uploaded file. It is created unattached and linked to a single
+// message or comment when its uploader references it.
+type Attachment struct {
+	ID           string    `json:"id" db:"id"`
+	Filename     string    `json:"filename" db:"filename"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	Size         int64     `json:"size" db:"size"`
+	Width        int       `json:"width,omitempty" db:"width"`   // Images only
+	Height       int       `json:"height,omitempty" db:"height"` // Images only
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	UploadedBy   string    `json:"uploaded_by" db:"uploaded_by"`
+	TargetType   string    `json:"target_type,omitempty" db:"target_type"` // Empty until attached
+	TargetID     string    `json:"target_id,omitempty" db:"target_id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	URL          string    `json:"url" db:"-"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// FillURLs sets the download URLs from the attachment ID
+func (a *Attachment) FillURLs() {
+	a.URL = AttachmentURLPrefix + a.ID
+	a.ThumbnailURL = ""
+	if a.ThumbnailKey != "" {
+		a.ThumbnailURL = AttachmentURLPrefix + a.ID + "/thumbnail"
+	}
+}
+
+// Mention records that a user was @mentioned in a chat message or comment
+type Mention struct {
+	Username   string    `json:"username" db:"username"`         // Mentioned user
+	SourceType string    `json:"source_type" db:"source_type"`   // "message" or "comment"
+	SourceID   string    `json:"source_id" db:"source_id"`       // Message or comment ID
+	RoomID     string    `json:"room,omitempty" db:"room_id"`    // Set for messages
+	PostID     string    `json:"post_id,omitempty" db:"post_id"` // Set for comments
+	Author     string    `json:"author" db:"author"`             // Who wrote the mention
+	Excerpt    string    `json:"excerpt" db:"excerpt"`           // Start of the mentioning text
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReactionSummary aggregates reactions of one emoji on a target
+type ReactionSummary struct {
+	Emoji string   `json:"emoji"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// SearchResult is a single ranked hit
+type SearchResult struct {
+	Type      string    `json:"type"`              // "message", "post" or "comment"
+	ID        string    `json:"id"`                // ID of the matching row
+	Snippet   string    `json:"snippet"`           // HTML-escaped excerpt with matches wrapped in <mark>
+	Rank      float64   `json:"rank"`              // bm25 score; lower is more relevant
+	Author    string    `json:"author"`            // Username or author name
+	RoomID    string    `json:"room_id,omitempty"` // Set for messages
+	PostID    string    `json:"post_id,omitempty"` // Set for posts and comments
+	Title     string    `json:"title,omitempty"`   // Set for posts
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package protocol
+
+// ContentHeldEvent tells the sender that their message or comment was quarantined
+// for moderator review instead of being broadcast
+type ContentHeldEvent struct {
+	Type       string `json:"type"`              // "CONTENT_HELD"
+	QueueID    string `json:"queue_id"`          // Review queue item ID
+	TargetType string `json:"target_type"`       // "message" or "comment"
+	Room       string `json:"room,omitempty"`    // Set for messages
+	PostID     string `json:"post_id,omitempty"` // Set for comments
+	Reason     string `json:"reason"`            // Why the content was held
+}
+
+// GetType returns the event type
+func (e *ContentHeldEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for moderation notices
+func (e *ContentHeldEvent) GetUser() string { return "" }
@@ -0,0 +1,77 @@
+// Package protocol defines the WebSocket wire format: the event type names and
+// the JSON structs for every event a client sends or receives. The server's
+// handlers and the Go client SDK (pkg/client) both use these types, so the two
+// can't drift apart.
+package protocol
+
+// Event types, as carried in every event's "type" field
+const (
+	EventJoinRoom    = "JOIN_ROOM"
+	EventChatMessage = "CHAT_MESSAGE"
+	EventPostComment = "POST_COMMENT"
+	EventRoomJoined  = "ROOM_JOINED"
+	EventError       = "ERROR"
+
+	// Comment lifecycle events
+	EventEditComment    = "EDIT_COMMENT"
+	EventDeleteComment  = "DELETE_COMMENT"
+	EventCommentUpdated = "COMMENT_UPDATED"
+	EventCommentDeleted = "COMMENT_DELETED"
+
+	// Post feed events, fanned out from the domain bus
+	EventPostCreated    = "POST_CREATED"
+	EventPostUpdated    = "POST_UPDATED"
+	EventPostDeleted    = "POST_DELETED"
+	EventCommentCreated = "COMMENT_CREATED"
+
+	// Subscription events
+	EventSubscribePost    = "SUBSCRIBE_POST"
+	EventUnsubscribePost  = "UNSUBSCRIBE_POST"
+	EventSubscribeFeed    = "SUBSCRIBE_FEED"
+	EventUnsubscribeFeed  = "UNSUBSCRIBE_FEED"
+	EventPostSubscribed   = "POST_SUBSCRIBED"
+	EventPostUnsubscribed = "POST_UNSUBSCRIBED"
+	EventFeedSubscribed   = "FEED_SUBSCRIBED"
+	EventFeedUnsubscribed = "FEED_UNSUBSCRIBED"
+	EventPostSubscribers  = "POST_SUBSCRIBERS"
+
+	// Reaction events
+	EventReactionAdd     = "REACTION_ADD"
+	EventReactionRemove  = "REACTION_REMOVE"
+	EventReactionUpdated = "REACTION_UPDATED"
+
+	// Read receipt events
+	EventMarkRead     = "MARK_READ"
+	EventReadReceipt  = "READ_RECEIPT"
+	EventUnreadUpdate = "UNREAD_UPDATE"
+
+	// History events
+	EventFetchHistory = "FETCH_HISTORY"
+	EventHistoryPage  = "HISTORY_PAGE"
+
+	// Mention events
+	EventMention = "MENTION"
+
+	// Search events
+	EventSearch        = "SEARCH"
+	EventSearchResults = "SEARCH_RESULTS"
+
+	// Moderation events
+	EventContentHeld = "CONTENT_HELD"
+
+	// Room sanction events
+	EventMuteUser     = "MUTE_USER"
+	EventUnmuteUser   = "UNMUTE_USER"
+	EventBanUser      = "BAN_USER"
+	EventUnbanUser    = "UNBAN_USER"
+	EventUserMuted    = "USER_MUTED"
+	EventUserUnmuted  = "USER_UNMUTED"
+	EventUserBanned   = "USER_BANNED"
+	EventUserUnbanned = "USER_UNBANNED"
+)
+
+// Event is implemented by every event struct in this package
+type Event interface {
+	GetType() string
+	GetUser() string
+}
@@ -0,0 +1,35 @@
+package protocol
+
+// ReactionEvent represents a reaction add or remove request
+type ReactionEvent struct {
+	Type       string `json:"type"`                                                  // "REACTION_ADD" or "REACTION_REMOVE"
+	TargetType string `json:"target_type" schema:"required,enum=message|comment"`    // "message" or "comment"
+	TargetID   string `json:"target_id" schema:"required,minLength=1,maxLength=100"` // Message or comment ID
+	Emoji      string `json:"emoji" schema:"required,minLength=1,maxLength=8"`       // Emoji to add or remove
+	User       string `json:"user"`                                                  // Reacting username
+}
+
+// ReactionUpdatedEvent is broadcast with the new aggregated counts for a target
+type ReactionUpdatedEvent struct {
+	Type       string            `json:"type"`              // "REACTION_UPDATED"
+	TargetType string            `json:"target_type"`       // "message" or "comment"
+	TargetID   string            `json:"target_id"`         // Message or comment ID
+	Room       string            `json:"room,omitempty"`    // Set for message targets
+	PostID     string            `json:"post_id,omitempty"` // Set for comment targets
+	User       string            `json:"user"`              // User whose reaction changed
+	Emoji      string            `json:"emoji"`             // Emoji that changed
+	Action     string            `json:"action"`            // "add" or "remove"
+	Reactions  []ReactionSummary `json:"reactions"`         // Aggregated counts
+}
+
+// GetType returns the event type
+func (e *ReactionEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *ReactionEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *ReactionUpdatedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *ReactionUpdatedEvent) GetUser() string { return e.User }
@@ -0,0 +1,45 @@
+package protocol
+
+import "time"
+
+// MarkReadEvent marks a room as read up to a message
+type MarkReadEvent struct {
+	Type      string `json:"type"`                                            // "MARK_READ"
+	Room      string `json:"room" schema:"required,minLength=1,maxLength=50"` // Room being read
+	MessageID string `json:"message_id,omitempty" schema:"maxLength=100"`     // Last seen message; newest if empty
+	User      string `json:"user"`                                            // Reader username
+}
+
+// ReadReceiptEvent is broadcast to a room when a member's read position moves
+type ReadReceiptEvent struct {
+	Type      string    `json:"type"`       // "READ_RECEIPT"
+	Room      string    `json:"room"`       // Room that was read
+	User      string    `json:"user"`       // Reader username
+	MessageID string    `json:"message_id"` // Last read message
+	ReadAt    time.Time `json:"read_at"`    // When the receipt was recorded
+}
+
+// UnreadUpdateEvent tells a user their unread count for a room changed
+type UnreadUpdateEvent struct {
+	Type   string `json:"type"`   // "UNREAD_UPDATE"
+	Room   string `json:"room"`   // Room whose count changed
+	Unread int    `json:"unread"` // Current unread count
+}
+
+// GetType returns the event type
+func (e *MarkReadEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *MarkReadEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *ReadReceiptEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *ReadReceiptEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *UnreadUpdateEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for unread updates
+func (e *UnreadUpdateEvent) GetUser() string { return "" }
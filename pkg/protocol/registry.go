@@ -0,0 +1,88 @@
+package protocol
+
+//go:generate go run gen_docs.go
+
+// Direction says which side of the connection sends an event
+type Direction string
+
+// Event directions
+const (
+	ClientToServer Direction = "client" // Sent by clients
+	ServerToClient Direction = "server" // Sent by the server
+)
+
+// EventSpec registers an event type with the struct that carries it
+type EventSpec struct {
+	Type      string
+	Direction Direction
+	Payload   interface{} // Zero value of the event struct
+}
+
+// Events lists every event type on the WebSocket, in the order they are
+// documented. The AsyncAPI document, the per-event JSON Schemas and the
+// validation of inbound events are all generated from it, so a new event type
+// only needs its struct and an entry here.
+//
+// Field constraints for client events come from schema struct tags, e.g.
+// `schema:"required,maxLength=50"`; the keys are required, minLength,
+// maxLength, pattern, enum (values separated by |), minimum, maximum and
+// maxItems. Descriptions come from the field comments (see gen_docs.go).
+var Events = []EventSpec{
+	// Client to server
+	{EventJoinRoom, ClientToServer, JoinRoomEvent{}},
+	{EventChatMessage, ClientToServer, ChatMessageEvent{}},
+	{EventPostComment, ClientToServer, PostCommentEvent{}},
+	{EventEditComment, ClientToServer, EditCommentEvent{}},
+	{EventDeleteComment, ClientToServer, DeleteCommentEvent{}},
+	{EventReactionAdd, ClientToServer, ReactionEvent{}},
+	{EventReactionRemove, ClientToServer, ReactionEvent{}},
+	{EventMarkRead, ClientToServer, MarkReadEvent{}},
+	{EventFetchHistory, ClientToServer, FetchHistoryEvent{}},
+	{EventSearch, ClientToServer, SearchEvent{}},
+	{EventSubscribePost, ClientToServer, SubscribePostEvent{}},
+	{EventUnsubscribePost, ClientToServer, UnsubscribePostEvent{}},
+	{EventSubscribeFeed, ClientToServer, FeedEvent{}},
+	{EventUnsubscribeFeed, ClientToServer, FeedEvent{}},
+	{EventMuteUser, ClientToServer, SanctionEvent{}},
+	{EventUnmuteUser, ClientToServer, SanctionEvent{}},
+	{EventBanUser, ClientToServer, SanctionEvent{}},
+	{EventUnbanUser, ClientToServer, SanctionEvent{}},
+
+	// Server to client
+	{EventRoomJoined, ServerToClient, RoomJoinedEvent{}},
+	{EventChatMessage, ServerToClient, ChatMessageEvent{}},
+	{EventPostComment, ServerToClient, PostCommentEvent{}},
+	{EventCommentUpdated, ServerToClient, CommentUpdatedEvent{}},
+	{EventCommentDeleted, ServerToClient, CommentDeletedEvent{}},
+	{EventPostCreated, ServerToClient, PostEvent{}},
+	{EventPostUpdated, ServerToClient, PostEvent{}},
+	{EventPostDeleted, ServerToClient, PostEvent{}},
+	{EventCommentCreated, ServerToClient, CommentCreatedEvent{}},
+	{EventPostSubscribed, ServerToClient, PostSubscribedEvent{}},
+	{EventPostUnsubscribed, ServerToClient, PostUnsubscribedEvent{}},
+	{EventFeedSubscribed, ServerToClient, FeedSubscriptionEvent{}},
+	{EventFeedUnsubscribed, ServerToClient, FeedSubscriptionEvent{}},
+	{EventPostSubscribers, ServerToClient, PostSubscribersEvent{}},
+	{EventReactionUpdated, ServerToClient, ReactionUpdatedEvent{}},
+	{EventReadReceipt, ServerToClient, ReadReceiptEvent{}},
+	{EventUnreadUpdate, ServerToClient, UnreadUpdateEvent{}},
+	{EventHistoryPage, ServerToClient, HistoryPageEvent{}},
+	{EventSearchResults, ServerToClient, SearchResultsEvent{}},
+	{EventMention, ServerToClient, MentionEvent{}},
+	{EventContentHeld, ServerToClient, ContentHeldEvent{}},
+	{EventUserMuted, ServerToClient, SanctionNoticeEvent{}},
+	{EventUserUnmuted, ServerToClient, SanctionNoticeEvent{}},
+	{EventUserBanned, ServerToClient, SanctionNoticeEvent{}},
+	{EventUserUnbanned, ServerToClient, SanctionNoticeEvent{}},
+	{EventError, ServerToClient, ErrorEvent{}},
+}
+
+// Lookup returns the registered spec for an event type sent in direction
+func Lookup(eventType string, direction Direction) (EventSpec, bool) {
+	for _, spec := range Events {
+		if spec.Type == eventType && spec.Direction == direction {
+			return spec, true
+		}
+	}
+	return EventSpec{}, false
+}
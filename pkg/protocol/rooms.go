@@ -0,0 +1,27 @@
+package protocol
+
+// JoinRoomEvent represents a room join event
+type JoinRoomEvent struct {
+	Type string `json:"type"`                                                                     // "JOIN_ROOM"
+	Room string `json:"room" schema:"required,minLength=2,maxLength=30,pattern=^[a-zA-Z0-9_-]+$"` // Room name to join
+	User string `json:"user"`                                                                     // Username
+}
+
+// RoomJoinedEvent represents a room joined confirmation
+type RoomJoinedEvent struct {
+	Type string `json:"type"` // "ROOM_JOINED"
+	Room string `json:"room"` // Room that was joined
+	User string `json:"user"` // Username who joined
+}
+
+// GetType returns the event type
+func (e *JoinRoomEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *JoinRoomEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *RoomJoinedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *RoomJoinedEvent) GetUser() string { return e.User }
@@ -0,0 +1,35 @@
+package protocol
+
+import "time"
+
+// SanctionEvent is sent by a moderator to mute, ban, unmute or unban a user
+type SanctionEvent struct {
+	Type            string `json:"type"`                                              // "MUTE_USER", "UNMUTE_USER", "BAN_USER" or "UNBAN_USER"
+	Room            string `json:"room" schema:"required,minLength=1,maxLength=50"`   // Room to moderate
+	Target          string `json:"target" schema:"required,minLength=1,maxLength=50"` // User to act on
+	DurationSeconds int    `json:"duration_seconds,omitempty" schema:"minimum=0"`     // Required for mutes; 0 bans permanently
+	Reason          string `json:"reason,omitempty" schema:"maxLength=200"`           // Shown to the room
+	User            string `json:"user"`                                              // Moderator username
+}
+
+// SanctionNoticeEvent is broadcast to the room (and sent to the target) after a moderation action
+type SanctionNoticeEvent struct {
+	Type      string     `json:"type"`                 // "USER_BANNED", "USER_UNBANNED", "USER_MUTED" or "USER_UNMUTED"
+	Room      string     `json:"room"`                 // Room the action applies to
+	User      string     `json:"user"`                 // User who was acted on
+	By        string     `json:"by"`                   // Moderator who acted
+	Reason    string     `json:"reason,omitempty"`     // Moderator's reason
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // When a ban or mute ends; absent for permanent bans
+}
+
+// GetType returns the event type
+func (e *SanctionEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *SanctionEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *SanctionNoticeEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *SanctionNoticeEvent) GetUser() string { return e.User }
@@ -0,0 +1,316 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the $schema of the standalone per-event schemas
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// requestIDDoc describes the request_id every client event may carry
+const requestIDDoc = "Optional client-chosen ID, echoed on the reply or on the ERROR the event fails with"
+
+// Schema is the subset of JSON Schema the protocol is described with
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Const       string             `json:"const,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Minimum     *int64             `json:"minimum,omitempty"`
+	Maximum     *int64             `json:"maximum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	Properties  Properties         `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+
+	pattern *regexp.Regexp // Compiled Pattern, for validation
+}
+
+// Property is a named object property
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps object properties in struct field order, so documents read
+// like the Go structs and validation reports errors in a stable order
+type Properties []Property
+
+// MarshalJSON writes the properties as a JSON object
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// property returns the named property's schema, or nil
+func (s *Schema) property(name string) *Schema {
+	for _, property := range s.Properties {
+		if property.Name == name {
+			return property.Schema
+		}
+	}
+	return nil
+}
+
+func (s *Schema) isRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EventSchema returns a standalone JSON Schema for one registered event, with
+// the models it carries under $defs
+func EventSchema(spec EventSpec) *Schema {
+	builder := newSchemaBuilder("#/$defs/")
+	schema := builder.event(spec)
+	schema.Schema = JSONSchemaDialect
+	if len(builder.defs) > 0 {
+		schema.Defs = builder.defs
+	}
+	return schema
+}
+
+// SchemaName names an event's schema by direction and type, e.g.
+// "client.CHAT_MESSAGE", since some types are sent both ways with different fields
+func SchemaName(spec EventSpec) string {
+	return string(spec.Direction) + "." + spec.Type
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaBuilder generates schemas from event structs by reflection. Nested
+// structs become shared definitions referenced through refPrefix.
+type schemaBuilder struct {
+	refPrefix string
+	defs      map[string]*Schema
+}
+
+func newSchemaBuilder(refPrefix string) *schemaBuilder {
+	return &schemaBuilder{refPrefix: refPrefix, defs: make(map[string]*Schema)}
+}
+
+// event builds the schema of a registered event: its struct's fields, with
+// type pinned to the event type and, for client events, the schema tag
+// constraints and the request_id every client event may carry
+func (b *schemaBuilder) event(spec EventSpec) *Schema {
+	t := reflect.TypeOf(spec.Payload)
+	inbound := spec.Direction == ClientToServer
+
+	schema := b.object(t, inbound)
+	schema.Title = spec.Type
+
+	typeField := schema.property("type")
+	if typeField == nil {
+		panic(fmt.Sprintf("protocol: %s has no type field", t.Name()))
+	}
+	typeField.Const = spec.Type
+	typeField.Description = "Event type"
+	if !schema.isRequired("type") {
+		schema.Required = append([]string{"type"}, schema.Required...)
+	}
+
+	if inbound && schema.property("request_id") == nil {
+		schema.Properties = append(schema.Properties, Property{
+			Name:   "request_id",
+			Schema: &Schema{Type: "string", Description: requestIDDoc},
+		})
+	}
+	return schema
+}
+
+// object builds an object schema from a struct. Fields without omitempty are
+// always sent by the server, so they are required in server events; in client
+// events only fields tagged schema:"required" are.
+func (b *schemaBuilder) object(t reflect.Type, inbound bool) *Schema {
+	schema := &Schema{Type: "object", Description: typeDocs[typeKey(t)]}
+	b.fields(t, schema, inbound)
+	return schema
+}
+
+func (b *schemaBuilder) fields(t reflect.Type, schema *Schema, inbound bool) {
+	docs := fieldDocs[typeKey(t)]
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		// Embedded structs are flattened, as encoding/json does
+		if field.Anonymous && name == "" {
+			if embedded := deref(field.Type); embedded.Kind() == reflect.Struct {
+				b.fields(embedded, schema, inbound)
+				continue
+			}
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.value(field.Type)
+		if property.Ref == "" {
+			property.Description = docs[name]
+		}
+
+		required := !inbound && !hasOption(options, "omitempty")
+		if inbound {
+			var err error
+			if required, err = applyTag(property, field.Tag.Get("schema")); err != nil {
+				panic(fmt.Sprintf("protocol: %s.%s: %v", t.Name(), field.Name, err))
+			}
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties = append(schema.Properties, Property{Name: name, Schema: property})
+	}
+}
+
+// value builds the schema of a field's Go type
+func (b *schemaBuilder) value(t reflect.Type) *Schema {
+	t = deref(t)
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.value(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		return b.ref(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// ref defines a nested struct once and refers to it by name
+func (b *schemaBuilder) ref(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, defined := b.defs[name]; !defined {
+		b.defs[name] = nil // Reserve the name so recursive types terminate
+		b.defs[name] = b.object(t, false)
+	}
+	return &Schema{Ref: b.refPrefix + name}
+}
+
+// applyTag applies a schema struct tag's constraints and reports whether it
+// marks the field required
+func applyTag(schema *Schema, tag string) (bool, error) {
+	required := false
+	if tag == "" {
+		return required, nil
+	}
+
+	for _, item := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(item, "=")
+		switch key {
+		case "required":
+			required = true
+		case "pattern":
+			compiled, err := regexp.Compile(value)
+			if err != nil {
+				return false, err
+			}
+			schema.Pattern, schema.pattern = value, compiled
+		case "enum":
+			schema.Enum = strings.Split(value, "|")
+		case "minLength", "maxLength", "maxItems":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			switch key {
+			case "minLength":
+				schema.MinLength = &n
+			case "maxLength":
+				schema.MaxLength = &n
+			default:
+				schema.MaxItems = &n
+			}
+		case "minimum", "maximum":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "minimum" {
+				schema.Minimum = &n
+			} else {
+				schema.Maximum = &n
+			}
+		default:
+			return false, fmt.Errorf("unknown schema tag key %q", key)
+		}
+	}
+	return required, nil
+}
+
+// typeKey names a struct the way gen_docs.go keys its comments
+func typeKey(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package protocol
+
+// SearchEvent requests a full-text search
+type SearchEvent struct {
+	Type      string `json:"type"`                                                      // "SEARCH"
+	Query     string `json:"query" schema:"required,minLength=1,maxLength=200"`         // Words to search for (all must match)
+	Scope     string `json:"scope,omitempty" schema:"enum=all|messages|posts|comments"` // "all" (default), "messages", "posts" or "comments"
+	Room      string `json:"room,omitempty" schema:"maxLength=50"`                      // Restrict message hits to a room; not allowed with scope posts or comments
+	Author    string `json:"author,omitempty" schema:"maxLength=50"`                    // Restrict hits to an author
+	From      string `json:"from,omitempty"`                                            // YYYY-MM-DD or RFC3339 lower bound
+	To        string `json:"to,omitempty"`                                              // YYYY-MM-DD or RFC3339 upper bound
+	Limit     int    `json:"limit,omitempty" schema:"minimum=0"`                        // Page size (default 20, max 50)
+	Offset    int    `json:"offset,omitempty" schema:"minimum=0,maximum=1000"`          // Results to skip (max 1000)
+	RequestID string `json:"request_id,omitempty"`                                      // Echoed back on SEARCH_RESULTS
+	User      string `json:"user"`                                                      // Searching username
+}
+
+// SearchResultsEvent is the reply to SEARCH, sent only to the requesting connection
+type SearchResultsEvent struct {
+	Type      string         `json:"type"`                 // "SEARCH_RESULTS"
+	Query     string         `json:"query"`                // Normalized query text
+	Scope     string         `json:"scope"`                // Scope that was searched
+	Results   []SearchResult `json:"results"`              // Hits, best first
+	Offset    int            `json:"offset"`               // Offset that was applied
+	RequestID string         `json:"request_id,omitempty"` // Copied from the request
+}
+
+// GetType returns the event type
+func (e *SearchEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *SearchEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *SearchResultsEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for search results
+func (e *SearchResultsEvent) GetUser() string { return "" }
@@ -0,0 +1,85 @@
+package protocol
+
+// SubscribePostEvent asks for live updates on a post without having to comment on it
+type SubscribePostEvent struct {
+	Type          string `json:"type"`                                                                         // "SUBSCRIBE_POST"
+	PostID        string `json:"post_id" schema:"required,minLength=1,maxLength=100,pattern=^[a-zA-Z0-9_-]+$"` // Post to follow
+	Snapshot      bool   `json:"snapshot,omitempty"`                                                           // Also send the most recent comments
+	SnapshotLimit int    `json:"snapshot_limit,omitempty" schema:"minimum=0"`                                  // Snapshot size (default 20, max 100)
+	User          string `json:"user"`                                                                         // Requesting username
+}
+
+// UnsubscribePostEvent stops live updates on a post
+type UnsubscribePostEvent struct {
+	Type   string `json:"type"`                                                                         // "UNSUBSCRIBE_POST"
+	PostID string `json:"post_id" schema:"required,minLength=1,maxLength=100,pattern=^[a-zA-Z0-9_-]+$"` // Post to stop following
+	User   string `json:"user"`                                                                         // Requesting username
+}
+
+// FeedEvent subscribes to or unsubscribes from the posts feed
+type FeedEvent struct {
+	Type string `json:"type"` // "SUBSCRIBE_FEED" or "UNSUBSCRIBE_FEED"
+	User string `json:"user"` // Requesting username
+}
+
+// CommentSnapshot is the newest page of a post's comments, oldest first
+type CommentSnapshot struct {
+	Comments   []*Comment `json:"comments"`              // Most recent comments
+	NextCursor string     `json:"next_cursor,omitempty"` // Use as FETCH_HISTORY before for older comments; set only when has_more
+	HasMore    bool       `json:"has_more"`              // Older comments exist
+}
+
+// PostSubscribedEvent confirms a post subscription, sent only to the subscriber
+type PostSubscribedEvent struct {
+	Type        string           `json:"type"`               // "POST_SUBSCRIBED"
+	PostID      string           `json:"post_id"`            // Post now followed
+	Subscribers int              `json:"subscribers"`        // Distinct users following the post
+	Snapshot    *CommentSnapshot `json:"snapshot,omitempty"` // Set when a snapshot was requested
+}
+
+// PostUnsubscribedEvent confirms a post unsubscription
+type PostUnsubscribedEvent struct {
+	Type   string `json:"type"`    // "POST_UNSUBSCRIBED"
+	PostID string `json:"post_id"` // Post no longer followed
+}
+
+// FeedSubscriptionEvent confirms a posts feed subscription change
+type FeedSubscriptionEvent struct {
+	Type string `json:"type"` // "FEED_SUBSCRIBED" or "FEED_UNSUBSCRIBED"
+}
+
+// GetType returns the event type
+func (e *SubscribePostEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *SubscribePostEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *UnsubscribePostEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *UnsubscribePostEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *FeedEvent) GetType() string { return e.Type }
+
+// GetUser returns the user
+func (e *FeedEvent) GetUser() string { return e.User }
+
+// GetType returns the event type
+func (e *PostSubscribedEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for confirmations
+func (e *PostSubscribedEvent) GetUser() string { return "" }
+
+// GetType returns the event type
+func (e *PostUnsubscribedEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for confirmations
+func (e *PostUnsubscribedEvent) GetUser() string { return "" }
+
+// GetType returns the event type
+func (e *FeedSubscriptionEvent) GetType() string { return e.Type }
+
+// GetUser returns empty string for confirmations
+func (e *FeedSubscriptionEvent) GetUser() string { return "" }
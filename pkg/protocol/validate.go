@@ -0,0 +1,178 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError reports the first field of a client event that doesn't match the
+// event's schema. Field is a path like "room" or "attachment_ids[2]", or empty
+// when the event as a whole is malformed.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// clientSchemas holds the schema of every client event type, built when the
+// package loads so a malformed schema tag fails at startup
+var clientSchemas = buildClientSchemas()
+
+func buildClientSchemas() map[string]*Schema {
+	schemas := make(map[string]*Schema)
+	builder := newSchemaBuilder("")
+	for _, spec := range Events {
+		if spec.Direction == ClientToServer {
+			schemas[spec.Type] = builder.event(spec)
+		}
+	}
+	return schemas
+}
+
+// Validate checks a raw client event against the schema of its type. Unknown
+// fields are allowed, and null counts as absent, as with encoding/json.
+func Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var event map[string]interface{}
+	if err := decoder.Decode(&event); err != nil || event == nil {
+		return &FieldError{Message: "event must be a JSON object"}
+	}
+
+	eventType, ok := event["type"].(string)
+	if !ok {
+		if event["type"] == nil {
+			return &FieldError{Field: "type", Message: "is required"}
+		}
+		return &FieldError{Field: "type", Message: "must be a string"}
+	}
+
+	schema, ok := clientSchemas[eventType]
+	if !ok {
+		if _, sent := Lookup(eventType, ServerToClient); sent {
+			return &FieldError{Field: "type", Message: fmt.Sprintf("%s is sent by the server, not by clients", eventType)}
+		}
+		return &FieldError{Field: "type", Message: fmt.Sprintf("unknown event type %q", eventType)}
+	}
+
+	return validateObject(schema, event, "")
+}
+
+func validateObject(schema *Schema, object map[string]interface{}, path string) error {
+	for _, property := range schema.Properties {
+		value := object[property.Name]
+		field := joinPath(path, property.Name)
+		if value == nil {
+			if schema.isRequired(property.Name) {
+				return &FieldError{Field: field, Message: "is required"}
+			}
+			continue
+		}
+		if err := validateValue(property.Schema, value, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateValue(schema *Schema, value interface{}, field string) error {
+	switch schema.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return &FieldError{Field: field, Message: "must be a string"}
+		}
+		return validateString(schema, s, field)
+
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return &FieldError{Field: field, Message: "must be an integer"}
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			return &FieldError{Field: field, Message: "must be an integer"}
+		}
+		if schema.Minimum != nil && i < *schema.Minimum {
+			return &FieldError{Field: field, Message: fmt.Sprintf("must be at least %d", *schema.Minimum)}
+		}
+		if schema.Maximum != nil && i > *schema.Maximum {
+			return &FieldError{Field: field, Message: fmt.Sprintf("must be at most %d", *schema.Maximum)}
+		}
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return &FieldError{Field: field, Message: "must be a number"}
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &FieldError{Field: field, Message: "must be a boolean"}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return &FieldError{Field: field, Message: "must be an array"}
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			return &FieldError{Field: field, Message: fmt.Sprintf("must have at most %d items", *schema.MaxItems)}
+		}
+		for i, item := range items {
+			if err := validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return &FieldError{Field: field, Message: "must be an object"}
+		}
+		return validateObject(schema, object, field)
+	}
+	return nil
+}
+
+func validateString(schema *Schema, s, field string) error {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			return &FieldError{Field: field, Message: "must not be empty"}
+		}
+		return &FieldError{Field: field, Message: fmt.Sprintf("must be at least %d characters", *schema.MinLength)}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return &FieldError{Field: field, Message: fmt.Sprintf("must be at most %d characters", *schema.MaxLength)}
+	}
+	if schema.pattern != nil && !schema.pattern.MatchString(s) {
+		return &FieldError{Field: field, Message: fmt.Sprintf("must match %s", schema.Pattern)}
+	}
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if s == allowed {
+				return nil
+			}
+		}
+		return &FieldError{Field: field, Message: "must be one of " + strings.Join(schema.Enum, ", ")}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -137,7 +137,7 @@ class ChatApp {
         if (message.type === 'join' || message.type === 'leave') {
             messageElement.className += ' system';
             messageElement.innerHTML = `
-                <div class="message-content">${message.content}</div>
+                <div class="message-content">${this.escapeHtml(message.content)}</div>
             `;
         } else {
             const isOwnMessage = message.username === this.username;
@@ -149,7 +149,7 @@ class ChatApp {
                 <div class="message-header">
                     ${isOwnMessage ? 'You' : message.username} • ${timestamp}
                 </div>
-                <div class="message-content">${this.escapeHtml(message.content)}</div>
+                <div class="message-content">${message.content_html || this.escapeHtml(message.content)}</div>
             `;
         }
 
@@ -266,7 +266,7 @@ class SimpleChatApp {
                 
             case 'CHAT_MESSAGE':
                 if (data.room === this.currentRoom) {
-                    this.addChatMessage(data.user, data.message);
+                    this.addChatMessage(data.user, data.message, data.content_html);
                 }
                 break;
                 
@@ -280,7 +280,7 @@ class SimpleChatApp {
         }
     }
     
-    addChatMessage(username, message) {
+    addChatMessage(username, message, contentHtml) {
         if (!this.elements.messagesDiv) return;
         
         const messageElement = document.createElement('div');
@@ -296,7 +296,7 @@ class SimpleChatApp {
                 <span class="username">${this.escapeHtml(username)}</span>
                 <span class="timestamp">${timestamp}</span>
             </div>
-            <div class="message-content">${this.escapeHtml(message)}</div>
+            <div class="message-content">${contentHtml || this.escapeHtml(message)}</div>
         `;
         
         this.elements.messagesDiv.appendChild(messageElement);
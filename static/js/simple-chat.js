@@ -233,7 +233,7 @@ class SimpleChatApp {
     handleChatMessage(event) {
         // Only show messages for current room
         if (event.room === this.currentRoom) {
-            this.addChatMessage(event.user, event.message);
+            this.addChatMessage(event.user, event.message, event.content_html);
         }
     }
 
@@ -247,7 +247,7 @@ class SimpleChatApp {
         this.addSystemMessage(`❌ Error: ${event.message}`);
     }
 
-    addChatMessage(username, message) {
+    addChatMessage(username, message, contentHtml) {
         const messageElement = document.createElement('div');
         messageElement.className = 'message';
         
@@ -262,7 +262,7 @@ class SimpleChatApp {
                 <span class="username">${this.escapeHtml(username)}</span>
                 <span class="timestamp">${timestamp}</span>
             </div>
-            <div class="message-content">${this.escapeHtml(message)}</div>
+            <div class="message-content">${contentHtml || this.escapeHtml(message)}</div>
         `;
         
         this.messagesDiv.appendChild(messageElement);